@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// framesPerBlock is the number of the frames processed while the engine is locked. It is short enough for the REST changes to be heard immediately.
+const framesPerBlock = 1024
+
+// minLevel is reported instead of -Inf dBFS, because JSON has no infinity.
+const minLevel = -120.0
+
+// errNotFound is returned when the band or the preset does not exist.
+var errNotFound = errors.New("not found")
+
+// engine applies the bands to the stream. The bands, the bypass and the meters are changed by the REST handlers while the stream is processed.
+type engine struct {
+	sampleRate float64
+
+	mu         sync.Mutex
+	bands      []equalizer.Band
+	equalizers []*equalizer.ParametricEQ
+	bypass     bool
+	peaks      []*equalizer.LevelMeter
+	rmss       []*equalizer.LevelMeter
+	frames     int64
+}
+
+// levels is the reading of the meters of the output.
+type levels struct {
+	// Peak and RMS are the current readings of each channel in dBFS, and MaxPeak is the peak hold.
+	Peak    []float64 `json:"peak"`
+	MaxPeak []float64 `json:"maxPeak"`
+	RMS     []float64 `json:"rms"`
+
+	// Frames is the number of the frames processed since the start.
+	Frames int64 `json:"frames"`
+}
+
+func newEngine(sampleRate float64, channels int, bands []equalizer.Band) *engine {
+	e := &engine{
+		sampleRate: sampleRate,
+		bands:      bands,
+		equalizers: make([]*equalizer.ParametricEQ, channels),
+		peaks:      make([]*equalizer.LevelMeter, channels),
+		rmss:       make([]*equalizer.LevelMeter, channels),
+	}
+
+	for i := range e.equalizers {
+		e.equalizers[i] = equalizer.NewParametricEQ(sampleRate, bands...)
+		e.peaks[i] = equalizer.NewPPMMeter(sampleRate)
+		e.rmss[i] = equalizer.NewVUMeter(sampleRate)
+	}
+
+	return e
+}
+
+// Bands returns the copy of the current bands.
+func (e *engine) Bands() []equalizer.Band {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]equalizer.Band(nil), e.bands...)
+}
+
+// SetBands replaces all bands. When the number of the bands and their types are not changed, the state variables are preserved.
+func (e *engine) SetBands(bands []equalizer.Band) error {
+	if err := checkBands(e.sampleRate, bands); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(bands) == len(e.bands) {
+		for i, band := range bands {
+			for _, eq := range e.equalizers {
+				eq.SetBand(i, band)
+			}
+		}
+	} else {
+		for i := range e.equalizers {
+			e.equalizers[i] = equalizer.NewParametricEQ(e.sampleRate, bands...)
+		}
+	}
+
+	e.bands = append([]equalizer.Band(nil), bands...)
+
+	return nil
+}
+
+// Band returns the i-th band.
+func (e *engine) Band(i int) (equalizer.Band, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if i < 0 || i >= len(e.bands) {
+		return equalizer.Band{}, errNotFound
+	}
+
+	return e.bands[i], nil
+}
+
+// SetBand replaces the i-th band.
+func (e *engine) SetBand(i int, band equalizer.Band) error {
+	if err := checkBands(e.sampleRate, []equalizer.Band{band}); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if i < 0 || i >= len(e.bands) {
+		return errNotFound
+	}
+
+	for _, eq := range e.equalizers {
+		eq.SetBand(i, band)
+	}
+
+	e.bands[i] = band
+
+	return nil
+}
+
+// Bypass returns true when the bands are bypassed.
+func (e *engine) Bypass() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.bypass
+}
+
+// SetBypass enables or disables the bands. The state variables are cleared when they are enabled again.
+func (e *engine) SetBypass(bypass bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.bypass && !bypass {
+		for _, eq := range e.equalizers {
+			eq.Reset()
+		}
+	}
+
+	e.bypass = bypass
+}
+
+// Levels returns the reading of the meters.
+func (e *engine) Levels() levels {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	l := levels{
+		Peak:    make([]float64, len(e.peaks)),
+		MaxPeak: make([]float64, len(e.peaks)),
+		RMS:     make([]float64, len(e.rmss)),
+		Frames:  e.frames,
+	}
+
+	for i := range e.peaks {
+		l.Peak[i] = math.Max(minLevel, e.peaks[i].Level())
+		l.MaxPeak[i] = math.Max(minLevel, e.peaks[i].Max())
+		l.RMS[i] = math.Max(minLevel, e.rmss[i].Level())
+	}
+
+	return l
+}
+
+// sampleWriter is implemented by wav.Writer and wav.RawWriter.
+type sampleWriter interface {
+	Write(samples []float64) error
+}
+
+// Run processes the stream until the reader returns io.EOF or the context is canceled. With realtime, the processing is paced at the sample rate,
+// so the file is processed like the live stream and the changes are heard at the right time.
+func (e *engine) Run(ctx context.Context, reader *wav.Reader, writer sampleWriter, realtime bool) error {
+	channels := len(e.equalizers)
+	buffer := make([]float64, framesPerBlock*channels)
+	start := time.Now()
+
+	for ctx.Err() == nil {
+		n, err := reader.Read(buffer)
+
+		if n > 0 {
+			e.process(buffer[:n])
+
+			if err := writer.Write(buffer[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if realtime {
+			e.mu.Lock()
+			elapsed := time.Duration(float64(e.frames) / e.sampleRate * float64(time.Second))
+			e.mu.Unlock()
+
+			time.Sleep(time.Until(start.Add(elapsed)))
+		}
+	}
+
+	return nil
+}
+
+// process applies the bands to the interleaved samples in place and measures the output.
+func (e *engine) process(samples []float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	channels := len(e.equalizers)
+
+	for i := range samples {
+		c := i % channels
+
+		if !e.bypass {
+			samples[i] = e.equalizers[c].Apply(samples[i])
+		}
+
+		e.peaks[c].Apply(samples[i])
+		e.rmss[c].Apply(samples[i])
+	}
+
+	e.frames += int64(len(samples) / channels)
+}
+
+// checkBands returns the error when the frequency of any band is not below the Nyquist frequency.
+func checkBands(sampleRate float64, bands []equalizer.Band) error {
+	for _, band := range bands {
+		if err := equalizer.CheckFrequency(sampleRate, band.Frequency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
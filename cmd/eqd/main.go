@@ -0,0 +1,228 @@
+// Command eqd is the equalizer daemon which processes the audio stream and exposes the REST endpoints to control it,
+// so the equalizer can be controlled from the web UI or the scripts while the audio is playing.
+//
+// Usage:
+//
+//	parec --format=s16le --rate=48000 --channels=2 | eqd --format s16le --rate 48000 --channels 2 --config chain.yaml | pacat --format=s16le --rate=48000 --channels=2
+//	eqd -i in.wav -o out.wav --realtime --presets ./presets --addr localhost:8080
+//
+// Without -i, the headerless PCM is read from the standard input in the format given by --format, --rate and --channels,
+// so the capture device is processed through the pipe, e.g. parec or arecord. Without -o, the headerless PCM is written
+// to the standard output in the same format as the input, e.g. to pacat or aplay. The filters are given by the same
+// flags and config file as the equalizer command.
+//
+// With --realtime, the file is processed at the speed of the sample rate, so the changes are applied at the right time.
+// The daemon exits when the stream ends or it is interrupted.
+//
+// The endpoints are:
+//
+//	GET  /bands          ... List the bands as [{"type":"peak","frequency":1000,"q":1,"gain":3}, ...]
+//	PUT  /bands          ... Replace all bands with the list.
+//	GET  /bands/{i}      ... Get the i-th band, counted from 0.
+//	PUT  /bands/{i}      ... Replace the i-th band. The state of the filter is preserved, so the change does not click.
+//	GET  /presets        ... List the names of the YAML or JSON config files in the --presets directory.
+//	POST /presets/{name} ... Replace all bands with the preset.
+//	GET  /meters         ... Get the peak, the peak hold and the RMS of each channel of the output in dBFS.
+//	GET  /bypass         ... Get the bypass as {"bypass":false}.
+//	PUT  /bypass         ... Enable or disable the bypass.
+//	GET  /ws             ... WebSocket which filters the chunks sent by the browser with its own copy of the bands. See package websocket.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "eqd: %v\n", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// run executes the command with the arguments except the program name.
+func run(args []string) error {
+	var (
+		input      string
+		output     string
+		name       string
+		sampleRate int
+		channels   int
+		addr       string
+		presets    string
+		realtime   bool
+		bands      []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("eqd", flag.ContinueOnError)
+	flags.StringVar(&input, "i", "", "input WAV `file`. The raw PCM is read from the standard input when it is omitted")
+	flags.StringVar(&output, "o", "", "output WAV `file`. The raw PCM is written to the standard output when it is omitted")
+	flags.StringVar(&name, "format", "s16le", "sample `format` of the raw input, one of "+strings.Join(config.RawFormatNames(), ", "))
+	flags.IntVar(&sampleRate, "rate", 48000, "sample `rate` of the raw input in Hz")
+	flags.IntVar(&channels, "channels", 2, "number of the interleaved channels of the raw input")
+	flags.StringVar(&addr, "addr", "localhost:8080", "`address` of the REST endpoints")
+	flags.StringVar(&presets, "presets", "", "`directory` of the preset config files")
+	flags.BoolVar(&realtime, "realtime", false, "process the input at the speed of the sample rate")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	reader, closeInput, err := openInput(input, name, sampleRate, channels)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeInput()
+
+	format := reader.Format
+
+	if err := checkBands(float64(format.SampleRate), bands); err != nil {
+		return err
+	}
+
+	writer, closeOutput, err := openOutput(output, format)
+
+	if err != nil {
+		return err
+	}
+
+	e := newEngine(float64(format.SampleRate), format.Channels, bands)
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		closeOutput()
+
+		return err
+	}
+
+	httpServer := &http.Server{Handler: &server{engine: e, presets: presets}}
+
+	go httpServer.Serve(listener)
+
+	fmt.Fprintf(os.Stderr, "eqd: listening on http://%s\n", listener.Addr())
+
+	// Stop at the interrupt, so the WAV header is completed.
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+
+	signal.Notify(interrupt, os.Interrupt)
+
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	runErr := e.Run(ctx, reader, writer, realtime)
+
+	httpServer.Shutdown(context.Background())
+
+	if err := closeOutput(); err != nil && runErr == nil {
+		runErr = err
+	}
+
+	return runErr
+}
+
+// openInput opens the WAV file, or the raw PCM from the standard input when the path is empty.
+func openInput(path, name string, sampleRate, channels int) (*wav.Reader, func() error, error) {
+	if path != "" {
+		file, err := os.Open(path)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reader, err := wav.NewReader(bufio.NewReader(file))
+
+		if err != nil {
+			file.Close()
+
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		return reader, file.Close, nil
+	}
+
+	format, ok := config.RawFormats[name]
+
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown format %q", name)
+	}
+
+	format.SampleRate = sampleRate
+	format.Channels = channels
+
+	reader, err := wav.NewRawReader(bufio.NewReader(os.Stdin), format)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reader, func() error { return nil }, nil
+}
+
+// openOutput creates the WAV file, or writes the raw PCM to the standard output when the path is empty.
+// The returned function completes the output.
+func openOutput(path string, format wav.Format) (sampleWriter, func() error, error) {
+	if path == "" {
+		out := bufio.NewWriter(os.Stdout)
+		writer, err := wav.NewRawWriter(out, format)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return writer, out.Flush, nil
+	}
+
+	file, err := os.Create(path)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	writer, err := wav.NewWriter(file, format)
+
+	if err != nil {
+		file.Close()
+
+		return nil, nil, err
+	}
+
+	return writer, func() error {
+		if err := writer.Close(); err != nil {
+			file.Close()
+
+			return err
+		}
+
+		return file.Close()
+	}, nil
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/websocket"
+)
+
+// presetExtensions are the extensions of the preset files in the order of the priority.
+var presetExtensions = []string{".yaml", ".yml", ".json"}
+
+// server serves the REST endpoints which control the engine.
+type server struct {
+	engine  *engine
+	presets string
+}
+
+// ServeHTTP routes the request. The paths are parsed by hand, because the path parameters of http.ServeMux are not available in Go 1.15.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "bands":
+		s.handleBands(w, r)
+	case len(parts) == 2 && parts[0] == "bands":
+		s.handleBand(w, r, parts[1])
+	case path == "presets":
+		s.handlePresets(w, r)
+	case len(parts) == 2 && parts[0] == "presets":
+		s.handlePreset(w, r, parts[1])
+	case path == "meters":
+		s.handleMeters(w, r)
+	case path == "bypass":
+		s.handleBypass(w, r)
+	case path == "ws":
+		// Each connection starts with the current bands and changes them independently of the stream.
+		websocket.NewHandler(s.engine.sampleRate, len(s.engine.equalizers), s.engine.Bands()...).ServeHTTP(w, r)
+	default:
+		writeError(w, http.StatusNotFound, errNotFound)
+	}
+}
+
+// handleBands gets or replaces all bands.
+func (s *server) handleBands(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, config.Filters(s.engine.Bands()))
+	case http.MethodPut:
+		var filters []config.Filter
+
+		if err := readJSON(r, &filters); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		bands, err := config.Bands(filters)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+		if err := s.engine.SetBands(bands); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		writeJSON(w, config.Filters(bands))
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+// handleBand gets or replaces the band at the index.
+func (s *server) handleBand(w http.ResponseWriter, r *http.Request, index string) {
+	i, err := strconv.Atoi(index)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		band, err := s.engine.Band(i)
+
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+
+			return
+		}
+
+		writeJSON(w, config.Filters([]equalizer.Band{band})[0])
+	case http.MethodPut:
+		var filter config.Filter
+
+		if err := readJSON(r, &filter); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		bands, err := config.Bands([]config.Filter{filter})
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+		if err := s.engine.SetBand(i, bands[0]); errors.Is(err, errNotFound) {
+			writeError(w, http.StatusNotFound, err)
+
+			return
+		} else if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		writeJSON(w, config.Filters(bands)[0])
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+// handlePresets lists the names of the presets.
+func (s *server) handlePresets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+
+		return
+	}
+
+	names := []string{}
+
+	if s.presets != "" {
+		entries, err := ioutil.ReadDir(s.presets)
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		seen := map[string]bool{}
+
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			name := strings.TrimSuffix(entry.Name(), ext)
+
+			if !entry.IsDir() && isPresetExtension(ext) && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	writeJSON(w, names)
+}
+
+// handlePreset loads the preset and replaces all bands with it.
+func (s *server) handlePreset(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+
+		return
+	}
+
+	path, err := s.presetPath(name)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+
+		return
+	}
+
+	bands, err := config.Load(path)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+	if err := s.engine.SetBands(bands); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	writeJSON(w, config.Filters(bands))
+}
+
+// presetPath returns the path of the preset file. The name must not contain the path separators.
+func (s *server) presetPath(name string) (string, error) {
+	if s.presets == "" || name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errNotFound
+	}
+
+	for _, ext := range presetExtensions {
+		path := filepath.Join(s.presets, name+ext)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", errNotFound
+}
+
+// handleMeters returns the levels of the output.
+func (s *server) handleMeters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+
+		return
+	}
+
+	writeJSON(w, s.engine.Levels())
+}
+
+// bypassState is the body of the bypass endpoint.
+type bypassState struct {
+	Bypass bool `json:"bypass"`
+}
+
+// handleBypass gets or sets the bypass.
+func (s *server) handleBypass(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, bypassState{Bypass: s.engine.Bypass()})
+	case http.MethodPut:
+		var state bypassState
+
+		if err := readJSON(r, &state); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		s.engine.SetBypass(state.Bypass)
+		writeJSON(w, state)
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+func isPresetExtension(ext string) bool {
+	for _, e := range presetExtensions {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readJSON decodes the request body. The unknown fields are reported instead of ignored.
+func readJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
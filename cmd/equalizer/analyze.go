@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/plot"
+)
+
+// runAnalyze writes the magnitude and the phase of the filters without processing the audio.
+func runAnalyze(args []string) error {
+	var (
+		sampleRate float64
+		plotPath   string
+		csvPath    string
+		points     int
+		bands      []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer analyze", flag.ContinueOnError)
+	flags.Float64Var(&sampleRate, "rate", 48000.0, "sample `rate` in Hz")
+	flags.StringVar(&plotPath, "plot", "", "write the plot to the PNG, SVG or text `file` chosen by the extension")
+	flags.StringVar(&csvPath, "csv", "", "write the frequency, the gain and the phase to the CSV `file`")
+	flags.IntVar(&points, "points", 200, "number of the frequencies written to the CSV file")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if len(bands) == 0 {
+		flags.Usage()
+
+		return errors.New("no filters are given")
+	}
+	if sampleRate <= 0.0 || points < 2 {
+		return errors.New("--rate must be positive and --points must be at least 2")
+	}
+
+	equalizers, err := newEqualizers(sampleRate, 1, bands)
+
+	if err != nil {
+		return err
+	}
+
+	eq := equalizers[0]
+	maxFrequency := math.Min(20000.0, 0.95*sampleRate/2.0)
+	options := plot.Options{
+		MinFrequency: 20.0,
+		MaxFrequency: maxFrequency,
+		Phase:        true,
+	}
+
+	// Show the chain in the terminal when no output is given.
+	if plotPath == "" && csvPath == "" {
+		options.Format = plot.Text
+
+		return plot.Response(os.Stdout, eq, options)
+	}
+	if plotPath != "" {
+		if options.Format, err = plotFormat(plotPath); err != nil {
+			return err
+		}
+		if err := writeFile(plotPath, func(w io.Writer) error {
+			return plot.Response(w, eq, options)
+		}); err != nil {
+			return err
+		}
+	}
+	if csvPath != "" {
+		frequencies := plot.Frequencies(options.MinFrequency, maxFrequency, points)
+
+		if err := writeFile(csvPath, func(w io.Writer) error {
+			return writeResponseCSV(w, eq, frequencies)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// plotFormat returns the plot format for the extension of the path.
+func plotFormat(path string) (plot.Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return plot.PNG, nil
+	case ".svg":
+		return plot.SVG, nil
+	case ".txt":
+		return plot.Text, nil
+	default:
+		return 0, fmt.Errorf("%s: the extension must be .png, .svg or .txt", path)
+	}
+}
+
+// writeResponseCSV writes the gain in dB and the phase in degrees at each frequency in Hz.
+func writeResponseCSV(w io.Writer, eq *equalizer.ParametricEQ, frequencies []float64) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "frequency,gain,phase")
+
+	magnitudes := plot.MagnitudeXYs(eq, frequencies)
+	phases := plot.PhaseXYs(eq, frequencies)
+
+	for i, frequency := range frequencies {
+		fmt.Fprintf(b, "%g,%g,%g\n", frequency, magnitudes[i].Y, phases[i].Y)
+	}
+
+	return b.Flush()
+}
+
+// writeFile creates the file and closes it after the write function returns.
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return file.Close()
+}
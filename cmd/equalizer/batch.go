@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// batchResult is the outcome of processing one file.
+type batchResult struct {
+	input   string
+	output  string
+	elapsed time.Duration
+	err     error
+}
+
+// runBatch applies the filters to every file which matches the glob pattern and writes the results to the output directory.
+func runBatch(args []string) error {
+	var (
+		pattern string
+		outDir  string
+		jobs    int
+		bands   []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer batch", flag.ContinueOnError)
+	flags.StringVar(&pattern, "glob", "", "glob `pattern` of the input WAV files")
+	flags.StringVar(&outDir, "o", "", "output `directory`")
+	flags.IntVar(&jobs, "jobs", runtime.NumCPU(), "number of the files processed concurrently")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if pattern == "" || outDir == "" {
+		flags.Usage()
+
+		return errors.New("--glob and -o are required")
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if jobs < 1 {
+		return fmt.Errorf("--jobs must be positive, got %d", jobs)
+	}
+
+	inputs, err := filepath.Glob(pattern)
+
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no files match %q", pattern)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	outputs, err := batchOutputs(inputs, outDir)
+
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	done := 0
+	failed := 0
+
+	for result := range processBatch(inputs, outputs, bands, jobs) {
+		done++
+
+		// The error of processFile already contains the file name.
+		if result.err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "[%d/%d] failed: %v\n", done, len(inputs), result.err)
+		} else {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s -> %s (%v)\n", done, len(inputs), result.input, result.output, result.elapsed.Round(time.Millisecond))
+		}
+	}
+
+	printSummary(os.Stderr, len(inputs), failed, time.Since(start))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(inputs))
+	}
+
+	return nil
+}
+
+// batchOutputs returns the output path for each input. The inputs must have distinct base names and must not be overwritten.
+func batchOutputs(inputs []string, outDir string) ([]string, error) {
+	outputs := make([]string, len(inputs))
+	seen := map[string]string{}
+
+	for i, input := range inputs {
+		output := filepath.Join(outDir, filepath.Base(input))
+
+		if previous, ok := seen[output]; ok {
+			return nil, fmt.Errorf("%s and %s are both written to %s", previous, input, output)
+		}
+		if same, err := samePath(input, output); err != nil {
+			return nil, err
+		} else if same {
+			return nil, fmt.Errorf("%s would be overwritten, choose the other output directory", input)
+		}
+
+		seen[output] = input
+		outputs[i] = output
+	}
+
+	return outputs, nil
+}
+
+// samePath reports whether the two paths refer to the same file. The second path may not exist yet.
+func samePath(a, b string) (bool, error) {
+	x, err := os.Stat(a)
+
+	if err != nil {
+		return false, err
+	}
+
+	y, err := os.Stat(b)
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return os.SameFile(x, y), nil
+}
+
+// processBatch processes the files with the given number of the workers. The results are sent in the order of completion.
+func processBatch(inputs, outputs []string, bands []equalizer.Band, jobs int) <-chan batchResult {
+	indices := make(chan int)
+	results := make(chan batchResult)
+
+	var wg sync.WaitGroup
+
+	for j := 0; j < jobs && j < len(inputs); j++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				start := time.Now()
+				err := processFile(inputs[i], outputs[i], bands)
+
+				results <- batchResult{
+					input:   inputs[i],
+					output:  outputs[i],
+					elapsed: time.Since(start),
+					err:     err,
+				}
+			}
+		}()
+	}
+	go func() {
+		for i := range inputs {
+			indices <- i
+		}
+
+		close(indices)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// printSummary writes the number of the processed and failed files.
+func printSummary(w io.Writer, total, failed int, elapsed time.Duration) {
+	fmt.Fprintf(w, "%d files processed, %d succeeded, %d failed in %v\n", total, total-failed, failed, elapsed.Round(time.Millisecond))
+}
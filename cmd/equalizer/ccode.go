@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// runCCode writes the C source which applies the filters, so the chain can be built into the firmware.
+func runCCode(args []string) error {
+	var (
+		output     string
+		name       string
+		sampleRate float64
+		channels   int
+		float32    bool
+		bands      []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer ccode", flag.ContinueOnError)
+	flags.StringVar(&output, "o", "", "write the source to the `file` instead of the standard output")
+	flags.StringVar(&name, "name", "equalizer", "`prefix` of the C functions and types")
+	flags.Float64Var(&sampleRate, "rate", 48000.0, "sample `rate` in Hz of the firmware")
+	flags.IntVar(&channels, "channels", 1, "number of the interleaved channels")
+	flags.BoolVar(&float32, "float32", false, "use float instead of double")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if len(bands) == 0 {
+		flags.Usage()
+
+		return errors.New("no filters are given")
+	}
+
+	equalizers, err := newEqualizers(sampleRate, 1, bands)
+
+	if err != nil {
+		return err
+	}
+
+	options := equalizer.CCodeOptions{
+		Name:     name,
+		Channels: channels,
+		Float32:  float32,
+	}
+
+	if output == "" {
+		return equalizer.WriteCCode(os.Stdout, equalizers[0], options)
+	}
+
+	return writeFile(output, func(w io.Writer) error {
+		return equalizer.WriteCCode(w, equalizers[0], options)
+	})
+}
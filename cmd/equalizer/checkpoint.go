@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// checkpoint is the progress of the interrupted run saved as JSON. The output file has exactly the frames when the
+// checkpoint is saved, and the state is the filter state of each channel after processing them.
+type checkpoint struct {
+	Input  string                    `json:"input"`
+	Output string                    `json:"output"`
+	Bands  []equalizer.Band          `json:"bands"`
+	Frames int64                     `json:"frames"`
+	State  [][]equalizer.FilterState `json:"state"`
+}
+
+// loadCheckpoint reads the checkpoint file. It returns nil without the error when the file does not exist.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c checkpoint
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// save writes the checkpoint to the temporary file and renames it, so the interruption never leaves the broken file.
+func (c *checkpoint) save(path string) error {
+	data, err := json.Marshal(c)
+
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// checkpointWriter writes the samples and saves the checkpoint at the interval.
+type checkpointWriter struct {
+	writer     *wav.Writer
+	file       *os.File
+	equalizers []*equalizer.ParametricEQ
+	checkpoint *checkpoint
+	path       string
+	interval   time.Duration
+	saved      time.Time
+}
+
+// Write writes the samples which the equalizers have already processed.
+func (w *checkpointWriter) Write(samples []float64) error {
+	if err := w.writer.Write(samples); err != nil {
+		return err
+	}
+
+	w.checkpoint.Frames += int64(len(samples) / len(w.equalizers))
+
+	if time.Since(w.saved) < w.interval {
+		return nil
+	}
+
+	// The samples must reach the disk before the checkpoint refers to them.
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	for i, eq := range w.equalizers {
+		w.checkpoint.State[i] = eq.State()
+	}
+
+	w.saved = time.Now()
+
+	return w.checkpoint.save(w.path)
+}
+
+// processFileResumable is the same as processFile except the progress is saved to the checkpoint file at the interval.
+// When the checkpoint file exists, the processing resumes from it. The checkpoint file is removed after the success.
+func processFileResumable(input, output string, bands []equalizer.Band, path string, interval time.Duration) error {
+	c, err := loadCheckpoint(path)
+
+	if err != nil {
+		return err
+	}
+	if c != nil && (c.Input != input || c.Output != output || !reflect.DeepEqual(c.Bands, bands)) {
+		return fmt.Errorf("%s was saved for the other input, output or filters, remove it to start over", path)
+	}
+
+	in, err := os.Open(input)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	reader, err := wav.NewReader(in)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	format := reader.Format
+	equalizers, err := newEqualizers(float64(format.SampleRate), format.Channels, bands)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	var (
+		out    *os.File
+		writer *wav.Writer
+	)
+
+	if c == nil {
+		c = &checkpoint{
+			Input:  input,
+			Output: output,
+			Bands:  bands,
+			State:  make([][]equalizer.FilterState, len(equalizers)),
+		}
+
+		if out, err = os.Create(output); err != nil {
+			return err
+		}
+
+		writer, err = wav.NewWriter(out, format)
+	} else {
+		if out, err = os.OpenFile(output, os.O_RDWR, 0); err != nil {
+			return err
+		}
+		if err := resume(c, reader, equalizers); err != nil {
+			out.Close()
+
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		writer, err = wav.ResumeWriter(out, format, c.Frames)
+	}
+	if err != nil {
+		out.Close()
+
+		return fmt.Errorf("%s: %w", output, err)
+	}
+
+	w := &checkpointWriter{
+		writer:     writer,
+		file:       out,
+		equalizers: equalizers,
+		checkpoint: c,
+		path:       path,
+		interval:   interval,
+		saved:      time.Now(),
+	}
+
+	if err := filter(reader, w, equalizers); err != nil {
+		out.Close()
+
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	if err := writer.Close(); err != nil {
+		out.Close()
+
+		return fmt.Errorf("%s: %w", output, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// The checkpoint is not saved when the run finishes within the interval.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// resume skips the processed frames of the input and restores the filter state.
+func resume(c *checkpoint, reader *wav.Reader, equalizers []*equalizer.ParametricEQ) error {
+	if len(c.State) != len(equalizers) {
+		return fmt.Errorf("checkpoint has %d channels but the input has %d", len(c.State), len(equalizers))
+	}
+	for i, eq := range equalizers {
+		if err := eq.SetState(c.State[i]); err != nil {
+			return err
+		}
+	}
+
+	return reader.Skip(c.Frames)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// runGoCode writes the Go source which has the coefficient table of the filters for each sample rate.
+func runGoCode(args []string) error {
+	var (
+		output      string
+		packageName string
+		name        string
+		rates       string
+		bands       []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer gocode", flag.ContinueOnError)
+	flags.StringVar(&output, "o", "", "write the source to the `file` instead of the standard output")
+	flags.StringVar(&packageName, "package", "filters", "`name` of the package of the source")
+	flags.StringVar(&name, "name", "Equalizer", "exported `identifier` of the table and the constructor")
+	flags.StringVar(&rates, "rates", "44100,48000", "comma separated sample `rates` in Hz for which the filters are designed")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if len(bands) == 0 {
+		flags.Usage()
+
+		return errors.New("no filters are given")
+	}
+
+	sampleRates, err := parseRates(rates)
+
+	if err != nil {
+		return err
+	}
+
+	// The first rate is the primary design. The filters are designed again for the other rates.
+	equalizers, err := newEqualizers(sampleRates[0], 1, bands)
+
+	if err != nil {
+		return err
+	}
+
+	options := equalizer.GoCodeOptions{
+		Package: packageName,
+		Name:    name,
+		Rates:   sampleRates[1:],
+	}
+
+	if output == "" {
+		return equalizer.WriteGoCode(os.Stdout, equalizers[0], options)
+	}
+
+	return writeFile(output, func(w io.Writer) error {
+		return equalizer.WriteGoCode(w, equalizers[0], options)
+	})
+}
@@ -0,0 +1,32 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/moutend/go-equalizer/pkg/pipeline"
+)
+
+// reloadOnHangup reloads the config on SIGHUP as the other daemons until the context is done.
+func reloadOnHangup(ctx context.Context, watcher *pipeline.Watcher) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				watcher.Reload()
+			}
+		}
+	}()
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"context"
+
+	"github.com/moutend/go-equalizer/pkg/pipeline"
+)
+
+// reloadOnHangup does nothing, because js has no signal.
+func reloadOnHangup(ctx context.Context, watcher *pipeline.Watcher) {}
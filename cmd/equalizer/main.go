@@ -0,0 +1,171 @@
+// Command equalizer applies the equalizer to the WAV file.
+//
+// Usage:
+//
+//	equalizer -i in.wav -o out.wav --highpass 80:0.707 --peak 2500:1.4:-3
+//	equalizer -i in.wav -o out.wav --config chain.yaml
+//	equalizer -i long.wav -o out.wav --config chain.yaml --checkpoint out.checkpoint
+//	equalizer batch --glob "stems/*.wav" -o out --config chain.yaml --jobs 8
+//	equalizer analyze --config chain.yaml --plot response.png --csv response.csv
+//	ffmpeg -i in.mp3 -f s16le -ar 48000 -ac 2 - | equalizer pipe --format s16le --rate 48000 --channels 2 --peak 2500:1.4:-3 > out.raw
+//	equalizer pipewire --config chain.yaml -o ~/.config/pipewire/pipewire.conf.d/equalizer.conf
+//	equalizer proxy --upstream http://radio.example.com/stream --ffmpeg ffmpeg --codec mp3 --config chain.yaml
+//	parec --format=s16le | equalizer pipeline --watch 1s pipeline.yaml | pacat --format=s16le
+//	equalizer quantize --config chain.yaml --rate 48000 --bits 16 --integer-bits 1
+//	equalizer ccode --config chain.yaml --rate 48000 --channels 2 --float32 -o equalizer.c
+//	equalizer gocode --config chain.yaml --package presets --name Loudness --rates 44100,48000 -o loudness_gen.go
+//
+// The filters are applied in the order of the flags. The following filter flags are available:
+//
+//	--lowpass, --highpass, --allpass, --bandpass, --notch ... frequency[:q]
+//	--lowshelf, --highshelf, --peak ... frequency:q:gain
+//
+// The frequency is in Hz and the gain is in dB. The Q value defaults to 0.707 when it is omitted.
+// For the band-pass, notch and peaking filters, q is the band width in octaves as same as the equalizer package.
+//
+// The config file lists the filters in the order they are applied. The type is one of the filter flag names without the dashes.
+//
+//	filters:
+//	  - type: highpass
+//	    frequency: 80
+//	    q: 0.707
+//	  - type: peak
+//	    frequency: 2500
+//	    q: 1.4
+//	    gain: -3
+//
+// When both the config file and the filter flags are given, the filters in the config file are applied first.
+//
+// With --checkpoint, the number of the written frames and the filter state are saved to the file every
+// --checkpoint-interval. When the run is interrupted, run the same command again to resume from the checkpoint.
+// The checkpoint file is removed after the output is completed.
+//
+// The batch subcommand processes the files which match the glob pattern in parallel and writes them to the output
+// directory with the same base names. The progress of each file and the summary are written to the standard error.
+//
+// The analyze subcommand renders the magnitude and the phase of the filters at the sample rate given by --rate without
+// processing the audio. The plot format is chosen by the extension, .png, .svg or .txt, and the CSV file has the columns
+// of the frequency in Hz, the gain in dB and the phase in degrees. The text plot is written to the standard output when
+// neither --plot nor --csv is given.
+//
+// The pipe subcommand reads the headerless PCM from the standard input and writes the filtered PCM in the same format
+// to the standard output. The format is one of u8, s16le, s24le, s32le, f32le and f64le as same as ffmpeg.
+//
+// The pipewire subcommand writes the configuration of the PipeWire filter-chain module which creates the virtual sink
+// applying the filters. The filters are designed for each of --rates, so the response is kept at any rate of the server.
+// Restart PipeWire after writing it to ~/.config/pipewire/pipewire.conf.d/ and choose the sink in the sound settings.
+//
+// The proxy subcommand pulls the HTTP or Icecast stream given by --upstream, applies the filters and serves the filtered
+// stream on --addr, so the internet radio can be relayed with the tonal correction. ffmpeg given by --ffmpeg decodes the
+// compressed stream and encodes the output to --codec, mp3 or aac. Without ffmpeg, the upstream must be the WAV stream and
+// the output is the WAV stream. The listeners join from the middle of the stream, and the one which falls behind is
+// disconnected. The proxy exits when the upstream ends, so run it under the supervisor which restarts it.
+//
+// The pipeline subcommand processes the standard input by the pipeline config file, which describes the input, the stages
+// and the output, and writes the result to the standard output. See package pipeline for the format. With --watch, the
+// file is reloaded on change and the new pipeline is crossfaded in. SIGHUP also reloads it. The input and the output
+// cannot be changed by the reload.
+//
+// The quantize subcommand rounds the coefficients of each band to the fixed point format of --bits and --integer-bits,
+// or to float32 with --float32, and reports the largest deviation of the magnitude response and the stability margin, so
+// the chain can be checked before it is loaded to the DSP. It fails when the quantized filters are unstable.
+//
+// The ccode subcommand writes the self-contained C source which has the coefficient table of the filters designed at
+// --rate and the function to filter the interleaved samples in place, so the chain can be built into the firmware.
+//
+// The gocode subcommand writes the Go source which has the coefficient table of the filters designed for each of --rates
+// and the constructor of the chain, so the application embeds the fixed equalizer without designing it at the startup.
+// Use it with go generate.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "equalizer: %v\n", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// run executes the command with the arguments except the program name.
+func run(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "batch":
+			return runBatch(args[1:])
+		case "analyze":
+			return runAnalyze(args[1:])
+		case "pipe":
+			return runPipe(args[1:])
+		case "pipewire":
+			return runPipeWire(args[1:])
+		case "proxy":
+			return runProxy(args[1:])
+		case "pipeline":
+			return runPipeline(args[1:])
+		case "quantize":
+			return runQuantize(args[1:])
+		case "ccode":
+			return runCCode(args[1:])
+		case "gocode":
+			return runGoCode(args[1:])
+		}
+	}
+
+	return runApply(args)
+}
+
+// runApply applies the filters given by the flags to the input file.
+func runApply(args []string) error {
+	var (
+		input          string
+		output         string
+		checkpointPath string
+		interval       time.Duration
+		bands          []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer", flag.ContinueOnError)
+	flags.StringVar(&input, "i", "", "input WAV `file`")
+	flags.StringVar(&output, "o", "", "output WAV `file`")
+	flags.StringVar(&checkpointPath, "checkpoint", "", "save the progress to the `file` and resume from it after the interruption")
+	flags.DurationVar(&interval, "checkpoint-interval", time.Minute, "interval of saving the checkpoint")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if input == "" || output == "" {
+		flags.Usage()
+
+		return errors.New("-i and -o are required")
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if checkpointPath != "" {
+		return processFileResumable(input, output, bands, checkpointPath, interval)
+	}
+
+	return processFile(input, output, bands)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// runPipe reads the raw PCM from the standard input and writes the filtered PCM in the same format to the standard output.
+func runPipe(args []string) error {
+	var (
+		name       string
+		sampleRate int
+		channels   int
+		bands      []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer pipe", flag.ContinueOnError)
+	flags.StringVar(&name, "format", "s16le", "sample `format`, one of "+strings.Join(config.RawFormatNames(), ", "))
+	flags.IntVar(&sampleRate, "rate", 48000, "sample `rate` in Hz")
+	flags.IntVar(&channels, "channels", 2, "number of the interleaved channels")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	format, ok := config.RawFormats[name]
+
+	if !ok {
+		return fmt.Errorf("unknown format %q", name)
+	}
+
+	format.SampleRate = sampleRate
+	format.Channels = channels
+
+	reader, err := wav.NewRawReader(bufio.NewReader(os.Stdin), format)
+
+	if err != nil {
+		return err
+	}
+
+	equalizers, err := newEqualizers(float64(sampleRate), channels, bands)
+
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	writer, err := wav.NewRawWriter(out, format)
+
+	if err != nil {
+		return err
+	}
+	if err := filter(reader, writer, equalizers); err != nil {
+		return err
+	}
+
+	return out.Flush()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/moutend/go-equalizer/pkg/pipeline"
+)
+
+// runPipeline processes the standard input by the pipeline config file and writes the result to the standard output.
+func runPipeline(args []string) error {
+	var (
+		watch     time.Duration
+		crossfade time.Duration
+	)
+
+	flags := flag.NewFlagSet("equalizer pipeline", flag.ContinueOnError)
+	flags.DurationVar(&watch, "watch", 0, "check the config file at the `interval` and reload it on change. 0 disables it")
+	flags.DurationVar(&crossfade, "crossfade", 50*time.Millisecond, "`duration` of the crossfade to the reloaded pipeline")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return fmt.Errorf("one config file is required")
+	}
+
+	watcher, err := pipeline.NewWatcher(flags.Arg(0), watch, crossfade)
+
+	if err != nil {
+		return err
+	}
+
+	watcher.OnReload = func(err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "equalizer: reload: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "equalizer: reloaded %s\n", flags.Arg(0))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if watch > 0 {
+		go watcher.Watch(ctx)
+	}
+
+	reloadOnHangup(ctx, watcher)
+
+	// The standard output is not buffered, so the WAV header is completed when it is redirected to the file.
+	return watcher.Run(bufio.NewReader(os.Stdin), os.Stdout)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// defaultPipeWireRates are the sample rates at which PipeWire commonly runs.
+const defaultPipeWireRates = "44100,48000,88200,96000,192000"
+
+// runPipeWire writes the PipeWire filter-chain configuration which creates the virtual sink applying the filters.
+func runPipeWire(args []string) error {
+	var (
+		output      string
+		name        string
+		description string
+		channels    int
+		rates       string
+		bands       []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer pipewire", flag.ContinueOnError)
+	flags.StringVar(&output, "o", "", "write the configuration to the `file` instead of the standard output")
+	flags.StringVar(&name, "name", "equalizer", "node `name` of the virtual sink")
+	flags.StringVar(&description, "description", "Equalizer Sink", "`description` shown in the sound settings")
+	flags.IntVar(&channels, "channels", 2, "number of the channels of the virtual sink")
+	flags.StringVar(&rates, "rates", defaultPipeWireRates, "comma separated sample `rates` in Hz for which the filters are designed")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if len(bands) == 0 {
+		flags.Usage()
+
+		return errors.New("no filters are given")
+	}
+
+	sampleRates, err := parseRates(rates)
+
+	if err != nil {
+		return err
+	}
+
+	// The first rate is the primary design. The filters are designed again for the other rates.
+	equalizers, err := newEqualizers(sampleRates[0], 1, bands)
+
+	if err != nil {
+		return err
+	}
+
+	options := equalizer.PipeWireOptions{
+		Name:        name,
+		Description: description,
+		Channels:    channels,
+		Rates:       sampleRates[1:],
+	}
+
+	if output == "" {
+		return equalizer.WritePipeWireConfig(os.Stdout, equalizers[0], options)
+	}
+
+	return writeFile(output, func(w io.Writer) error {
+		return equalizer.WritePipeWireConfig(w, equalizers[0], options)
+	})
+}
+
+// parseRates parses the comma separated sample rates.
+func parseRates(value string) ([]float64, error) {
+	var rates []float64
+
+	for _, field := range strings.Split(value, ",") {
+		rate, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+
+		if err != nil || rate <= 0.0 {
+			return nil, fmt.Errorf("invalid sample rate %q", field)
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// framesPerBlock is the number of the frames processed at once.
+const framesPerBlock = 4096
+
+// newEqualizers returns the equalizer for each channel.
+func newEqualizers(sampleRate float64, channels int, bands []equalizer.Band) ([]*equalizer.ParametricEQ, error) {
+	for _, band := range bands {
+		if err := equalizer.CheckFrequency(sampleRate, band.Frequency); err != nil {
+			return nil, err
+		}
+	}
+
+	equalizers := make([]*equalizer.ParametricEQ, channels)
+
+	for i := range equalizers {
+		equalizers[i] = equalizer.NewParametricEQ(sampleRate, bands...)
+	}
+
+	return equalizers, nil
+}
+
+// process applies the bands to each channel of the WAV stream and writes the result in the same format.
+func process(r io.Reader, w io.WriteSeeker, bands []equalizer.Band) error {
+	reader, err := wav.NewReader(r)
+
+	if err != nil {
+		return err
+	}
+
+	format := reader.Format
+	equalizers, err := newEqualizers(float64(format.SampleRate), format.Channels, bands)
+
+	if err != nil {
+		return err
+	}
+
+	writer, err := wav.NewWriter(w, format)
+
+	if err != nil {
+		return err
+	}
+	if err := filter(reader, writer, equalizers); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// sampleWriter is implemented by wav.Writer and wav.RawWriter.
+type sampleWriter interface {
+	Write(samples []float64) error
+}
+
+// filter applies the equalizers to the interleaved samples until the reader returns io.EOF.
+func filter(reader *wav.Reader, writer sampleWriter, equalizers []*equalizer.ParametricEQ) error {
+	channels := len(equalizers)
+	buffer := make([]float64, framesPerBlock*channels)
+
+	for {
+		n, err := reader.Read(buffer)
+
+		for i := 0; i < n; i++ {
+			buffer[i] = equalizers[i%channels].Apply(buffer[i])
+		}
+		if n > 0 {
+			if err := writer.Write(buffer[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// processFile applies the bands to the input WAV file and writes the output WAV file.
+func processFile(input, output string, bands []equalizer.Band) error {
+	in, err := os.Open(input)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(output)
+
+	if err != nil {
+		return err
+	}
+	if err := process(in, out, bands); err != nil {
+		out.Close()
+
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	return out.Close()
+}
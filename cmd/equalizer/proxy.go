@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// listenerBuffer is the number of the chunks queued for each listener. The listener which falls behind by more is disconnected.
+const listenerBuffer = 256
+
+// proxyCodec is the output codec of the proxy.
+type proxyCodec struct {
+	// format is the muxer of ffmpeg. The empty format is the WAV stream encoded without ffmpeg.
+	format      string
+	contentType string
+}
+
+// proxyCodecs are the codecs which the listeners can decode from the middle of the stream, so the late listeners can join.
+var proxyCodecs = map[string]proxyCodec{
+	"wav": {"", "audio/wav"},
+	"mp3": {"mp3", "audio/mpeg"},
+	"aac": {"adts", "audio/aac"},
+}
+
+// runProxy pulls the HTTP or Icecast stream, applies the filters and serves the filtered stream to the listeners.
+func runProxy(args []string) error {
+	var (
+		upstream   string
+		addr       string
+		ffmpegPath string
+		codec      string
+		bitrate    string
+		sampleRate int
+		channels   int
+		bands      []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer proxy", flag.ContinueOnError)
+	flags.StringVar(&upstream, "upstream", "", "`URL` of the HTTP or Icecast stream")
+	flags.StringVar(&addr, "addr", "localhost:8000", "`address` on which the filtered stream is served")
+	flags.StringVar(&ffmpegPath, "ffmpeg", "", "`path` of ffmpeg which decodes and encodes the compressed streams. Only WAV is supported without it")
+	flags.StringVar(&codec, "codec", "wav", "output `codec`, one of "+strings.Join(proxyCodecNames(), ", "))
+	flags.StringVar(&bitrate, "bitrate", "192k", "`bitrate` of the compressed output")
+	flags.IntVar(&sampleRate, "rate", 44100, "sample `rate` in Hz to which ffmpeg decodes the stream")
+	flags.IntVar(&channels, "channels", 2, "number of the channels to which ffmpeg decodes the stream")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if upstream == "" {
+		flags.Usage()
+
+		return errors.New("--upstream is required")
+	}
+
+	output, ok := proxyCodecs[codec]
+
+	if !ok {
+		return fmt.Errorf("unknown codec %q", codec)
+	}
+	if output.format != "" && ffmpegPath == "" {
+		return fmt.Errorf("--ffmpeg is required to encode %s", codec)
+	}
+
+	response, err := http.Get(upstream)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("upstream: %s", response.Status)
+	}
+
+	var reader *wav.Reader
+
+	if ffmpegPath == "" {
+		if reader, err = wav.NewReader(bufio.NewReader(response.Body)); err != nil {
+			return fmt.Errorf("upstream: %w (use --ffmpeg for the compressed streams)", err)
+		}
+	} else {
+		decoder := exec.Command(ffmpegPath, "-hide_banner", "-loglevel", "error", "-i", "pipe:0",
+			"-f", "s16le", "-ar", strconv.Itoa(sampleRate), "-ac", strconv.Itoa(channels), "pipe:1")
+		decoder.Stdin = response.Body
+		decoder.Stderr = os.Stderr
+
+		stdout, err := decoder.StdoutPipe()
+
+		if err != nil {
+			return err
+		}
+		if err := decoder.Start(); err != nil {
+			return err
+		}
+
+		defer decoder.Wait()
+		defer decoder.Process.Kill()
+
+		if reader, err = wav.NewRawReader(bufio.NewReader(stdout), wav.Format{SampleRate: sampleRate, Channels: channels, BitsPerSample: 16}); err != nil {
+			return err
+		}
+	}
+
+	format := reader.Format
+	equalizers, err := newEqualizers(float64(format.SampleRate), format.Channels, bands)
+
+	if err != nil {
+		return err
+	}
+
+	h := &hub{
+		contentType: output.contentType,
+		name:        response.Header.Get("Icy-Name"),
+		listeners:   map[chan []byte]struct{}{},
+	}
+
+	defer h.Close()
+
+	// The filtered PCM is written to the hub directly as WAV, or through the encoder.
+	var pcmWriter io.Writer = h
+
+	if output.format == "" {
+		if h.header, err = wav.StreamHeader(format); err != nil {
+			return err
+		}
+	} else {
+		encoder := exec.Command(ffmpegPath, "-hide_banner", "-loglevel", "error",
+			"-f", "s16le", "-ar", strconv.Itoa(format.SampleRate), "-ac", strconv.Itoa(format.Channels), "-i", "pipe:0",
+			"-f", output.format, "-b:a", bitrate, "pipe:1")
+		encoder.Stdout = h
+		encoder.Stderr = os.Stderr
+
+		stdin, err := encoder.StdinPipe()
+
+		if err != nil {
+			return err
+		}
+		if err := encoder.Start(); err != nil {
+			return err
+		}
+
+		defer encoder.Wait()
+		defer stdin.Close()
+
+		pcmWriter = stdin
+		format.BitsPerSample = 16
+		format.Float = false
+	}
+
+	writer, err := wav.NewRawWriter(pcmWriter, format)
+
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: h}
+
+	go server.Serve(listener)
+
+	defer server.Close()
+
+	fmt.Fprintf(os.Stderr, "equalizer: serving %s on http://%s\n", upstream, listener.Addr())
+
+	if err := filter(reader, writer, equalizers); err != nil {
+		return err
+	}
+
+	return errors.New("upstream: stream ended")
+}
+
+// proxyCodecNames returns the sorted names of the output codecs for the usage.
+func proxyCodecNames() []string {
+	names := make([]string, 0, len(proxyCodecs))
+
+	for name := range proxyCodecs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// hub broadcasts the encoded stream to the listeners. The slow listener is disconnected instead of delaying the others.
+type hub struct {
+	contentType string
+	name        string
+
+	// header is written to each listener before the stream, e.g. the WAV header.
+	header []byte
+
+	mu        sync.Mutex
+	listeners map[chan []byte]struct{}
+	closed    bool
+}
+
+// Write sends the copy of the chunk to every listener.
+func (h *hub) Write(p []byte) (int, error) {
+	chunk := append([]byte(nil), p...)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for listener := range h.listeners {
+		select {
+		case listener <- chunk:
+		default:
+			delete(h.listeners, listener)
+			close(listener)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close disconnects all listeners.
+func (h *hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for listener := range h.listeners {
+		delete(h.listeners, listener)
+		close(listener)
+	}
+
+	h.closed = true
+
+	return nil
+}
+
+// ServeHTTP streams the chunks to the listener from the time it connects.
+func (h *hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", h.contentType)
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+
+	if h.name != "" {
+		w.Header().Set("Icy-Name", h.name)
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	listener := make(chan []byte, listenerBuffer)
+
+	h.mu.Lock()
+
+	if h.closed {
+		h.mu.Unlock()
+		http.Error(w, "stream ended", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	h.listeners[listener] = struct{}{}
+	h.mu.Unlock()
+
+	defer h.remove(listener)
+
+	flusher, _ := w.(http.Flusher)
+
+	if _, err := w.Write(h.header); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case chunk, ok := <-listener:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// remove unsubscribes the listener which has disconnected.
+func (h *hub) remove(listener chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.listeners[listener]; ok {
+		delete(h.listeners, listener)
+		close(listener)
+	}
+}
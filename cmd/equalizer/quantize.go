@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// runQuantize reports the deviation of the response and the stability margin of each band with the quantized coefficients.
+func runQuantize(args []string) error {
+	var (
+		sampleRate  float64
+		bits        int
+		integerBits int
+		float32     bool
+		bands       []equalizer.Band
+	)
+
+	flags := flag.NewFlagSet("equalizer quantize", flag.ContinueOnError)
+	flags.Float64Var(&sampleRate, "rate", 48000.0, "sample `rate` in Hz")
+	flags.IntVar(&bits, "bits", 16, "word length of the fixed point coefficients including the sign bit")
+	flags.IntVar(&integerBits, "integer-bits", 1, "integer bits of the fixed point coefficients excluding the sign bit")
+	flags.BoolVar(&float32, "float32", false, "round the coefficients to float32 instead of the fixed point")
+	configPath := config.AddFlag(flags)
+	config.AddBandFlags(flags, &bands)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	bands, err := config.With(*configPath, bands)
+
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if len(bands) == 0 {
+		flags.Usage()
+
+		return errors.New("no filters are given")
+	}
+	if sampleRate <= 0.0 {
+		return errors.New("--rate must be positive")
+	}
+	if !float32 && (integerBits < 0 || bits < integerBits+2 || bits > 64) {
+		return errors.New("--bits must be between --integer-bits+2 and 64")
+	}
+
+	equalizers, err := newEqualizers(sampleRate, 1, bands)
+
+	if err != nil {
+		return err
+	}
+
+	q := equalizer.Quantization{Bits: bits, IntegerBits: integerBits, Float32: float32}
+	filters := equalizers[0].Filters()
+
+	fmt.Printf("%s at %g Hz\n", q, sampleRate)
+
+	for i, band := range bands {
+		writeQuantizationReport(os.Stdout, fmt.Sprintf("%d %s %g Hz", i+1, config.TypeName(band.Name), band.Frequency), equalizer.AnalyzeQuantization(q, filters[i]))
+	}
+
+	report := equalizer.AnalyzeQuantization(q, filters...)
+
+	writeQuantizationReport(os.Stdout, "total", report)
+
+	if report.Margin <= 0.0 {
+		return errors.New("the quantized filters are unstable, use more bits")
+	}
+
+	return nil
+}
+
+// writeQuantizationReport writes the report in one line.
+func writeQuantizationReport(w io.Writer, name string, report equalizer.QuantizationReport) {
+	fmt.Fprintf(w, "%s: max deviation %.3g dB at %.4g Hz, pole radius %.6f (margin %.3g)", name, report.MaxDeviation, report.DeviationFrequency, report.PoleRadius, report.Margin)
+
+	if report.Saturated {
+		fmt.Fprint(w, ", saturated")
+	}
+
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,264 @@
+// Command wasm exposes the parametric equalizer to JavaScript, so the same filters run in the browser as on the server.
+//
+// Build it with the wasm_exec.js of the same Go version, which is in misc/wasm before Go 1.24:
+//
+//	GOOS=js GOARCH=wasm go build -o equalizer.wasm ./cmd/wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// After the module is started, the global goEqualizer creates the equalizer of one channel:
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("equalizer.wasm"), go.importObject);
+//	go.run(instance);
+//
+//	const eq = goEqualizer.create(48000, [{ type: "peak", frequency: 1000, q: 1, gain: 6 }]);
+//	eq.process(samples);                      // Float32Array, filtered in place
+//	eq.setBand(0, { type: "peak", frequency: 2000, q: 1, gain: -3 });
+//	eq.setBands([{ type: "lowpass", frequency: 8000 }]);
+//	eq.response([100, 1000, 10000]);          // gains in dB
+//	eq.reset();
+//	eq.release();
+//
+// The band is the same as the filter of the config file of the equalizer command. The methods return the Error instead of
+// throwing it when the arguments are invalid, because the Go functions can not throw. Call release when the equalizer is
+// no longer used, because the functions of the object keep it alive.
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"syscall/js"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+func main() {
+	js.Global().Set("goEqualizer", js.ValueOf(map[string]interface{}{
+		"create": js.FuncOf(create),
+	}))
+
+	// The functions are called while main is running.
+	select {}
+}
+
+// create returns the equalizer object. The arguments are the sample rate and the array of the bands.
+func create(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 || args[0].Type() != js.TypeNumber {
+		return jsError(errors.New("equalizer: the sample rate is required"))
+	}
+
+	sampleRate := args[0].Float()
+
+	if sampleRate <= 0.0 {
+		return jsError(fmt.Errorf("equalizer: invalid sample rate %v", sampleRate))
+	}
+
+	var bands []equalizer.Band
+
+	if len(args) > 1 {
+		var err error
+
+		if bands, err = parseBands(sampleRate, args[1]); err != nil {
+			return jsError(err)
+		}
+	}
+
+	e := &binding{eq: equalizer.NewParametricEQ(sampleRate, bands...)}
+
+	return e.object()
+}
+
+// binding holds the equalizer and the functions exposed to JavaScript.
+type binding struct {
+	eq     *equalizer.ParametricEQ
+	buffer []byte
+	funcs  []js.Func
+}
+
+// object returns the JavaScript object whose methods call the equalizer.
+func (b *binding) object() js.Value {
+	methods := map[string]func(args []js.Value) interface{}{
+		"process":  b.process,
+		"setBand":  b.setBand,
+		"setBands": b.setBands,
+		"response": b.response,
+		"reset":    b.reset,
+		"bands":    b.bands,
+	}
+
+	object := map[string]interface{}{}
+
+	for name, method := range methods {
+		method := method
+		f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return method(args)
+		})
+
+		b.funcs = append(b.funcs, f)
+		object[name] = f
+	}
+
+	release := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		return b.release()
+	})
+
+	b.funcs = append(b.funcs, release)
+	object["release"] = release
+
+	return js.ValueOf(object)
+}
+
+// process filters the Float32Array in place.
+func (b *binding) process(args []js.Value) interface{} {
+	if len(args) < 1 || !args[0].InstanceOf(js.Global().Get("Float32Array")) {
+		return jsError(errors.New("equalizer: process takes Float32Array"))
+	}
+
+	// js.CopyBytesToGo accepts only Uint8Array, so the samples are copied through the view of the same buffer.
+	samples := args[0]
+	view := js.Global().Get("Uint8Array").New(samples.Get("buffer"), samples.Get("byteOffset"), samples.Get("byteLength"))
+	size := view.Length()
+
+	if cap(b.buffer) < size {
+		b.buffer = make([]byte, size)
+	}
+
+	buffer := b.buffer[:size]
+	js.CopyBytesToGo(buffer, view)
+
+	for i := 0; i+4 <= size; i += 4 {
+		value := float64(math.Float32frombits(binary.LittleEndian.Uint32(buffer[i:])))
+		binary.LittleEndian.PutUint32(buffer[i:], math.Float32bits(float32(b.eq.Apply(value))))
+	}
+
+	js.CopyBytesToJS(view, buffer)
+
+	return nil
+}
+
+// setBand replaces the band at the index. The state of the filter is preserved, so the change does not click.
+func (b *binding) setBand(args []js.Value) interface{} {
+	if len(args) < 2 || args[0].Type() != js.TypeNumber {
+		return jsError(errors.New("equalizer: setBand takes the index and the band"))
+	}
+
+	i := args[0].Int()
+
+	if i < 0 || i >= len(b.eq.Bands()) {
+		return jsError(fmt.Errorf("equalizer: band %d does not exist", i))
+	}
+
+	array := js.Global().Get("Array").Call("of", args[1])
+	bands, err := parseBands(b.eq.SampleRate(), array)
+
+	if err != nil {
+		return jsError(err)
+	}
+
+	b.eq.SetBand(i, bands[0])
+
+	return nil
+}
+
+// setBands replaces all bands. The state of the filters is cleared when the number of the bands is changed.
+func (b *binding) setBands(args []js.Value) interface{} {
+	if len(args) < 1 {
+		return jsError(errors.New("equalizer: setBands takes the array of the bands"))
+	}
+
+	bands, err := parseBands(b.eq.SampleRate(), args[0])
+
+	if err != nil {
+		return jsError(err)
+	}
+
+	if len(bands) == len(b.eq.Bands()) {
+		for i, band := range bands {
+			b.eq.SetBand(i, band)
+		}
+	} else {
+		b.eq = equalizer.NewParametricEQ(b.eq.SampleRate(), bands...)
+	}
+
+	return nil
+}
+
+// response returns the gains in dB at the frequencies.
+func (b *binding) response(args []js.Value) interface{} {
+	if len(args) < 1 {
+		return jsError(errors.New("equalizer: response takes the array of the frequencies"))
+	}
+
+	frequencies := make([]float64, args[0].Length())
+
+	for i := range frequencies {
+		frequencies[i] = args[0].Index(i).Float()
+	}
+
+	gains := make([]interface{}, len(frequencies))
+
+	for i, point := range b.eq.Response(frequencies) {
+		gains[i] = point.Gain
+	}
+
+	return js.ValueOf(gains)
+}
+
+// reset clears the state of the filters.
+func (b *binding) reset(args []js.Value) interface{} {
+	b.eq.Reset()
+
+	return nil
+}
+
+// bands returns the current bands in the same format as setBands.
+func (b *binding) bands(args []js.Value) interface{} {
+	data, _ := json.Marshal(config.Filters(b.eq.Bands()))
+
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+// release releases the functions of the object. The object can not be used after it.
+func (b *binding) release() interface{} {
+	for _, f := range b.funcs {
+		f.Release()
+	}
+
+	b.funcs = nil
+
+	return nil
+}
+
+// parseBands converts the JavaScript array of the bands through JSON, so the fields are the same as the config file.
+func parseBands(sampleRate float64, value js.Value) ([]equalizer.Band, error) {
+	var filters []config.Filter
+
+	data := js.Global().Get("JSON").Call("stringify", value).String()
+
+	if err := json.Unmarshal([]byte(data), &filters); err != nil {
+		return nil, fmt.Errorf("equalizer: invalid bands: %w", err)
+	}
+
+	bands, err := config.Bands(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, band := range bands {
+		if err := equalizer.CheckFrequency(sampleRate, band.Frequency); err != nil {
+			return nil, err
+		}
+	}
+
+	return bands, nil
+}
+
+// jsError converts the error to the JavaScript Error.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
@@ -15,34 +15,20 @@ func main() {
 		panic(err)
 	}
 
-	// f0 -> L channel / f1 -> R channel
-	f0 := equalizer.NewBandPass(44100, 440, 0.5)
-	f1 := equalizer.NewBandPass(44100, 440, 0.5)
-
-	ch := 0
-	bs := []byte{}
-
-	for i := 0; i < len(data); i += 8 {
-		input := math.Float64frombits(
-			binary.LittleEndian.Uint64(data[i : i+8]),
+	// The stereo filter keeps the state of the L and R channels separately.
+	filter := equalizer.NewStereo(equalizer.NewBandPass(44100, 440, 0.5))
+
+	// The output is written over the input, so the loop allocates nothing per sample.
+	for i := 0; i+16 <= len(data); i += 16 {
+		left, right := filter.Apply(
+			math.Float64frombits(binary.LittleEndian.Uint64(data[i:i+8])),
+			math.Float64frombits(binary.LittleEndian.Uint64(data[i+8:i+16])),
 		)
 
-		output := input
-
-		if ch == 0 {
-			output = f0.Apply(output)
-		} else {
-			output = f1.Apply(output)
-		}
-
-		ch = (ch + 1) % 2
-
-		b := make([]byte, 8)
-		binary.LittleEndian.PutUint64(b, math.Float64bits(output))
-
-		bs = append(bs, b...)
+		binary.LittleEndian.PutUint64(data[i:i+8], math.Float64bits(left))
+		binary.LittleEndian.PutUint64(data[i+8:i+16], math.Float64bits(right))
 	}
-	if err := ioutil.WriteFile("output.raw", bs, 0644); err != nil {
+	if err := ioutil.WriteFile("output.raw", data, 0644); err != nil {
 		panic(err)
 	}
 }
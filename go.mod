@@ -1,3 +1,5 @@
 module github.com/moutend/go-equalizer
 
 go 1.15
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,110 @@
+// Package beep connects the equalizer to the playback graph of github.com/faiface/beep.
+//
+// The types implement the interfaces of beep structurally, so this package does not import beep and beep is not
+// required to build the equalizer.
+package beep
+
+import "github.com/moutend/go-equalizer/pkg/equalizer"
+
+// Source is the stereo stream. It has the same methods as beep.Streamer, so any beep.Streamer can be passed.
+type Source interface {
+	Stream(samples [][2]float64) (n int, ok bool)
+	Err() error
+}
+
+// bufferProcessor is implemented by the processors which can process the buffer faster than calling Apply for each sample, e.g. equalizer.Chain.
+type bufferProcessor interface {
+	ProcessBuffer(buffer []float64)
+}
+
+// resetter is implemented by the processors which have the state variables.
+type resetter interface {
+	Reset()
+}
+
+// Streamer applies the processors to the samples streamed from the source. It implements the beep.Streamer interface,
+// so it can be passed to speaker.Play, beep.Seq, beep.Mix and the other streamers of beep.
+type Streamer struct {
+	source Source
+	left   equalizer.Processor
+	right  equalizer.Processor
+
+	// buffer holds one channel of the samples while the processor processes them.
+	buffer []float64
+}
+
+// NewStreamer returns the streamer.
+//
+// Parameters:
+//
+//     - source ... Stream to be equalized. e.g. the streamer returned by wav.Decode of beep
+//     - left ... Processor for the left channel. e.g. *equalizer.Chain
+//     - right ... Processor for the right channel.
+//
+// NOTE: The processors have the state variables, so pass the different instances to left and right. The nil processor passes the channel through.
+// The processors must be designed for the sample rate of the source, i.e. format.SampleRate of beep.
+func NewStreamer(source Source, left, right equalizer.Processor) *Streamer {
+	return &Streamer{
+		source: source,
+		left:   left,
+		right:  right,
+	}
+}
+
+// Source returns the wrapped stream.
+func (s *Streamer) Source() Source {
+	return s.source
+}
+
+// Stream fills the samples from the source and applies the processors to them.
+func (s *Streamer) Stream(samples [][2]float64) (int, bool) {
+	n, ok := s.source.Stream(samples)
+
+	if n > len(s.buffer) {
+		s.buffer = make([]float64, n)
+	}
+
+	s.process(samples[:n], 0, s.left)
+	s.process(samples[:n], 1, s.right)
+
+	return n, ok
+}
+
+// Err returns the error of the source.
+func (s *Streamer) Err() error {
+	return s.source.Err()
+}
+
+// Reset clears the state variables of the processors. Call it after seeking the source to avoid the click.
+func (s *Streamer) Reset() {
+	for _, processor := range []equalizer.Processor{s.left, s.right} {
+		if r, ok := processor.(resetter); ok {
+			r.Reset()
+		}
+	}
+}
+
+// process applies the processor to the channel of the samples in place.
+func (s *Streamer) process(samples [][2]float64, channel int, processor equalizer.Processor) {
+	if processor == nil {
+		return
+	}
+	if p, ok := processor.(bufferProcessor); ok {
+		buffer := s.buffer[:len(samples)]
+
+		for i := range samples {
+			buffer[i] = samples[i][channel]
+		}
+
+		p.ProcessBuffer(buffer)
+
+		for i := range samples {
+			samples[i][channel] = buffer[i]
+		}
+
+		return
+	}
+	for i := range samples {
+		samples[i][channel] = processor.Apply(samples[i][channel])
+	}
+}
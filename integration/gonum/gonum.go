@@ -0,0 +1,33 @@
+// Package gonum exposes the frequency responses of the filters and the chains as plotter.XYs of gonum.org/v1/plot, so
+// they can be drawn with plotter.NewLine and plotter.NewScatter together with the other data.
+//
+// This package is the separate module which depends on gonum.org/v1/plot.
+package gonum
+
+import (
+	"github.com/moutend/go-equalizer/pkg/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// XYs copies the curve sampled by pkg/plot to plotter.XYs.
+func XYs(xys plot.XYs) plotter.XYs {
+	points := make(plotter.XYs, len(xys))
+
+	for i, xy := range xys {
+		points[i] = plotter.XY{X: xy.X, Y: xy.Y}
+	}
+
+	return points
+}
+
+// MagnitudeXYs returns the gain in dB of the responder at the frequencies in Hz, e.g. of equalizer.Filter or equalizer.Chain.
+//
+// NOTE: Set plot.LogScale{} to the X axis scale and plot.LogTicks{} to the X axis tick marker of the gonum plot to show the frequency on the logarithmic axis.
+func MagnitudeXYs(responder plot.Responder, frequencies []float64) plotter.XYs {
+	return XYs(plot.MagnitudeXYs(responder, frequencies))
+}
+
+// PhaseXYs returns the phase in degrees between -180 and 180 of the responder at the frequencies in Hz.
+func PhaseXYs(responder plot.Responder, frequencies []float64) plotter.XYs {
+	return XYs(plot.PhaseXYs(responder, frequencies))
+}
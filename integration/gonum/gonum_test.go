@@ -0,0 +1,69 @@
+package gonum
+
+import (
+	"math"
+	"testing"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/plot"
+	gonumplot "gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestMagnitudeXYs(t *testing.T) {
+	chain := equalizer.NewChain(
+		equalizer.NewPeaking(48000.0, 1000.0, 1.0, 6.0),
+		equalizer.NewHighShelf(48000.0, 8000.0, 0.707, -3.0),
+	)
+	frequencies := plot.Frequencies(20.0, 20000.0, 200)
+	xys := MagnitudeXYs(chain, frequencies)
+
+	if len(xys) != len(frequencies) {
+		t.Fatalf("%d points, want %d", len(xys), len(frequencies))
+	}
+
+	// The points are accepted by gonum as they are.
+	line, err := plotter.NewLine(xys)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := gonumplot.New()
+	p.X.Scale = gonumplot.LogScale{}
+	p.X.Tick.Marker = gonumplot.LogTicks{}
+	p.Add(line)
+
+	if _, err := p.WriterTo(4*96, 3*96, "png"); err != nil {
+		t.Fatal(err)
+	}
+	for i, xy := range line.XYs {
+		if xy.X != frequencies[i] {
+			t.Errorf("point %d is at %v Hz, want %v Hz", i, xy.X, frequencies[i])
+		}
+	}
+
+	// The peak of 6 dB is at 1 kHz.
+	for i, frequency := range frequencies {
+		if math.Abs(frequency-1000.0)/1000.0 > 0.02 {
+			continue
+		}
+		if math.Abs(xys[i].Y-6.0) > 0.1 {
+			t.Errorf("gain at %v Hz is %v dB, want 6 dB", frequency, xys[i].Y)
+		}
+	}
+}
+
+func TestPhaseXYs(t *testing.T) {
+	frequencies := plot.Frequencies(20.0, 20000.0, 50)
+	xys := PhaseXYs(equalizer.NewAllPass(48000.0, 1000.0, 0.707), frequencies)
+
+	if _, err := plotter.NewScatter(xys); err != nil {
+		t.Fatal(err)
+	}
+	for i, xy := range xys {
+		if xy.Y < -180.0 || xy.Y > 180.0 {
+			t.Errorf("phase of point %d is %v degrees", i, xy.Y)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package equalizerpb is the generated code of equalizer.proto.
+package equalizerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative equalizer.proto
@@ -0,0 +1,339 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: equalizer.proto
+
+package equalizerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Band is one filter of the chain.
+type Band struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// type is one of "lowpass", "highpass", "bandpass", "notch", "allpass", "peak", "lowshelf" and "highshelf".
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// frequency is the center or the cutoff frequency in Hz.
+	Frequency float64 `protobuf:"fixed64,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	// q is the quality factor. The default of the type is used when it is not set.
+	Q *float64 `protobuf:"fixed64,3,opt,name=q,proto3,oneof" json:"q,omitempty"`
+	// gain is the gain in dB of the peak and the shelf filters.
+	Gain          float64 `protobuf:"fixed64,4,opt,name=gain,proto3" json:"gain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Band) Reset() {
+	*x = Band{}
+	mi := &file_equalizer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Band) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Band) ProtoMessage() {}
+
+func (x *Band) ProtoReflect() protoreflect.Message {
+	mi := &file_equalizer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Band.ProtoReflect.Descriptor instead.
+func (*Band) Descriptor() ([]byte, []int) {
+	return file_equalizer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Band) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Band) GetFrequency() float64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *Band) GetQ() float64 {
+	if x != nil && x.Q != nil {
+		return *x.Q
+	}
+	return 0
+}
+
+func (x *Band) GetGain() float64 {
+	if x != nil {
+		return x.Gain
+	}
+	return 0
+}
+
+// FilterSpec describes the stream and the filters applied to every channel.
+type FilterSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sample_rate is the sample rate in Hz. It can not be changed after the first request.
+	SampleRate float64 `protobuf:"fixed64,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	// channels is the number of the interleaved channels. It can not be changed after the first request.
+	Channels uint32 `protobuf:"varint,2,opt,name=channels,proto3" json:"channels,omitempty"`
+	// bands are applied in the order. The state of the filters is preserved when only the parameters are changed.
+	Bands         []*Band `protobuf:"bytes,3,rep,name=bands,proto3" json:"bands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterSpec) Reset() {
+	*x = FilterSpec{}
+	mi := &file_equalizer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterSpec) ProtoMessage() {}
+
+func (x *FilterSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_equalizer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterSpec.ProtoReflect.Descriptor instead.
+func (*FilterSpec) Descriptor() ([]byte, []int) {
+	return file_equalizer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FilterSpec) GetSampleRate() float64 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *FilterSpec) GetChannels() uint32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+func (x *FilterSpec) GetBands() []*Band {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+type FilterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// spec is required in the first request.
+	Spec *FilterSpec `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	// samples are the interleaved samples between -1.0 and 1.0. The chunk must contain the whole frames.
+	Samples       []float32 `protobuf:"fixed32,2,rep,packed,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterRequest) Reset() {
+	*x = FilterRequest{}
+	mi := &file_equalizer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterRequest) ProtoMessage() {}
+
+func (x *FilterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_equalizer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterRequest.ProtoReflect.Descriptor instead.
+func (*FilterRequest) Descriptor() ([]byte, []int) {
+	return file_equalizer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FilterRequest) GetSpec() *FilterSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetSamples() []float32 {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type FilterResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// samples are the filtered samples of the chunk in the same order.
+	Samples       []float32 `protobuf:"fixed32,1,rep,packed,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterResponse) Reset() {
+	*x = FilterResponse{}
+	mi := &file_equalizer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterResponse) ProtoMessage() {}
+
+func (x *FilterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_equalizer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterResponse.ProtoReflect.Descriptor instead.
+func (*FilterResponse) Descriptor() ([]byte, []int) {
+	return file_equalizer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FilterResponse) GetSamples() []float32 {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+var File_equalizer_proto protoreflect.FileDescriptor
+
+const file_equalizer_proto_rawDesc = "" +
+	"\n" +
+	"\x0fequalizer.proto\x12\fequalizer.v1\"e\n" +
+	"\x04Band\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1c\n" +
+	"\tfrequency\x18\x02 \x01(\x01R\tfrequency\x12\x11\n" +
+	"\x01q\x18\x03 \x01(\x01H\x00R\x01q\x88\x01\x01\x12\x12\n" +
+	"\x04gain\x18\x04 \x01(\x01R\x04gainB\x04\n" +
+	"\x02_q\"s\n" +
+	"\n" +
+	"FilterSpec\x12\x1f\n" +
+	"\vsample_rate\x18\x01 \x01(\x01R\n" +
+	"sampleRate\x12\x1a\n" +
+	"\bchannels\x18\x02 \x01(\rR\bchannels\x12(\n" +
+	"\x05bands\x18\x03 \x03(\v2\x12.equalizer.v1.BandR\x05bands\"W\n" +
+	"\rFilterRequest\x12,\n" +
+	"\x04spec\x18\x01 \x01(\v2\x18.equalizer.v1.FilterSpecR\x04spec\x12\x18\n" +
+	"\asamples\x18\x02 \x03(\x02R\asamples\"*\n" +
+	"\x0eFilterResponse\x12\x18\n" +
+	"\asamples\x18\x01 \x03(\x02R\asamples2T\n" +
+	"\tEqualizer\x12G\n" +
+	"\x06Filter\x12\x1b.equalizer.v1.FilterRequest\x1a\x1c.equalizer.v1.FilterResponse(\x010\x01B>Z<github.com/moutend/go-equalizer/integration/grpc/equalizerpbb\x06proto3"
+
+var (
+	file_equalizer_proto_rawDescOnce sync.Once
+	file_equalizer_proto_rawDescData []byte
+)
+
+func file_equalizer_proto_rawDescGZIP() []byte {
+	file_equalizer_proto_rawDescOnce.Do(func() {
+		file_equalizer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_equalizer_proto_rawDesc), len(file_equalizer_proto_rawDesc)))
+	})
+	return file_equalizer_proto_rawDescData
+}
+
+var file_equalizer_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_equalizer_proto_goTypes = []any{
+	(*Band)(nil),           // 0: equalizer.v1.Band
+	(*FilterSpec)(nil),     // 1: equalizer.v1.FilterSpec
+	(*FilterRequest)(nil),  // 2: equalizer.v1.FilterRequest
+	(*FilterResponse)(nil), // 3: equalizer.v1.FilterResponse
+}
+var file_equalizer_proto_depIdxs = []int32{
+	0, // 0: equalizer.v1.FilterSpec.bands:type_name -> equalizer.v1.Band
+	1, // 1: equalizer.v1.FilterRequest.spec:type_name -> equalizer.v1.FilterSpec
+	2, // 2: equalizer.v1.Equalizer.Filter:input_type -> equalizer.v1.FilterRequest
+	3, // 3: equalizer.v1.Equalizer.Filter:output_type -> equalizer.v1.FilterResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_equalizer_proto_init() }
+func file_equalizer_proto_init() {
+	if File_equalizer_proto != nil {
+		return
+	}
+	file_equalizer_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_equalizer_proto_rawDesc), len(file_equalizer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_equalizer_proto_goTypes,
+		DependencyIndexes: file_equalizer_proto_depIdxs,
+		MessageInfos:      file_equalizer_proto_msgTypes,
+	}.Build()
+	File_equalizer_proto = out.File
+	file_equalizer_proto_goTypes = nil
+	file_equalizer_proto_depIdxs = nil
+}
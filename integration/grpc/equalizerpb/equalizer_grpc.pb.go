@@ -0,0 +1,123 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: equalizer.proto
+
+package equalizerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Equalizer_Filter_FullMethodName = "/equalizer.v1.Equalizer/Filter"
+)
+
+// EqualizerClient is the client API for Equalizer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Equalizer applies the filters to the PCM streamed by the client.
+type EqualizerClient interface {
+	// Filter applies the filters to the chunks in the order of the requests and returns one response for each request.
+	// The first request must have the spec. The later requests may have the spec to change the bands while streaming.
+	Filter(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FilterRequest, FilterResponse], error)
+}
+
+type equalizerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEqualizerClient(cc grpc.ClientConnInterface) EqualizerClient {
+	return &equalizerClient{cc}
+}
+
+func (c *equalizerClient) Filter(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FilterRequest, FilterResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Equalizer_ServiceDesc.Streams[0], Equalizer_Filter_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FilterRequest, FilterResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Equalizer_FilterClient = grpc.BidiStreamingClient[FilterRequest, FilterResponse]
+
+// EqualizerServer is the server API for Equalizer service.
+// All implementations must embed UnimplementedEqualizerServer
+// for forward compatibility.
+//
+// Equalizer applies the filters to the PCM streamed by the client.
+type EqualizerServer interface {
+	// Filter applies the filters to the chunks in the order of the requests and returns one response for each request.
+	// The first request must have the spec. The later requests may have the spec to change the bands while streaming.
+	Filter(grpc.BidiStreamingServer[FilterRequest, FilterResponse]) error
+	mustEmbedUnimplementedEqualizerServer()
+}
+
+// UnimplementedEqualizerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEqualizerServer struct{}
+
+func (UnimplementedEqualizerServer) Filter(grpc.BidiStreamingServer[FilterRequest, FilterResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Filter not implemented")
+}
+func (UnimplementedEqualizerServer) mustEmbedUnimplementedEqualizerServer() {}
+func (UnimplementedEqualizerServer) testEmbeddedByValue()                   {}
+
+// UnsafeEqualizerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EqualizerServer will
+// result in compilation errors.
+type UnsafeEqualizerServer interface {
+	mustEmbedUnimplementedEqualizerServer()
+}
+
+func RegisterEqualizerServer(s grpc.ServiceRegistrar, srv EqualizerServer) {
+	// If the following call pancis, it indicates UnimplementedEqualizerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Equalizer_ServiceDesc, srv)
+}
+
+func _Equalizer_Filter_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EqualizerServer).Filter(&grpc.GenericServerStream[FilterRequest, FilterResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Equalizer_FilterServer = grpc.BidiStreamingServer[FilterRequest, FilterResponse]
+
+// Equalizer_ServiceDesc is the grpc.ServiceDesc for Equalizer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Equalizer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "equalizer.v1.Equalizer",
+	HandlerType: (*EqualizerServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Filter",
+			Handler:       _Equalizer_Filter_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "equalizer.proto",
+}
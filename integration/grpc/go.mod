@@ -0,0 +1,20 @@
+module github.com/moutend/go-equalizer/integration/grpc
+
+go 1.25.0
+
+require (
+	github.com/moutend/go-equalizer v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.82.1
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	golang.org/x/net v0.53.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
+	golang.org/x/text v0.36.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260414002931-afd174a4e478 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The root module has no published release yet, so it is always built from the local copy.
+replace github.com/moutend/go-equalizer => ../..
@@ -0,0 +1,180 @@
+// Package grpc serves the equalizer as the gRPC service defined in equalizerpb/equalizer.proto, so the media pipelines
+// written in any language can stream the PCM to it and receive the filtered PCM back.
+//
+// This package is the separate module which depends on google.golang.org/grpc.
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/moutend/go-equalizer/integration/grpc/equalizerpb"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pipeline"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxChannels is the largest number of the channels accepted by the server.
+const MaxChannels = 64
+
+// errNoSpec is returned when the first request of the stream does not have the spec.
+var errNoSpec = errors.New("grpc: the first request must have the spec")
+
+// Server implements equalizerpb.EqualizerServer. Each stream has its own filters, so the streams are processed independently.
+type Server struct {
+	equalizerpb.UnimplementedEqualizerServer
+}
+
+// NewServer returns the server. Register it with equalizerpb.RegisterEqualizerServer.
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Filter applies the filters to the chunks until the client closes the stream.
+func (s *Server) Filter(stream equalizerpb.Equalizer_FilterServer) error {
+	var f *filter
+
+	for {
+		request, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if spec := request.GetSpec(); spec != nil {
+			if f == nil {
+				f, err = newFilter(spec)
+			} else {
+				err = f.update(spec)
+			}
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		if f == nil {
+			return status.Error(codes.InvalidArgument, errNoSpec.Error())
+		}
+
+		samples := request.GetSamples()
+
+		if len(samples)%len(f.equalizers) != 0 {
+			return status.Errorf(codes.InvalidArgument, "grpc: %d samples are not the whole frames of %d channels", len(samples), len(f.equalizers))
+		}
+		if err := stream.Send(&equalizerpb.FilterResponse{Samples: f.process(samples)}); err != nil {
+			return err
+		}
+	}
+}
+
+// filter is the state of one stream.
+type filter struct {
+	sampleRate float64
+	equalizers []*equalizer.ParametricEQ
+	bands      int
+	buffer     []float64
+}
+
+func newFilter(spec *equalizerpb.FilterSpec) (*filter, error) {
+	if spec.GetSampleRate() <= 0.0 {
+		return nil, fmt.Errorf("grpc: invalid sample rate %v", spec.GetSampleRate())
+	}
+	if spec.GetChannels() < 1 || spec.GetChannels() > MaxChannels {
+		return nil, fmt.Errorf("grpc: invalid channels %d", spec.GetChannels())
+	}
+
+	f := &filter{
+		sampleRate: spec.GetSampleRate(),
+		equalizers: make([]*equalizer.ParametricEQ, spec.GetChannels()),
+	}
+
+	for i := range f.equalizers {
+		f.equalizers[i] = equalizer.NewParametricEQ(f.sampleRate)
+	}
+	if err := f.update(spec); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// update replaces the bands. When the number of the bands is not changed, the state variables are preserved, so the change does not click.
+func (f *filter) update(spec *equalizerpb.FilterSpec) error {
+	if spec.GetSampleRate() != f.sampleRate || int(spec.GetChannels()) != len(f.equalizers) {
+		return errors.New("grpc: sample rate and channels can not be changed")
+	}
+
+	bands, err := bands(spec.GetBands())
+
+	if err != nil {
+		return err
+	}
+
+	for _, band := range bands {
+		if err := equalizer.CheckFrequency(f.sampleRate, band.Frequency); err != nil {
+			return err
+		}
+	}
+
+	if len(bands) == f.bands {
+		for _, eq := range f.equalizers {
+			for i, band := range bands {
+				eq.SetBand(i, band)
+			}
+		}
+	} else {
+		for i := range f.equalizers {
+			f.equalizers[i] = equalizer.NewParametricEQ(f.sampleRate, bands...)
+		}
+	}
+
+	f.bands = len(bands)
+
+	return nil
+}
+
+// process applies the filters to the interleaved samples and returns the filtered samples.
+func (f *filter) process(samples []float32) []float32 {
+	channels := len(f.equalizers)
+	frames := len(samples) / channels
+	output := make([]float32, len(samples))
+
+	if cap(f.buffer) < frames {
+		f.buffer = make([]float64, frames)
+	}
+
+	buffer := f.buffer[:frames]
+
+	for c, eq := range f.equalizers {
+		for i := range buffer {
+			buffer[i] = float64(samples[i*channels+c])
+		}
+
+		eq.ProcessBuffer(buffer)
+
+		for i := range buffer {
+			output[i*channels+c] = float32(buffer[i])
+		}
+	}
+
+	return output
+}
+
+// bands converts the bands of the spec with the same type names as the config file.
+func bands(specs []*equalizerpb.Band) ([]equalizer.Band, error) {
+	filters := make([]pipeline.Filter, len(specs))
+
+	for i, spec := range specs {
+		filters[i] = pipeline.Filter{
+			Type:      spec.GetType(),
+			Frequency: spec.GetFrequency(),
+			Q:         spec.Q,
+			Gain:      spec.GetGain(),
+		}
+	}
+
+	return pipeline.Bands(filters)
+}
@@ -0,0 +1,57 @@
+// The server serves the gRPC equalizer service.
+//
+// Usage:
+//
+//     go run . -addr localhost:50051
+//
+// The clients call equalizer.v1.Equalizer/Filter with the spec in the first request, e.g. with grpcurl:
+//
+//     grpcurl -plaintext -d '{"spec":{"sample_rate":48000,"channels":1,"bands":[{"type":"peak","frequency":1000,"q":1,"gain":6}]},"samples":[0.5,0,0]}' localhost:50051 equalizer.v1.Equalizer/Filter
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+
+	eqgrpc "github.com/moutend/go-equalizer/integration/grpc"
+	"github.com/moutend/go-equalizer/integration/grpc/equalizerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	var addr string
+
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	flags.StringVar(&addr, "addr", "localhost:50051", "`address` to listen on")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	equalizerpb.RegisterEqualizerServer(server, eqgrpc.NewServer())
+
+	// The reflection lets grpcurl call the service without the proto file.
+	reflection.Register(server)
+
+	log.Printf("listening on %s", listener.Addr())
+
+	return server.Serve(listener)
+}
@@ -0,0 +1,11 @@
+module github.com/moutend/go-equalizer/integration/jack
+
+go 1.19
+
+require (
+	github.com/moutend/go-equalizer v0.0.0-00010101000000-000000000000
+	github.com/xthexder/go-jack v0.0.0-20220805234212-bc8604043aba
+)
+
+// The root module has no published release yet, so it is always built from the local copy.
+replace github.com/moutend/go-equalizer => ../..
@@ -0,0 +1,275 @@
+// Package jack registers the equalizer as the JACK client with github.com/xthexder/go-jack, so it can be inserted
+// into the session graph of the JACK or PipeWire JACK server like the other plugins.
+//
+// This package is the separate module which needs cgo and the JACK development files, e.g. libjack-jackd2-dev.
+package jack
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/xthexder/go-jack"
+)
+
+// ErrClosed is returned when the client is used after Close or after the server shut down.
+var ErrClosed = errors.New("jack: client is closed")
+
+// Factory returns the processor of the channel designed for the sample rate. It is called again when the server changes the sample rate.
+type Factory func(sampleRate float64, channel int) equalizer.Processor
+
+// bufferProcessor is implemented by the processors which can process the buffer faster than calling Apply for each sample, e.g. equalizer.Chain.
+type bufferProcessor interface {
+	ProcessBuffer(buffer []float64)
+}
+
+// Client is the JACK client which has the input and the output ports of each channel and processes them through the processors.
+type Client struct {
+	client  *jack.Client
+	inputs  []*jack.Port
+	outputs []*jack.Port
+	factory Factory
+
+	// mu guards the processors while the server calls the process callback from the real-time thread.
+	mu         sync.Mutex
+	processors []equalizer.Processor
+	bypass     bool
+	buffer     []float64
+	xruns      int64
+
+	// closed is set by Close and down is set when the server shuts down.
+	closed bool
+	down   bool
+}
+
+// NewClient opens the client and registers the ports "in_1", "out_1", "in_2", "out_2" and so on. Call Activate to begin the processing.
+//
+// Parameters:
+//
+//     - name ... Client name shown in the session graph. e.g. "equalizer"
+//     - channels ... Number of the input and the output ports. e.g. 2
+//     - factory ... Function which designs the processor of each channel for the sample rate of the server.
+func NewClient(name string, channels int, factory Factory) (*Client, error) {
+	if channels < 1 {
+		return nil, fmt.Errorf("jack: invalid channels %d", channels)
+	}
+
+	client, status := jack.ClientOpen(name, jack.NoStartServer)
+
+	if client == nil {
+		return nil, fmt.Errorf("jack: failed to open the client: %v", jack.StrError(status))
+	}
+
+	c := &Client{
+		client:  client,
+		factory: factory,
+	}
+
+	for i := 1; i <= channels; i++ {
+		input := client.PortRegister(fmt.Sprintf("in_%d", i), jack.DEFAULT_AUDIO_TYPE, jack.PortIsInput, 0)
+		output := client.PortRegister(fmt.Sprintf("out_%d", i), jack.DEFAULT_AUDIO_TYPE, jack.PortIsOutput, 0)
+
+		if input == nil || output == nil {
+			client.Close()
+
+			return nil, fmt.Errorf("jack: failed to register the ports of the channel %d", i)
+		}
+
+		c.inputs = append(c.inputs, input)
+		c.outputs = append(c.outputs, output)
+	}
+
+	c.design(client.GetSampleRate())
+	c.buffer = make([]float64, client.GetBufferSize())
+
+	client.SetProcessCallback(c.process)
+	client.SetSampleRateCallback(c.setSampleRate)
+	client.SetBufferSizeCallback(c.setBufferSize)
+	client.SetXRunCallback(c.xrun)
+	client.OnShutdown(c.shutdown)
+
+	return c, nil
+}
+
+// Name returns the actual client name, which can be different from the requested name when it was not unique.
+func (c *Client) Name() string {
+	return c.client.GetName()
+}
+
+// SampleRate returns the sample rate of the server in Hz.
+func (c *Client) SampleRate() int {
+	return int(c.client.GetSampleRate())
+}
+
+// Inputs returns the full names of the input ports, e.g. "equalizer:in_1".
+func (c *Client) Inputs() []string {
+	return portNames(c.inputs)
+}
+
+// Outputs returns the full names of the output ports, e.g. "equalizer:out_1".
+func (c *Client) Outputs() []string {
+	return portNames(c.outputs)
+}
+
+// Activate tells the server that the client is ready to process.
+func (c *Client) Activate() error {
+	if c.isClosed() {
+		return ErrClosed
+	}
+
+	return jack.StrError(c.client.Activate())
+}
+
+// Connect connects the ports by the full names, e.g. Connect("system:capture_1", client.Inputs()[0]).
+func (c *Client) Connect(source, destination string) error {
+	if c.isClosed() {
+		return ErrClosed
+	}
+
+	return jack.StrError(c.client.Connect(source, destination))
+}
+
+// SetFactory replaces the factory and designs the processors again. The state variables of the old processors are discarded.
+func (c *Client) SetFactory(factory Factory) {
+	c.mu.Lock()
+	c.factory = factory
+	c.mu.Unlock()
+
+	c.design(c.client.GetSampleRate())
+}
+
+// Update calls f while the processors are not processing, so f can modify them safely, e.g. with ParametricEQ.SetBand.
+func (c *Client) Update(f func(processors []equalizer.Processor)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f(c.processors)
+}
+
+// SetBypass enables or disables the processors. The bypassed input is copied to the output.
+func (c *Client) SetBypass(bypass bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.bypass = bypass
+}
+
+// XRuns returns the number of the xruns reported by the server since the client was opened.
+func (c *Client) XRuns() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.xruns
+}
+
+// Close deactivates the client and unregisters the ports. It must be called even after the server shut down to release the client.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+
+	if closed {
+		return nil
+	}
+
+	return jack.StrError(c.client.Close())
+}
+
+func (c *Client) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed || c.down
+}
+
+// design creates the processors for the sample rate.
+func (c *Client) design(sampleRate uint32) {
+	processors := make([]equalizer.Processor, len(c.inputs))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range processors {
+		processors[i] = c.factory(float64(sampleRate), i)
+	}
+
+	c.processors = processors
+}
+
+// process is the process callback called by the server for each period.
+func (c *Client) process(frames uint32) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The buffer is allocated by the buffer size callback, which is called before the first period of the new size.
+	buffer := c.buffer[:frames]
+
+	for i, processor := range c.processors {
+		input := c.inputs[i].GetBuffer(frames)
+		output := c.outputs[i].GetBuffer(frames)
+
+		for j, sample := range input {
+			buffer[j] = float64(sample)
+		}
+		if !c.bypass && processor != nil {
+			if p, ok := processor.(bufferProcessor); ok {
+				p.ProcessBuffer(buffer)
+			} else {
+				for j := range buffer {
+					buffer[j] = processor.Apply(buffer[j])
+				}
+			}
+		}
+		for j := range output {
+			output[j] = jack.AudioSample(buffer[j])
+		}
+	}
+
+	return 0
+}
+
+func (c *Client) setSampleRate(sampleRate uint32) int {
+	c.design(sampleRate)
+
+	return 0
+}
+
+func (c *Client) setBufferSize(size uint32) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if int(size) > len(c.buffer) {
+		c.buffer = make([]float64, size)
+	}
+
+	return 0
+}
+
+func (c *Client) xrun() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.xruns++
+
+	return 0
+}
+
+// shutdown is called when the server shuts down or disconnects the client. The client cannot be used after it.
+func (c *Client) shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.down = true
+}
+
+func portNames(ports []*jack.Port) []string {
+	names := make([]string, len(ports))
+
+	for i, port := range ports {
+		names[i] = port.GetName()
+	}
+
+	return names
+}
@@ -0,0 +1,11 @@
+module github.com/moutend/go-equalizer/integration/malgo
+
+go 1.21
+
+require (
+	github.com/gen2brain/malgo v0.11.26
+	github.com/moutend/go-equalizer v0.0.0-00010101000000-000000000000
+)
+
+// The root module has no published release yet, so it is always built from the local copy.
+replace github.com/moutend/go-equalizer => ../..
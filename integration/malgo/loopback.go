@@ -0,0 +1,366 @@
+package malgo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ma "github.com/gen2brain/malgo"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pcm"
+)
+
+// ErrSameDevice is returned when the loopback source and the target are the same device, which captures its own output again.
+var ErrSameDevice = errors.New("malgo: loopback source and target must be different devices")
+
+// ErrNoTarget is returned when the target of the loopback is not given, because the default render device is usually the source.
+var ErrNoTarget = errors.New("malgo: loopback target device is required")
+
+// LoopbackConfig describes the captured render device and the device which plays the equalized output.
+type LoopbackConfig struct {
+	// SampleRate is the sample rate in Hz. 0 picks the native rate of the source.
+	SampleRate int
+
+	// Channels is the number of the channels of both the source and the target.
+	Channels int
+
+	// PeriodSize is the number of the frames processed by one callback. 0 picks 10 ms.
+	PeriodSize int
+
+	// Periods is the number of the periods in the buffer of each device. 0 picks 3.
+	Periods int
+
+	// SourceID is the render device whose output is captured, e.g. the virtual cable set as the default output of the system.
+	// nil means the default render device.
+	SourceID *ma.DeviceID
+
+	// TargetID is the render device which plays the equalized output, e.g. the speakers. It is required, and OpenLoopback
+	// returns ErrNoTarget when it is nil.
+	TargetID *ma.DeviceID
+}
+
+// Loopback captures the output of the render device with the WASAPI loopback, applies the processor of each channel and plays
+// it on the other render device. When the default output of the system is the virtual cable and the target is the speakers,
+// it is the system-wide equalizer of Windows.
+//
+// The source and the target run on the different clocks, so the ring buffer between them absorbs the jitter and the drift.
+// The frames are dropped or the silence is inserted when the drift exceeds the buffer, and they are counted in Stats.
+type Loopback struct {
+	context  *ma.AllocatedContext
+	capture  *ma.Device
+	playback *ma.Device
+
+	sampleRate int
+	periodSize int
+	periods    int
+
+	// mu guards the processors, the ring buffer and the statistics, which are shared by the callbacks of both devices.
+	mu         sync.Mutex
+	processors []equalizer.Processor
+	bypass     bool
+	samples    []float64
+	channel    []float64
+	stats      Stats
+	processing time.Duration
+
+	// ring holds the interleaved frames from the capture to the playback. The playback waits until fill frames are buffered.
+	ring    []float64
+	read    int
+	count   int
+	fill    int
+	playing bool
+}
+
+// OpenLoopback opens the loopback capture of the source and the playback of the target. Call Start to begin the processing and Close to release the devices.
+//
+// Parameters:
+//
+//     - config ... Source, target, sample rate, channels and buffer size of the devices.
+//     - processors ... Processor of each channel. e.g. *equalizer.ParametricEQ for the left and the right channels
+//
+// NOTE: Only the WASAPI backend of Windows supports the loopback, so the other platforms return the error.
+// The processors must be designed for the sample rate. When config.SampleRate is 0, design them for SampleRate after OpenLoopback and set them with SetProcessors.
+func OpenLoopback(config LoopbackConfig, processors ...equalizer.Processor) (*Loopback, error) {
+	if config.Channels < 1 {
+		return nil, fmt.Errorf("malgo: invalid channels %d", config.Channels)
+	}
+	if len(processors) != config.Channels {
+		return nil, fmt.Errorf("%w: %d processors for %d channels", ErrChannels, len(processors), config.Channels)
+	}
+	if config.TargetID == nil {
+		return nil, ErrNoTarget
+	}
+	if config.SourceID != nil && *config.SourceID == *config.TargetID {
+		return nil, ErrSameDevice
+	}
+
+	context, err := ma.InitContext([]ma.Backend{ma.BackendWasapi}, ma.ContextConfig{}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Loopback{
+		context:    context,
+		processors: processors,
+		periodSize: config.PeriodSize,
+		periods:    config.Periods,
+	}
+
+	captureConfig := ma.DefaultDeviceConfig(ma.Loopback)
+	captureConfig.SampleRate = uint32(config.SampleRate)
+	captureConfig.PerformanceProfile = ma.LowLatency
+	captureConfig.Capture.Format = ma.FormatF32
+	captureConfig.Capture.Channels = uint32(config.Channels)
+
+	// The capture device of the loopback is the render device to be captured.
+	if config.SourceID != nil {
+		captureConfig.Capture.DeviceID = config.SourceID.Pointer()
+	}
+	if config.PeriodSize > 0 {
+		captureConfig.PeriodSizeInFrames = uint32(config.PeriodSize)
+	}
+	if config.Periods > 0 {
+		captureConfig.Periods = uint32(config.Periods)
+	}
+
+	capture, err := ma.InitDevice(context.Context, captureConfig, ma.DeviceCallbacks{
+		Data: l.captured,
+	})
+
+	if err != nil {
+		l.closeContext()
+
+		return nil, err
+	}
+
+	l.capture = capture
+	l.sampleRate = int(capture.SampleRate())
+
+	// The target plays at the same rate, and miniaudio converts it when the target runs at the other rate.
+	playbackConfig := ma.DefaultDeviceConfig(ma.Playback)
+	playbackConfig.SampleRate = uint32(l.sampleRate)
+	playbackConfig.PerformanceProfile = ma.LowLatency
+	playbackConfig.PeriodSizeInFrames = captureConfig.PeriodSizeInFrames
+	playbackConfig.Periods = captureConfig.Periods
+	playbackConfig.Playback.Format = ma.FormatF32
+	playbackConfig.Playback.Channels = uint32(config.Channels)
+	playbackConfig.Playback.DeviceID = config.TargetID.Pointer()
+
+	playback, err := ma.InitDevice(context.Context, playbackConfig, ma.DeviceCallbacks{
+		Data: l.render,
+	})
+
+	if err != nil {
+		capture.Uninit()
+		l.closeContext()
+
+		return nil, err
+	}
+
+	l.playback = playback
+
+	// miniaudio picks 10 ms and 3 periods for the low latency profile when they are 0.
+	if l.periodSize <= 0 {
+		l.periodSize = l.sampleRate / 100
+	}
+	if l.periods <= 0 {
+		l.periods = 3
+	}
+
+	// The ring buffer holds twice the frames the playback waits for, so the capture can run ahead by the same amount.
+	l.fill = l.periodSize * l.periods
+	l.ring = make([]float64, 2*l.fill*config.Channels)
+
+	return l, nil
+}
+
+// SampleRate returns the sample rate of the devices in Hz.
+func (l *Loopback) SampleRate() int {
+	return l.sampleRate
+}
+
+// Start starts the processing and clears the statistics and the ring buffer.
+func (l *Loopback) Start() error {
+	l.mu.Lock()
+	l.stats = Stats{}
+	l.processing = 0
+	l.read = 0
+	l.count = 0
+	l.playing = false
+	l.mu.Unlock()
+
+	if err := l.playback.Start(); err != nil {
+		return err
+	}
+	if err := l.capture.Start(); err != nil {
+		l.playback.Stop()
+
+		return err
+	}
+
+	return nil
+}
+
+// Stop stops the processing. It can be started again.
+func (l *Loopback) Stop() error {
+	captureErr := l.capture.Stop()
+	playbackErr := l.playback.Stop()
+
+	if captureErr != nil {
+		return captureErr
+	}
+
+	return playbackErr
+}
+
+// Close stops the processing and releases the devices.
+func (l *Loopback) Close() error {
+	l.capture.Uninit()
+	l.playback.Uninit()
+
+	return l.closeContext()
+}
+
+func (l *Loopback) closeContext() error {
+	err := l.context.Uninit()
+	l.context.Free()
+
+	return err
+}
+
+// SetProcessors replaces the processors. The number of the processors must be the same as the channels.
+func (l *Loopback) SetProcessors(processors ...equalizer.Processor) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(processors) != len(l.processors) {
+		return fmt.Errorf("%w: %d processors for %d channels", ErrChannels, len(processors), len(l.processors))
+	}
+
+	l.processors = processors
+
+	return nil
+}
+
+// Update calls f while the processors are not processing, so f can modify them safely, e.g. with ParametricEQ.SetBand.
+func (l *Loopback) Update(f func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f()
+}
+
+// SetBypass enables or disables the processors. The bypassed input is played as it is.
+func (l *Loopback) SetBypass(bypass bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.bypass = bypass
+}
+
+// Stats returns the statistics of the callbacks. The callbacks and the processing time are of the capture device.
+func (l *Loopback) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+
+	if stats.Callbacks > 0 {
+		stats.AverageProcessing = l.processing / time.Duration(stats.Callbacks)
+	}
+
+	// The frames pass the capture period, the ring buffer and the playback buffer.
+	stats.Period = duration(l.periodSize, l.sampleRate)
+	stats.Latency = duration(l.periodSize+l.fill+l.periodSize*l.periods+processorLatency(l.processors), l.sampleRate)
+
+	return stats
+}
+
+// captured is the data callback of the loopback capture. It processes the frames and writes them to the ring buffer.
+func (l *Loopback) captured(_, input []byte, frameCount uint32) {
+	start := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	channels := len(l.processors)
+	frames := int(frameCount)
+	n := frames * channels
+
+	if len(input) < n*4 {
+		return
+	}
+	if len(l.samples) < n {
+		l.samples = make([]float64, n)
+		l.channel = make([]float64, frames)
+	}
+
+	samples := l.samples[:n]
+	pcm.F32LE.Decode(samples, input[:n*4])
+
+	if !l.bypass {
+		apply(l.processors, samples, l.channel[:frames])
+	}
+
+	// The frames which do not fit in the ring buffer are dropped.
+	free := len(l.ring)/channels - l.count
+
+	if frames > free {
+		l.stats.Overruns += int64(frames - free)
+		frames = free
+	}
+
+	write := (l.read + l.count) * channels
+
+	for i := 0; i < frames*channels; i++ {
+		l.ring[(write+i)%len(l.ring)] = samples[i]
+	}
+
+	l.count += frames
+
+	elapsed := time.Since(start)
+
+	l.stats.Callbacks++
+	l.stats.Frames += int64(frameCount)
+	l.processing += elapsed
+
+	if elapsed > l.stats.MaxProcessing {
+		l.stats.MaxProcessing = elapsed
+	}
+}
+
+// render is the data callback of the target. It reads the frames from the ring buffer.
+func (l *Loopback) render(output, _ []byte, frameCount uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	channels := len(l.processors)
+	frames := int(frameCount)
+
+	// Wait until the ring buffer is filled again after the start or the underrun, so the underruns do not repeat every period.
+	if !l.playing && l.count >= l.fill {
+		l.playing = true
+	}
+	if !l.playing || l.count < frames {
+		for i := range output {
+			output[i] = 0
+		}
+		if l.playing {
+			l.stats.Underruns++
+			l.playing = false
+		}
+
+		return
+	}
+
+	read := l.read * channels
+
+	for i := 0; i < frames*channels; i++ {
+		pcm.F32LE.EncodeSample(output[i*4:(i+1)*4], l.ring[(read+i)%len(l.ring)], pcm.Clip)
+	}
+
+	l.read = (l.read + frames) % (len(l.ring) / channels)
+	l.count -= frames
+}
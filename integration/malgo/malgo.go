@@ -0,0 +1,343 @@
+// Package malgo runs the equalizer between the capture and the playback devices with github.com/gen2brain/malgo,
+// the Go binding of miniaudio. It is the building block of the system-wide live equalizer, e.g. the microphone is
+// captured, equalized and played back with Duplex, or the output of the other device is captured with Loopback on Windows.
+//
+// This package is the separate module, so malgo and cgo are not required to build the equalizer.
+package malgo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ma "github.com/gen2brain/malgo"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pcm"
+)
+
+// ErrChannels is returned when the number of the processors is different from the number of the channels.
+var ErrChannels = errors.New("malgo: number of the processors does not match the channels")
+
+// bufferProcessor is implemented by the processors which can process the buffer faster than calling Apply for each sample, e.g. equalizer.Chain.
+type bufferProcessor interface {
+	ProcessBuffer(buffer []float64)
+}
+
+// latencyReporter is implemented by the processors which delay the signal.
+type latencyReporter interface {
+	Latency() int
+}
+
+// Config describes the duplex device.
+type Config struct {
+	// SampleRate is the sample rate in Hz. 0 picks the native rate of the device.
+	SampleRate int
+
+	// Channels is the number of the channels of both the capture and the playback.
+	Channels int
+
+	// PeriodSize is the number of the frames processed by one callback. 0 picks 10 ms.
+	PeriodSize int
+
+	// Periods is the number of the periods in the buffer of the device. 0 picks 3.
+	Periods int
+
+	// CaptureID and PlaybackID are the IDs of the devices returned by Context.Devices of malgo. nil means the default device.
+	CaptureID  *ma.DeviceID
+	PlaybackID *ma.DeviceID
+}
+
+// Stats is the statistics of the callbacks since the device was started.
+type Stats struct {
+	Callbacks int64
+	Frames    int64
+
+	// Underruns is the number of the callbacks which took longer than the period, so the playback buffer was not refilled in time,
+	// or which received no capture data and played the silence.
+	Underruns int64
+
+	// Overruns is the number of the captured frames dropped because the playback device consumed them slower. It is counted only by Loopback.
+	Overruns int64
+
+	// MaxProcessing and AverageProcessing are the time spent in the callback. The period is the deadline of the callback.
+	MaxProcessing     time.Duration
+	AverageProcessing time.Duration
+	Period            time.Duration
+
+	// Latency is the nominal delay from the capture to the playback, one capture period and the playback buffer plus
+	// the latency reported by the processors. The delay of the hardware and the drivers is not included.
+	Latency time.Duration
+}
+
+// Duplex captures the input, applies the processor of each channel and plays the output.
+type Duplex struct {
+	context *ma.AllocatedContext
+	device  *ma.Device
+
+	sampleRate int
+	periodSize int
+	periods    int
+
+	// mu guards the processors and the statistics while the device calls the callback from its own thread.
+	mu         sync.Mutex
+	processors []equalizer.Processor
+	bypass     bool
+	samples    []float64
+	channel    []float64
+	stats      Stats
+	processing time.Duration
+}
+
+// Open opens the capture and the playback devices. Call Start to begin the processing and Close to release the devices.
+//
+// Parameters:
+//
+//     - config ... Sample rate, channels and buffer size of the devices.
+//     - processors ... Processor of each channel. e.g. *equalizer.ParametricEQ for the left and the right channels
+//
+// NOTE: The processors must be designed for the sample rate. When config.SampleRate is 0, design them for SampleRate after Open and set them with SetProcessors.
+// The nil processor passes the channel through.
+func Open(config Config, processors ...equalizer.Processor) (*Duplex, error) {
+	if config.Channels < 1 {
+		return nil, fmt.Errorf("malgo: invalid channels %d", config.Channels)
+	}
+	if len(processors) != config.Channels {
+		return nil, fmt.Errorf("%w: %d processors for %d channels", ErrChannels, len(processors), config.Channels)
+	}
+
+	context, err := ma.InitContext(nil, ma.ContextConfig{}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deviceConfig := ma.DefaultDeviceConfig(ma.Duplex)
+	deviceConfig.SampleRate = uint32(config.SampleRate)
+	deviceConfig.PerformanceProfile = ma.LowLatency
+	deviceConfig.Capture.Format = ma.FormatF32
+	deviceConfig.Capture.Channels = uint32(config.Channels)
+	deviceConfig.Playback.Format = ma.FormatF32
+	deviceConfig.Playback.Channels = uint32(config.Channels)
+
+	if config.CaptureID != nil {
+		deviceConfig.Capture.DeviceID = config.CaptureID.Pointer()
+	}
+	if config.PlaybackID != nil {
+		deviceConfig.Playback.DeviceID = config.PlaybackID.Pointer()
+	}
+	if config.PeriodSize > 0 {
+		deviceConfig.PeriodSizeInFrames = uint32(config.PeriodSize)
+	}
+	if config.Periods > 0 {
+		deviceConfig.Periods = uint32(config.Periods)
+	}
+
+	d := &Duplex{
+		context:    context,
+		processors: processors,
+		periodSize: int(deviceConfig.PeriodSizeInFrames),
+		periods:    int(deviceConfig.Periods),
+	}
+
+	device, err := ma.InitDevice(context.Context, deviceConfig, ma.DeviceCallbacks{
+		Data: d.process,
+	})
+
+	if err != nil {
+		d.closeContext()
+
+		return nil, err
+	}
+
+	d.device = device
+	d.sampleRate = int(device.SampleRate())
+
+	// miniaudio picks 10 ms and 3 periods for the low latency profile when they are 0.
+	if d.periodSize == 0 {
+		d.periodSize = d.sampleRate / 100
+	}
+	if d.periods == 0 {
+		d.periods = 3
+	}
+
+	return d, nil
+}
+
+// SampleRate returns the sample rate of the device in Hz.
+func (d *Duplex) SampleRate() int {
+	return d.sampleRate
+}
+
+// Start starts the processing and clears the statistics.
+func (d *Duplex) Start() error {
+	d.mu.Lock()
+	d.stats = Stats{}
+	d.processing = 0
+	d.mu.Unlock()
+
+	return d.device.Start()
+}
+
+// Stop stops the processing. It can be started again.
+func (d *Duplex) Stop() error {
+	return d.device.Stop()
+}
+
+// Close stops the processing and releases the devices.
+func (d *Duplex) Close() error {
+	d.device.Uninit()
+
+	return d.closeContext()
+}
+
+func (d *Duplex) closeContext() error {
+	err := d.context.Uninit()
+	d.context.Free()
+
+	return err
+}
+
+// SetProcessors replaces the processors. The number of the processors must be the same as the channels.
+func (d *Duplex) SetProcessors(processors ...equalizer.Processor) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(processors) != len(d.processors) {
+		return fmt.Errorf("%w: %d processors for %d channels", ErrChannels, len(processors), len(d.processors))
+	}
+
+	d.processors = processors
+
+	return nil
+}
+
+// Update calls f while the processors are not processing, so f can modify them safely, e.g. with ParametricEQ.SetBand.
+func (d *Duplex) Update(f func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f()
+}
+
+// SetBypass enables or disables the processors. The bypassed input is played as it is.
+func (d *Duplex) SetBypass(bypass bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.bypass = bypass
+}
+
+// Stats returns the statistics of the callbacks.
+func (d *Duplex) Stats() Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := d.stats
+
+	if stats.Callbacks > 0 {
+		stats.AverageProcessing = d.processing / time.Duration(stats.Callbacks)
+	}
+
+	stats.Period = duration(d.periodSize, d.sampleRate)
+	stats.Latency = duration(d.periodSize*(1+d.periods)+processorLatency(d.processors), d.sampleRate)
+
+	return stats
+}
+
+// process is the data callback of the device.
+func (d *Duplex) process(output, input []byte, frameCount uint32) {
+	start := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	channels := len(d.processors)
+	frames := int(frameCount)
+	n := frames * channels
+
+	if len(d.samples) < n {
+		d.samples = make([]float64, n)
+		d.channel = make([]float64, frames)
+	}
+
+	samples := d.samples[:n]
+	silent := len(input) < len(output)
+
+	if silent {
+		for i := range output {
+			output[i] = 0
+		}
+	} else {
+		pcm.F32LE.Decode(samples, input[:n*4])
+
+		if !d.bypass {
+			apply(d.processors, samples, d.channel[:frames])
+		}
+
+		// The float output is never clipped by Encode. The device clips it.
+		pcm.F32LE.Encode(output, samples, pcm.Clip)
+	}
+
+	elapsed := time.Since(start)
+
+	d.stats.Callbacks++
+	d.stats.Frames += int64(frames)
+	d.processing += elapsed
+
+	if elapsed > d.stats.MaxProcessing {
+		d.stats.MaxProcessing = elapsed
+	}
+	if silent || elapsed > duration(frames, d.sampleRate) {
+		d.stats.Underruns++
+	}
+}
+
+// apply applies the processors to the interleaved samples in place. The buffer holds one channel and has the length of the frames.
+func apply(processors []equalizer.Processor, samples, buffer []float64) {
+	channels := len(processors)
+
+	for c, processor := range processors {
+		if processor == nil {
+			continue
+		}
+		if p, ok := processor.(bufferProcessor); ok {
+			for i := range buffer {
+				buffer[i] = samples[i*channels+c]
+			}
+
+			p.ProcessBuffer(buffer)
+
+			for i := range buffer {
+				samples[i*channels+c] = buffer[i]
+			}
+
+			continue
+		}
+		for i := range buffer {
+			samples[i*channels+c] = processor.Apply(samples[i*channels+c])
+		}
+	}
+}
+
+// processorLatency returns the largest latency reported by the processors in frames.
+func processorLatency(processors []equalizer.Processor) int {
+	latency := 0
+
+	for _, processor := range processors {
+		if p, ok := processor.(latencyReporter); ok && p.Latency() > latency {
+			latency = p.Latency()
+		}
+	}
+
+	return latency
+}
+
+// duration converts the number of the frames to the time.
+func duration(frames, sampleRate int) time.Duration {
+	if sampleRate == 0 {
+		return 0
+	}
+
+	return time.Duration(frames) * time.Second / time.Duration(sampleRate)
+}
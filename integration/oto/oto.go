@@ -0,0 +1,226 @@
+// Package oto feeds the equalized PCM to the player of github.com/ebitengine/oto.
+//
+// oto.Player pulls the PCM from the io.Reader, so the Reader of this package is passed to oto.Context.NewPlayer.
+// This package does not import oto and oto is not required to build the equalizer. See the player directory for the runnable example.
+package oto
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pcm"
+)
+
+// ErrChannels is returned when the number of the processors is different from the number of the channels.
+var ErrChannels = errors.New("oto: number of the processors does not match the channels")
+
+// bufferProcessor is implemented by the processors which can process the buffer faster than calling Apply for each sample, e.g. equalizer.Chain.
+type bufferProcessor interface {
+	ProcessBuffer(buffer []float64)
+}
+
+// resetter is implemented by the processors which have the state variables.
+type resetter interface {
+	Reset()
+}
+
+// Reader reads the interleaved PCM from the source, applies the processor of each channel and returns the PCM in the output format.
+// The processors can be replaced or updated while the player is reading, so the changes of the equalizer are heard immediately.
+type Reader struct {
+	source io.Reader
+	input  pcm.Format
+	output pcm.Format
+
+	// mu guards the processors while the player calls Read from its own goroutine.
+	mu         sync.Mutex
+	processors []equalizer.Processor
+	bypass     bool
+
+	// raw holds the bytes read from the source and partial is the number of the bytes of the incomplete frame at the beginning.
+	raw     []byte
+	partial int
+	samples []float64
+	channel []float64
+	encoded []byte
+	pending []byte
+	err     error
+}
+
+// NewReader returns the reader.
+//
+// Parameters:
+//
+//     - source ... Interleaved PCM. e.g. the file positioned at the data chunk of the WAV file
+//     - input ... Sample format of the source. e.g. pcm.S24LE
+//     - output ... Sample format passed to oto. It must match NewContextOptions.Format, i.e. pcm.S16LE, pcm.F32LE or 8 bit unsigned.
+//     - processors ... Processor of each channel. e.g. *equalizer.ParametricEQ for the left and the right channels
+//
+// NOTE: The number of the processors is the number of the channels. The nil processor passes the channel through.
+// The processors have the state variables, so pass the different instances to each channel.
+func NewReader(source io.Reader, input, output pcm.Format, processors ...equalizer.Processor) (*Reader, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if err := output.Validate(); err != nil {
+		return nil, err
+	}
+	if len(processors) == 0 {
+		return nil, fmt.Errorf("%w: no channels", ErrChannels)
+	}
+
+	return &Reader{
+		source:     source,
+		input:      input,
+		output:     output,
+		processors: processors,
+	}, nil
+}
+
+// Channels returns the number of the channels.
+func (r *Reader) Channels() int {
+	return len(r.processors)
+}
+
+// SetProcessors replaces the processors. The number of the processors must be the same as the channels.
+func (r *Reader) SetProcessors(processors ...equalizer.Processor) error {
+	if len(processors) != len(r.processors) {
+		return fmt.Errorf("%w: %d processors for %d channels", ErrChannels, len(processors), len(r.processors))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.processors = processors
+
+	return nil
+}
+
+// Update calls f while the processors are not processing, so f can modify them safely, e.g. with ParametricEQ.SetBand.
+func (r *Reader) Update(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f()
+}
+
+// SetBypass enables or disables the processors. The bypassed signal is only converted to the output format.
+// The processors are reset when they are enabled again, so the old state does not click.
+func (r *Reader) SetBypass(bypass bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bypass && !bypass {
+		for _, processor := range r.processors {
+			if p, ok := processor.(resetter); ok {
+				p.Reset()
+			}
+		}
+	}
+
+	r.bypass = bypass
+}
+
+// Bypass returns true when the processors are bypassed.
+func (r *Reader) Bypass() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.bypass
+}
+
+// Read fills p with the processed PCM. The incomplete frame at the end of the source is dropped.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		r.fill(len(p))
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+// fill reads the frames from the source which are converted to about size bytes and processes them.
+func (r *Reader) fill(size int) {
+	channels := len(r.processors)
+	frameSize := r.input.Size() * channels
+	frames := size / (r.output.Size() * channels)
+
+	if frames < 1 {
+		frames = 1
+	}
+	if len(r.raw) < frames*frameSize {
+		raw := make([]byte, frames*frameSize)
+		copy(raw, r.raw[:r.partial])
+		r.raw = raw
+	}
+
+	n, err := r.source.Read(r.raw[r.partial : frames*frameSize])
+	n += r.partial
+	frames = n / frameSize
+
+	if err != nil {
+		r.err = err
+	}
+	if frames == 0 {
+		r.partial = n
+
+		return
+	}
+	if len(r.samples) < frames*channels {
+		r.samples = make([]float64, frames*channels)
+		r.channel = make([]float64, frames)
+		r.encoded = make([]byte, frames*channels*r.output.Size())
+	}
+
+	samples := r.samples[:frames*channels]
+	r.input.Decode(samples, r.raw[:frames*frameSize])
+	r.partial = copy(r.raw, r.raw[frames*frameSize:n])
+	r.process(samples, frames)
+
+	// The integer output is clipped, which is what the sound card does.
+	m, _ := r.output.Encode(r.encoded, samples, pcm.Clip)
+	r.pending = r.encoded[:m]
+}
+
+// process applies the processors to the interleaved samples in place.
+func (r *Reader) process(samples []float64, frames int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bypass {
+		return
+	}
+
+	channels := len(r.processors)
+	buffer := r.channel[:frames]
+
+	for c, processor := range r.processors {
+		if processor == nil {
+			continue
+		}
+		if p, ok := processor.(bufferProcessor); ok {
+			for i := range buffer {
+				buffer[i] = samples[i*channels+c]
+			}
+
+			p.ProcessBuffer(buffer)
+
+			for i := range buffer {
+				samples[i*channels+c] = buffer[i]
+			}
+
+			continue
+		}
+		for i := 0; i < frames; i++ {
+			samples[i*channels+c] = processor.Apply(samples[i*channels+c])
+		}
+	}
+}
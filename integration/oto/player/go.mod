@@ -0,0 +1,17 @@
+module github.com/moutend/go-equalizer/integration/oto/player
+
+go 1.25.0
+
+require (
+	github.com/ebitengine/oto/v3 v3.5.1
+	github.com/moutend/go-equalizer v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/ebitengine/purego v0.11.0 // indirect
+	github.com/jfreymuth/pulse v0.1.3 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+)
+
+// The root module has no published release yet, so it is always built from the local copy.
+replace github.com/moutend/go-equalizer => ../../..
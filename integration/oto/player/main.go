@@ -0,0 +1,191 @@
+// The player plays the WAV file through the peaking filter and changes the filter while playing.
+//
+// Usage:
+//
+//     go run . -frequency 1000 -q 1 -gain 6 music.wav
+//
+// Type the following commands and press Enter while playing:
+//
+//     - f <Hz> ... Set the frequency.
+//     - q <Q> ... Set the band width.
+//     - g <dB> ... Set the gain.
+//     - b ... Toggle the bypass to compare with the original.
+//     - x ... Quit.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	otov3 "github.com/ebitengine/oto/v3"
+	"github.com/moutend/go-equalizer/integration/oto"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pcm"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	band := equalizer.Band{Name: equalizer.Peaking}
+
+	flags := flag.NewFlagSet("player", flag.ContinueOnError)
+	flags.Float64Var(&band.Frequency, "frequency", 1000.0, "center `frequency` in Hz")
+	flags.Float64Var(&band.Q, "q", 1.0, "band width in octaves")
+	flags.Float64Var(&band.Gain, "gain", 6.0, "`gain` in dB")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: player [flags] input.wav")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	reader, err := wav.NewReader(file)
+
+	if err != nil {
+		return err
+	}
+
+	format := reader.Format
+	sampleRate := float64(format.SampleRate)
+
+	if err := equalizer.CheckFrequency(sampleRate, band.Frequency); err != nil {
+		return err
+	}
+
+	equalizers := make([]*equalizer.ParametricEQ, format.Channels)
+	processors := make([]equalizer.Processor, format.Channels)
+
+	for i := range equalizers {
+		equalizers[i] = equalizer.NewParametricEQ(sampleRate, band)
+		processors[i] = equalizers[i]
+	}
+
+	// The file is positioned at the first sample, and the chunks after the data chunk must not be played. The stream
+	// whose size is unknown is played until the end of the file.
+	var source io.Reader = file
+
+	if frames := reader.Frames(); frames >= 0 {
+		source = io.LimitReader(file, frames*int64(format.Channels*format.BitsPerSample/8))
+	}
+
+	input := pcm.Format{BitsPerSample: format.BitsPerSample, Float: format.Float}
+	stream, err := oto.NewReader(source, input, pcm.F32LE, processors...)
+
+	if err != nil {
+		return err
+	}
+
+	context, ready, err := otov3.NewContext(&otov3.NewContextOptions{
+		SampleRate:   format.SampleRate,
+		ChannelCount: format.Channels,
+		Format:       otov3.FormatFloat32LE,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	<-ready
+
+	player := context.NewPlayer(stream)
+	player.Play()
+
+	commands := make(chan string)
+
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+
+		for scanner.Scan() {
+			commands <- scanner.Text()
+		}
+	}()
+
+	for player.IsPlaying() {
+		select {
+		case command := <-commands:
+			if strings.TrimSpace(command) == "x" {
+				return nil
+			}
+			if err := execute(command, stream, equalizers, &band); err != nil {
+				log.Println(err)
+			}
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return player.Err()
+}
+
+// execute applies the command to the band of every channel.
+func execute(command string, stream *oto.Reader, equalizers []*equalizer.ParametricEQ, band *equalizer.Band) error {
+	fields := strings.Fields(command)
+
+	if len(fields) == 1 && fields[0] == "b" {
+		stream.SetBypass(!stream.Bypass())
+		log.Printf("bypass: %v", stream.Bypass())
+
+		return nil
+	}
+	if len(fields) != 2 {
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	value, err := strconv.ParseFloat(fields[1], 64)
+
+	if err != nil {
+		return err
+	}
+
+	updated := *band
+
+	switch fields[0] {
+	case "f":
+		updated.Frequency = value
+	case "q":
+		updated.Q = value
+	case "g":
+		updated.Gain = value
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	if err := equalizer.CheckFrequency(equalizers[0].SampleRate(), updated.Frequency); err != nil {
+		return err
+	}
+
+	*band = updated
+
+	// SetBand keeps the state variables, so the change does not click.
+	stream.Update(func() {
+		for _, e := range equalizers {
+			e.SetBand(0, updated)
+		}
+	})
+
+	log.Printf("frequency: %g Hz, q: %g, gain: %g dB", band.Frequency, band.Q, band.Gain)
+
+	return nil
+}
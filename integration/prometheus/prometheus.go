@@ -0,0 +1,87 @@
+// Package prometheus exports the stats of equalizer.Metrics as the Prometheus metrics, so the long-running services
+// can be monitored with the same dashboards as the other services.
+//
+// This package is the separate module which depends on github.com/prometheus/client_golang.
+package prometheus
+
+import (
+	"sync"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector implements prometheus.Collector. Each metrics is labeled with the name given to Add.
+type Collector struct {
+	samples        *prometheus.Desc
+	blocks         *prometheus.Desc
+	clips          *prometheus.Desc
+	peak           *prometheus.Desc
+	processingTime *prometheus.Desc
+	maxBlockTime   *prometheus.Desc
+
+	mu      sync.Mutex
+	metrics map[string]*equalizer.Metrics
+}
+
+// NewCollector returns the collector. Register it with prometheus.MustRegister.
+//
+// Parameters:
+//
+//     - namespace ... Prefix of the metric names. e.g. "eqd"
+func NewCollector(namespace string) *Collector {
+	labels := []string{"processor"}
+
+	return &Collector{
+		samples:        prometheus.NewDesc(prometheus.BuildFQName(namespace, "equalizer", "samples_total"), "Number of the processed samples.", labels, nil),
+		blocks:         prometheus.NewDesc(prometheus.BuildFQName(namespace, "equalizer", "blocks_total"), "Number of the processed buffers.", labels, nil),
+		clips:          prometheus.NewDesc(prometheus.BuildFQName(namespace, "equalizer", "clips_total"), "Number of the output samples whose magnitude exceeds 1.0.", labels, nil),
+		peak:           prometheus.NewDesc(prometheus.BuildFQName(namespace, "equalizer", "peak"), "Largest magnitude of the output.", labels, nil),
+		processingTime: prometheus.NewDesc(prometheus.BuildFQName(namespace, "equalizer", "processing_seconds_total"), "Total time spent in processing the buffers.", labels, nil),
+		maxBlockTime:   prometheus.NewDesc(prometheus.BuildFQName(namespace, "equalizer", "block_seconds_max"), "Longest time spent in processing one buffer.", labels, nil),
+		metrics:        map[string]*equalizer.Metrics{},
+	}
+}
+
+// Add exports the metrics with the name, e.g. "left" and "right". The metrics of the same name is replaced.
+func (c *Collector) Add(name string, metrics *equalizer.Metrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metrics[name] = metrics
+}
+
+// Remove stops exporting the metrics of the name.
+func (c *Collector) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.metrics, name)
+}
+
+// Describe sends the descriptions of the metrics.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.samples
+	ch <- c.blocks
+	ch <- c.clips
+	ch <- c.peak
+	ch <- c.processingTime
+	ch <- c.maxBlockTime
+}
+
+// Collect sends the current stats.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, metrics := range c.metrics {
+		stats := metrics.Stats()
+
+		ch <- prometheus.MustNewConstMetric(c.samples, prometheus.CounterValue, float64(stats.Samples), name)
+		ch <- prometheus.MustNewConstMetric(c.blocks, prometheus.CounterValue, float64(stats.Blocks), name)
+		ch <- prometheus.MustNewConstMetric(c.clips, prometheus.CounterValue, float64(stats.Clips), name)
+		ch <- prometheus.MustNewConstMetric(c.peak, prometheus.GaugeValue, stats.Peak, name)
+		ch <- prometheus.MustNewConstMetric(c.processingTime, prometheus.CounterValue, stats.ProcessingTime.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(c.maxBlockTime, prometheus.GaugeValue, stats.MaxBlockTime.Seconds(), name)
+	}
+}
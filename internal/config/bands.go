@@ -0,0 +1,124 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// DefaultQ is used when the Q value is omitted.
+const DefaultQ = 1.0 / math.Sqrt2
+
+// filterFlags are the command line flags which append the band to the chain.
+var filterFlags = []struct {
+	flag string
+	name equalizer.FilterName
+	gain bool
+}{
+	{"lowpass", equalizer.LowPass, false},
+	{"highpass", equalizer.HighPass, false},
+	{"allpass", equalizer.AllPass, false},
+	{"bandpass", equalizer.BandPass, false},
+	{"notch", equalizer.BandReject, false},
+	{"lowshelf", equalizer.LowShelf, true},
+	{"highshelf", equalizer.HighShelf, true},
+	{"peak", equalizer.Peaking, true},
+}
+
+// bandFlag appends the band to the shared list, so the order of the bands follows the order of the flags.
+type bandFlag struct {
+	name  equalizer.FilterName
+	gain  bool
+	bands *[]equalizer.Band
+
+	// registered is true for the designers registered by equalizer.Register, whose gain is optional.
+	registered bool
+}
+
+func (b bandFlag) String() string {
+	return ""
+}
+
+func (b bandFlag) Set(value string) error {
+	band, err := parseBand(b.name, b.gain, b.registered, value)
+
+	if err != nil {
+		return err
+	}
+
+	*b.bands = append(*b.bands, band)
+
+	return nil
+}
+
+// AddBandFlags registers the filter flags which append the bands in the order they appear. The designers registered by
+// equalizer.Register are also added as the flags, so call it after they are registered.
+func AddBandFlags(flags *flag.FlagSet, bands *[]equalizer.Band) {
+	for _, f := range filterFlags {
+		usage := "append the " + f.flag + " filter `frequency[:q]`"
+
+		if f.gain {
+			usage = "append the " + f.flag + " filter `frequency:q:gain`"
+		}
+
+		flags.Var(bandFlag{name: f.name, gain: f.gain, bands: bands}, f.flag, usage)
+	}
+	for _, registered := range equalizer.Registered() {
+		// The built-in flags win, because redefining the flag panics.
+		if flags.Lookup(registered) != nil {
+			continue
+		}
+
+		name, _ := equalizer.Lookup(registered)
+		usage := "append the " + registered + " filter `frequency[:q[:gain]]`"
+
+		flags.Var(bandFlag{name: name, bands: bands, registered: true}, registered, usage)
+	}
+}
+
+// parseBand parses the band in the form of frequency[:q[:gain]]. The frequency is in Hz and the gain is in dB.
+// The gain is required when gain is true, and it is optional when registered is true.
+func parseBand(name equalizer.FilterName, gain, registered bool, value string) (equalizer.Band, error) {
+	fields := strings.Split(value, ":")
+	band := equalizer.Band{
+		Name: name,
+		Q:    DefaultQ,
+	}
+
+	if len(fields) > 3 || (!gain && !registered && len(fields) > 2) {
+		return band, fmt.Errorf("too many parameters in %q", value)
+	}
+	if gain && len(fields) != 3 {
+		return band, fmt.Errorf("%q must be frequency:q:gain", value)
+	}
+
+	values := make([]float64, len(fields))
+
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+
+		if err != nil {
+			return band, fmt.Errorf("invalid number %q in %q", field, value)
+		}
+
+		values[i] = v
+	}
+
+	band.Frequency = values[0]
+
+	if len(values) > 1 {
+		band.Q = values[1]
+	}
+	if len(values) > 2 {
+		band.Gain = values[2]
+	}
+	if band.Frequency <= 0.0 || band.Q <= 0.0 {
+		return band, fmt.Errorf("frequency and q must be positive in %q", value)
+	}
+
+	return band, nil
+}
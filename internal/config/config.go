@@ -0,0 +1,168 @@
+// Package config parses the filters given by the command line flags and the YAML config file, which are shared by the commands.
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the chain described in the YAML file, e.g.
+//
+//	filters:
+//	  - type: highpass
+//	    frequency: 80
+//	    q: 0.707
+//	  - type: peak
+//	    frequency: 2500
+//	    q: 1.4
+//	    gain: -3
+type config struct {
+	Filters []Filter `yaml:"filters"`
+}
+
+// Filter is one filter in the config file. The type is one of the filter flag names.
+type Filter struct {
+	Type      string   `yaml:"type" json:"type"`
+	Frequency float64  `yaml:"frequency" json:"frequency"`
+	Q         *float64 `yaml:"q" json:"q,omitempty"`
+	Gain      float64  `yaml:"gain" json:"gain"`
+}
+
+// Load reads the config file and returns the bands in the order of the filters.
+func Load(path string) ([]equalizer.Band, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bands, err := Parse(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return bands, nil
+}
+
+// AddFlag registers the --config flag and returns the pointer to the path.
+func AddFlag(flags *flag.FlagSet) *string {
+	return flags.String("config", "", "YAML `file` which describes the filters")
+}
+
+// With returns the bands in the config file followed by the bands given by the flags. The path may be empty.
+func With(path string, bands []equalizer.Band) ([]equalizer.Band, error) {
+	if path == "" {
+		return bands, nil
+	}
+
+	configured, err := Load(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(configured, bands...), nil
+}
+
+// Parse parses the YAML config. JSON is also accepted because it is the subset of YAML.
+func Parse(data []byte) ([]equalizer.Band, error) {
+	var c config
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	// Report the misspelled keys instead of ignoring them.
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return Bands(c.Filters)
+}
+
+// Bands converts the filters to the bands. The omitted Q value is DefaultQ.
+func Bands(filters []Filter) ([]equalizer.Band, error) {
+	bands := make([]equalizer.Band, len(filters))
+
+	for i, f := range filters {
+		name, ok := filterName(f.Type)
+
+		if !ok {
+			return nil, fmt.Errorf("filters[%d]: unknown type %q", i, f.Type)
+		}
+
+		band := equalizer.Band{
+			Name:      name,
+			Frequency: f.Frequency,
+			Q:         DefaultQ,
+			Gain:      f.Gain,
+		}
+
+		if f.Q != nil {
+			band.Q = *f.Q
+		}
+		if band.Frequency <= 0.0 || band.Q <= 0.0 {
+			return nil, fmt.Errorf("filters[%d]: frequency and q must be positive", i)
+		}
+
+		bands[i] = band
+	}
+
+	return bands, nil
+}
+
+// Filters converts the bands to the filters. The bands which have no flag name, e.g. the custom filter, are given the empty type.
+func Filters(bands []equalizer.Band) []Filter {
+	filters := make([]Filter, len(bands))
+
+	for i, band := range bands {
+		q := band.Q
+
+		filters[i] = Filter{
+			Type:      TypeName(band.Name),
+			Frequency: band.Frequency,
+			Q:         &q,
+			Gain:      band.Gain,
+		}
+	}
+
+	return filters
+}
+
+// TypeName returns the filter flag name of the filter name, e.g. "peak" for equalizer.Peaking, or the name registered by equalizer.Register.
+func TypeName(name equalizer.FilterName) string {
+	for _, f := range filterFlags {
+		if f.name == name {
+			return f.flag
+		}
+	}
+
+	return equalizer.RegisteredName(name)
+}
+
+// filterName returns the filter name of the type, which is the filter flag name or the name registered by equalizer.Register.
+func filterName(typ string) (equalizer.FilterName, bool) {
+	if name, ok := filterNames[typ]; ok {
+		return name, true
+	}
+
+	return equalizer.Lookup(typ)
+}
+
+// filterNames maps the filter flag names to the filter names.
+var filterNames = func() map[string]equalizer.FilterName {
+	names := map[string]equalizer.FilterName{}
+
+	for _, f := range filterFlags {
+		names[f.flag] = f.name
+	}
+
+	return names
+}()
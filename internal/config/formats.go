@@ -0,0 +1,30 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// RawFormats maps the sample format names of ffmpeg and sox to the WAV formats without the rate and the channels.
+var RawFormats = map[string]wav.Format{
+	"u8":    {BitsPerSample: 8},
+	"s16le": {BitsPerSample: 16},
+	"s24le": {BitsPerSample: 24},
+	"s32le": {BitsPerSample: 32},
+	"f32le": {BitsPerSample: 32, Float: true},
+	"f64le": {BitsPerSample: 64, Float: true},
+}
+
+// RawFormatNames returns the sorted names of the raw formats for the usage.
+func RawFormatNames() []string {
+	names := make([]string, 0, len(RawFormats))
+
+	for name := range RawFormats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,116 @@
+// Package fft provides the radix-2 fast Fourier transform used by the packages in this module.
+package fft
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Plan holds the precomputed tables for the transform of the fixed size.
+type Plan struct {
+	size     int
+	twiddles []complex128
+	reversed []int
+}
+
+// NewPlan returns the plan for the transform of the size. The size must be the power of 2.
+func NewPlan(size int) *Plan {
+	bits := 0
+
+	for 1<<uint(bits) < size {
+		bits++
+	}
+
+	p := &Plan{
+		size:     size,
+		twiddles: make([]complex128, size/2),
+		reversed: make([]int, size),
+	}
+
+	for i := range p.twiddles {
+		p.twiddles[i] = cmplx.Exp(complex(0.0, -2.0*math.Pi*float64(i)/float64(size)))
+	}
+	for i := range p.reversed {
+		r := 0
+
+		for b := 0; b < bits; b++ {
+			r |= (i >> uint(b) & 1) << uint(bits-1-b)
+		}
+
+		p.reversed[i] = r
+	}
+
+	return p
+}
+
+// Size returns the size of the transform.
+func (p *Plan) Size() int {
+	return p.size
+}
+
+// Forward computes the forward transform of the x in place.
+func (p *Plan) Forward(x []complex128) {
+	p.transform(x, false)
+}
+
+// Inverse computes the inverse transform of the x in place. The result is scaled by 1/size.
+func (p *Plan) Inverse(x []complex128) {
+	p.transform(x, true)
+
+	scale := complex(1.0/float64(p.size), 0.0)
+
+	for i := range x {
+		x[i] *= scale
+	}
+}
+
+func (p *Plan) transform(x []complex128, inverse bool) {
+	n := p.size
+
+	for i, r := range p.reversed {
+		if i < r {
+			x[i], x[r] = x[r], x[i]
+		}
+	}
+	for length := 2; length <= n; length <<= 1 {
+		half := length / 2
+		step := n / length
+
+		for start := 0; start < n; start += length {
+			for k := 0; k < half; k++ {
+				w := p.twiddles[k*step]
+
+				if inverse {
+					w = cmplx.Conj(w)
+				}
+
+				a := x[start+k]
+				b := x[start+k+half] * w
+
+				x[start+k] = a + b
+				x[start+k+half] = a - b
+			}
+		}
+	}
+}
+
+// Forward computes the forward transform of the x in place. The length of the x must be the power of 2.
+func Forward(x []complex128) {
+	NewPlan(len(x)).Forward(x)
+}
+
+// Inverse computes the inverse transform of the x in place. The length of the x must be the power of 2.
+func Inverse(x []complex128) {
+	NewPlan(len(x)).Inverse(x)
+}
+
+// NextPowerOfTwo returns the smallest power of 2 which is greater than or equal to the n.
+func NextPowerOfTwo(n int) int {
+	size := 1
+
+	for size < n {
+		size <<= 1
+	}
+
+	return size
+}
@@ -0,0 +1,2 @@
+// Package analysis provides the frequency domain analysis tools for the audio signal.
+package analysis
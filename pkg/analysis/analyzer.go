@@ -0,0 +1,226 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/moutend/go-equalizer/internal/fft"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// Averaging represents how the analyzer combines the spectra of the frames.
+type Averaging int
+
+// Averaging constants are averaging modes.
+const (
+	// NoAveraging shows the latest frame.
+	NoAveraging Averaging = iota
+
+	// ExponentialAveraging weights the recent frames more.
+	ExponentialAveraging
+
+	// LinearAveraging weights all frames equally.
+	LinearAveraging
+
+	// PeakHold keeps the maximum of all frames.
+	PeakHold
+)
+
+// AnalyzerOptions controls the spectrum analyzer.
+type AnalyzerOptions struct {
+	// FFTSize is the FFT size. It is rounded up to the power of 2. The default is 4096.
+	FFTSize int
+
+	// Overlap is the overlap ratio of the frames between 0 and 1. The default is 0.5.
+	Overlap float64
+
+	Window    Window
+	Averaging Averaging
+
+	// Smoothing is the weight of the previous spectrum between 0 and 1 used by ExponentialAveraging. The default is 0.8.
+	Smoothing float64
+
+	// MinFrequency and MaxFrequency are the range of the logarithmic frequency grid in Hz. The defaults are 20 Hz and 20 kHz.
+	MinFrequency float64
+	MaxFrequency float64
+
+	// Points is the number of the frequencies on the grid. The default is 200.
+	Points int
+}
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o AnalyzerOptions) withDefaults(sampleRate float64) AnalyzerOptions {
+	if o.FFTSize <= 0 {
+		o.FFTSize = 4096
+	}
+	if o.Overlap <= 0.0 || o.Overlap >= 1.0 {
+		o.Overlap = 0.5
+	}
+	if o.Smoothing <= 0.0 || o.Smoothing >= 1.0 {
+		o.Smoothing = 0.8
+	}
+	if o.MinFrequency <= 0.0 {
+		o.MinFrequency = 20.0
+	}
+	if o.MaxFrequency <= 0.0 || o.MaxFrequency > sampleRate/2.0 {
+		o.MaxFrequency = math.Min(20000.0, sampleRate/2.0)
+	}
+	if o.Points <= 1 {
+		o.Points = 200
+	}
+
+	o.FFTSize = fft.NextPowerOfTwo(o.FFTSize)
+
+	return o
+}
+
+// Analyzer is the streaming spectrum analyzer. The full scale sine wave is shown as 0 dB.
+type Analyzer struct {
+	sampleRate float64
+	options    AnalyzerOptions
+	plan       *fft.Plan
+	window     []float64
+	hop        int
+
+	// input samples waiting for the next frame
+	buffer []float64
+	frame  []complex128
+
+	// power spectrum normalized to the sine amplitude
+	power  []float64
+	frames int
+}
+
+// NewAnalyzer returns the spectrum analyzer.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - options ... Analyzer options. The zero value uses the defaults.
+func NewAnalyzer(sampleRate float64, options AnalyzerOptions) *Analyzer {
+	options = options.withDefaults(sampleRate)
+	size := options.FFTSize
+	hop := int(float64(size) * (1.0 - options.Overlap))
+
+	if hop < 1 {
+		hop = 1
+	}
+
+	return &Analyzer{
+		sampleRate: sampleRate,
+		options:    options,
+		plan:       fft.NewPlan(size),
+		window:     options.Window.Coefficients(size),
+		hop:        hop,
+		buffer:     make([]float64, 0, size),
+		frame:      make([]complex128, size),
+		power:      make([]float64, size/2+1),
+	}
+}
+
+// Write feeds the samples to the analyzer. The spectrum is updated every time the frame is filled.
+func (a *Analyzer) Write(samples []float64) {
+	size := a.options.FFTSize
+
+	for len(samples) > 0 {
+		n := size - len(a.buffer)
+
+		if n > len(samples) {
+			n = len(samples)
+		}
+
+		a.buffer = append(a.buffer, samples[:n]...)
+		samples = samples[n:]
+
+		if len(a.buffer) == size {
+			a.analyze()
+
+			// Keep the overlapping part for the next frame.
+			a.buffer = a.buffer[:copy(a.buffer, a.buffer[a.hop:])]
+		}
+	}
+}
+
+// Frames returns the number of the analyzed frames.
+func (a *Analyzer) Frames() int {
+	return a.frames
+}
+
+// Spectrum returns the level in dB on the logarithmic frequency grid. Each point shows the largest bin between the neighboring points.
+func (a *Analyzer) Spectrum() equalizer.Response {
+	o := a.options
+	binWidth := a.sampleRate / float64(o.FFTSize)
+	response := make(equalizer.Response, o.Points)
+	ratio := math.Pow(o.MaxFrequency/o.MinFrequency, 1.0/float64(o.Points-1))
+
+	for i := range response {
+		frequency := o.MinFrequency * math.Pow(ratio, float64(i))
+		lower := int(math.Ceil(frequency / math.Sqrt(ratio) / binWidth))
+		upper := int(math.Floor(frequency * math.Sqrt(ratio) / binWidth))
+		power := 0.0
+
+		if lower <= upper {
+			for k := lower; k <= upper && k < len(a.power); k++ {
+				power = math.Max(power, a.power[k])
+			}
+		} else {
+			// No bin in the band, interpolate the neighboring bins.
+			position := frequency / binWidth
+			k := int(position)
+
+			if k+1 < len(a.power) {
+				t := position - float64(k)
+				power = a.power[k] + (a.power[k+1]-a.power[k])*t
+			}
+		}
+
+		response[i] = equalizer.ResponsePoint{
+			Frequency: frequency,
+			Gain:      10.0 * math.Log10(power+1e-30),
+		}
+	}
+
+	return response
+}
+
+// Reset clears the buffered samples and the averaged spectrum.
+func (a *Analyzer) Reset() {
+	a.buffer = a.buffer[:0]
+	a.frames = 0
+
+	for k := range a.power {
+		a.power[k] = 0.0
+	}
+}
+
+// analyze computes the spectrum of the buffered frame and averages it.
+func (a *Analyzer) analyze() {
+	gain := 0.0
+
+	for i, x := range a.buffer {
+		a.frame[i] = complex(x*a.window[i], 0.0)
+		gain += a.window[i]
+	}
+
+	a.plan.Forward(a.frame)
+	a.frames++
+
+	for k := range a.power {
+		// The peak bin of the sine of the amplitude A is A/2*sum(w).
+		magnitude := 2.0 * cmplx.Abs(a.frame[k]) / gain
+		p := magnitude * magnitude
+
+		switch a.options.Averaging {
+		case ExponentialAveraging:
+			if a.frames > 1 {
+				p = a.options.Smoothing*a.power[k] + (1.0-a.options.Smoothing)*p
+			}
+		case LinearAveraging:
+			p = a.power[k] + (p-a.power[k])/float64(a.frames)
+		case PeakHold:
+			p = math.Max(p, a.power[k])
+		}
+
+		a.power[k] = p
+	}
+}
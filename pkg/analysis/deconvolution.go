@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/moutend/go-equalizer/internal/fft"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// TransferFunction is the system response measured with the sweep.
+type TransferFunction struct {
+	SampleRate float64
+
+	// ImpulseResponse is the causal part of the system impulse response.
+	ImpulseResponse []float64
+
+	minFrequency float64
+	maxFrequency float64
+	spectrum     []complex128
+}
+
+// Deconvolve returns the transfer function of the system from the sweep played through the system and its recording.
+// The spectral division is regularized outside the frequency range of the sweep, so the noise there is not amplified.
+//
+// Parameters:
+//
+//     - sweep ... Sweep played through the system, e.g. generated by generator.GenerateSweep.
+//     - recording ... Recorded response of the system. It should include the decay after the sweep.
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - minFrequency ... Start frequency of the sweep in Hz.
+//     - maxFrequency ... End frequency of the sweep in Hz.
+//
+// NOTE: For the exponential sweep, the harmonic distortion appears before the linear impulse response and it is removed from the ImpulseResponse.
+func Deconvolve(sweep, recording []float64, sampleRate, minFrequency, maxFrequency float64) *TransferFunction {
+	size := fft.NextPowerOfTwo(len(sweep) + len(recording))
+	plan := fft.NewPlan(size)
+
+	x := make([]complex128, size)
+	y := make([]complex128, size)
+
+	for i, v := range sweep {
+		x[i] = complex(v, 0.0)
+	}
+	for i, v := range recording {
+		y[i] = complex(v, 0.0)
+	}
+
+	plan.Forward(x)
+	plan.Forward(y)
+
+	// Regularization: tiny inside the sweep range, large outside.
+	energy := 0.0
+	count := 0
+
+	for k := 0; k <= size/2; k++ {
+		frequency := float64(k) * sampleRate / float64(size)
+
+		if frequency >= minFrequency && frequency <= maxFrequency {
+			energy += real(x[k] * cmplx.Conj(x[k]))
+			count++
+		}
+	}
+	if count > 0 {
+		energy /= float64(count)
+	}
+
+	h := make([]complex128, size)
+
+	for k := range h {
+		bin := k
+
+		if bin > size/2 {
+			bin = size - k
+		}
+
+		frequency := float64(bin) * sampleRate / float64(size)
+		epsilon := 1e-6 * energy
+
+		if frequency < minFrequency || frequency > maxFrequency {
+			epsilon = energy
+		}
+
+		h[k] = y[k] * cmplx.Conj(x[k]) / complex(real(x[k]*cmplx.Conj(x[k]))+epsilon, 0.0)
+	}
+
+	t := &TransferFunction{
+		SampleRate:   sampleRate,
+		minFrequency: minFrequency,
+		maxFrequency: maxFrequency,
+		spectrum:     append([]complex128{}, h...),
+	}
+
+	plan.Inverse(h)
+
+	t.ImpulseResponse = make([]float64, size/2)
+
+	for i := range t.ImpulseResponse {
+		t.ImpulseResponse[i] = real(h[i])
+	}
+
+	return t
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz. The nearest FFT bin is used.
+func (t *TransferFunction) FrequencyResponse(frequency float64) complex128 {
+	size := len(t.spectrum)
+	k := int(math.Round(frequency / t.SampleRate * float64(size)))
+
+	if k < 0 {
+		k = 0
+	}
+	if k > size/2 {
+		k = size / 2
+	}
+
+	return t.spectrum[k]
+}
+
+// Magnitude returns the magnitude response in dB at the FFT bins within the frequency range of the sweep.
+func (t *TransferFunction) Magnitude() equalizer.Response {
+	size := len(t.spectrum)
+	response := equalizer.Response{}
+
+	for k := 1; k <= size/2; k++ {
+		frequency := float64(k) * t.SampleRate / float64(size)
+
+		if frequency < t.minFrequency || frequency > t.maxFrequency {
+			continue
+		}
+
+		response = append(response, equalizer.ResponsePoint{
+			Frequency: frequency,
+			Gain:      20.0 * math.Log10(cmplx.Abs(t.spectrum[k])+1e-30),
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/moutend/go-equalizer/internal/fft"
+)
+
+// distortionBins is the number of the bins summed on each side of the harmonic peak.
+const distortionBins = 4
+
+// Distortion is the result of the harmonic distortion measurement.
+type Distortion struct {
+	// Fundamental is the frequency of the fundamental in Hz.
+	Fundamental float64
+
+	// Harmonics are the peak amplitudes of the fundamental (index 0), the 2nd harmonic (index 1) and so on.
+	Harmonics []float64
+
+	// THD is the ratio of the RMS sum of the harmonics to the fundamental.
+	THD float64
+
+	// THDN is the ratio of the RMS sum of everything except the fundamental and DC to the fundamental.
+	THDN float64
+}
+
+// HarmonicLevels returns the level of each harmonic in dB relative to the fundamental.
+func (d Distortion) HarmonicLevels() []float64 {
+	levels := make([]float64, len(d.Harmonics))
+
+	for i, amplitude := range d.Harmonics {
+		levels[i] = 20.0 * math.Log10(amplitude/d.Harmonics[0])
+	}
+
+	return levels
+}
+
+// THDPercent returns the THD in percent.
+func (d Distortion) THDPercent() float64 {
+	return d.THD * 100.0
+}
+
+// THDNDecibels returns the THD+N in dB.
+func (d Distortion) THDNDecibels() float64 {
+	return 20.0 * math.Log10(d.THDN)
+}
+
+// MeasureDistortion measures the harmonic distortion of the sine wave, e.g. the sine processed by the filter chain.
+//
+// Parameters:
+//
+//     - samples ... Processed sine wave. At least several thousand samples are recommended.
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - harmonics ... Number of the harmonics including the fundamental. e.g. 10
+//
+// NOTE: The fundamental is the largest peak of the spectrum. The harmonics above the Nyquist frequency are ignored. The zero value is returned when the samples are too short.
+func MeasureDistortion(samples []float64, sampleRate float64, harmonics int) Distortion {
+	size := fft.NextPowerOfTwo(len(samples))
+
+	if size > len(samples) {
+		size /= 2
+	}
+	if size < 4*(distortionBins+1) {
+		return Distortion{}
+	}
+
+	window := blackmanHarris(size)
+	spectrum := make([]complex128, size)
+
+	for i := range spectrum {
+		spectrum[i] = complex(samples[i]*window[i], 0.0)
+	}
+
+	fft.Forward(spectrum)
+
+	power := make([]float64, size/2+1)
+
+	for k := range power {
+		a := cmplx.Abs(spectrum[k])
+		power[k] = a * a
+	}
+
+	// Find the fundamental, skipping the DC.
+	peak := distortionBins + 1
+
+	for k := peak; k < len(power); k++ {
+		if power[k] > power[peak] {
+			peak = k
+		}
+	}
+
+	// Sum the power around the bin, which collects the energy spread by the window.
+	band := func(center int) float64 {
+		sum := 0.0
+
+		for k := center - distortionBins; k <= center+distortionBins; k++ {
+			if k > distortionBins && k < len(power) {
+				sum += power[k]
+			}
+		}
+
+		return sum
+	}
+
+	// Convert the power sum to the peak amplitude of the sine. By Parseval's theorem, the sum is size*A^2/4*sum(w^2).
+	energy := window2(window)
+	amplitude := func(sum float64) float64 {
+		return 2.0 * math.Sqrt(sum/(float64(size)*energy))
+	}
+
+	d := Distortion{
+		Fundamental: float64(peak) * sampleRate / float64(size),
+	}
+
+	fundamental := band(peak)
+	total := 0.0
+	harmonicPower := 0.0
+
+	for k := distortionBins + 1; k < len(power); k++ {
+		total += power[k]
+	}
+	for h := 1; h <= harmonics; h++ {
+		center := peak * h
+
+		if center+distortionBins >= len(power) {
+			break
+		}
+
+		p := band(center)
+		d.Harmonics = append(d.Harmonics, amplitude(p))
+
+		if h > 1 {
+			harmonicPower += p
+		}
+	}
+
+	d.THD = math.Sqrt(harmonicPower / fundamental)
+	d.THDN = math.Sqrt(math.Max(total-fundamental, 0.0) / fundamental)
+
+	return d
+}
+
+// window2 returns the sum of the squared window.
+func window2(window []float64) float64 {
+	sum := 0.0
+
+	for _, w := range window {
+		sum += w * w
+	}
+
+	return sum
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/moutend/go-equalizer/internal/fft"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// LongTermSpectrum returns the average power spectrum of the samples in dB with Welch's method, Hann window and 50% overlap.
+//
+// Parameters:
+//
+//     - samples ... Audio signal.
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - fftSize ... FFT size. It is rounded up to the power of 2. e.g. 4096
+//
+// NOTE: The DC bin is omitted. The signal shorter than the FFT size is padded with zeros.
+func LongTermSpectrum(samples []float64, sampleRate float64, fftSize int) equalizer.Response {
+	size := fft.NextPowerOfTwo(fftSize)
+	plan := fft.NewPlan(size)
+	window := hann(size)
+	hop := size / 2
+
+	power := make([]float64, size/2+1)
+	frame := make([]complex128, size)
+	frames := 0
+
+	for start := 0; start == 0 || start+size <= len(samples); start += hop {
+		for i := range frame {
+			x := 0.0
+
+			if start+i < len(samples) {
+				x = samples[start+i]
+			}
+
+			frame[i] = complex(x*window[i], 0.0)
+		}
+
+		plan.Forward(frame)
+
+		for k := range power {
+			a := cmplx.Abs(frame[k])
+			power[k] += a * a
+		}
+
+		frames++
+	}
+
+	response := make(equalizer.Response, 0, len(power)-1)
+
+	for k := 1; k < len(power); k++ {
+		response = append(response, equalizer.ResponsePoint{
+			Frequency: float64(k) * sampleRate / float64(size),
+			Gain:      10.0 * math.Log10(power[k]/float64(frames)+1e-30),
+		})
+	}
+
+	return response
+}
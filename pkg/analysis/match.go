@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// matchFFTSize is the FFT size used to analyze the long-term spectrum.
+const matchFFTSize = 8192
+
+// Match returns the parametric equalizer which makes the tonal balance of the source match the reference.
+// The long-term spectra of both signals are compared and the smoothed difference is fitted with AutoCorrect.
+//
+// Parameters:
+//
+//     - reference ... Signal whose tonal balance is the target.
+//     - source ... Signal to be corrected.
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - options ... Correction options. See equalizer.AutoCorrectOptions. The sample rate of the options is replaced with sampleRate.
+func Match(reference, source []float64, sampleRate float64, options equalizer.AutoCorrectOptions) (*equalizer.ParametricEQ, error) {
+	target := LongTermSpectrum(reference, sampleRate, matchFFTSize)
+	measured := LongTermSpectrum(source, sampleRate, matchFFTSize)
+
+	options.SampleRate = sampleRate
+
+	return equalizer.AutoCorrect(measured, target, options)
+}
+
+// MatchFiles is the same as Match but reads the reference and the source from the WAV files. The channels are mixed to mono before the analysis.
+func MatchFiles(referencePath, sourcePath string, options equalizer.AutoCorrectOptions) (*equalizer.ParametricEQ, error) {
+	referenceFormat, reference, err := wav.ReadFile(referencePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sourceFormat, source, err := wav.ReadFile(sourcePath)
+
+	if err != nil {
+		return nil, err
+	}
+	if referenceFormat.SampleRate != sourceFormat.SampleRate {
+		return nil, fmt.Errorf("analysis: sample rates differ: %d Hz and %d Hz", referenceFormat.SampleRate, sourceFormat.SampleRate)
+	}
+
+	return Match(mono(reference), mono(source), float64(sourceFormat.SampleRate), options)
+}
+
+// mono returns the average of the channels.
+func mono(channels [][]float64) []float64 {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	samples := make([]float64, len(channels[0]))
+
+	for _, channel := range channels {
+		for i := range samples {
+			samples[i] += channel[i] / float64(len(channels))
+		}
+	}
+
+	return samples
+}
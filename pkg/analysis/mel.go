@@ -0,0 +1,117 @@
+package analysis
+
+import "math"
+
+// HzToMel converts the frequency in Hz to the mel scale.
+func HzToMel(frequency float64) float64 {
+	return 2595.0 * math.Log10(1.0+frequency/700.0)
+}
+
+// MelToHz converts the mel scale to the frequency in Hz.
+func MelToHz(mel float64) float64 {
+	return 700.0 * (math.Pow(10.0, mel/2595.0) - 1.0)
+}
+
+// melFilter holds the triangular weights from the first FFT bin.
+type melFilter struct {
+	first   int
+	weights []float64
+}
+
+// MelBank is the mel-spaced triangular filter bank applied to the FFT magnitudes.
+type MelBank struct {
+	centers []float64
+	filters []melFilter
+}
+
+// NewMelBank returns the mel filter bank.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 16000.0
+//     - fftSize ... FFT size. The bank accepts fftSize/2+1 magnitudes.
+//     - bands ... Number of the mel bands. e.g. 40
+//     - minFrequency ... Lower edge of the lowest band in Hz. e.g. 0.0
+//     - maxFrequency ... Upper edge of the highest band in Hz. e.g. 8000.0
+//
+// NOTE: Each triangle peaks at 1.0 on its center frequency.
+func NewMelBank(sampleRate float64, fftSize, bands int, minFrequency, maxFrequency float64) *MelBank {
+	m := &MelBank{
+		centers: make([]float64, bands),
+		filters: make([]melFilter, bands),
+	}
+
+	// Edge frequencies of the triangles which are spaced equally on the mel scale.
+	edges := make([]float64, bands+2)
+	minMel := HzToMel(minFrequency)
+	maxMel := HzToMel(maxFrequency)
+
+	for i := range edges {
+		edges[i] = MelToHz(minMel + (maxMel-minMel)*float64(i)/float64(bands+1))
+	}
+
+	binWidth := sampleRate / float64(fftSize)
+	bins := fftSize/2 + 1
+
+	for i := 0; i < bands; i++ {
+		lower, center, upper := edges[i], edges[i+1], edges[i+2]
+		m.centers[i] = center
+
+		first := int(math.Ceil(lower / binWidth))
+		last := int(math.Floor(upper / binWidth))
+
+		if last >= bins {
+			last = bins - 1
+		}
+
+		filter := melFilter{first: first}
+
+		for k := first; k <= last; k++ {
+			frequency := float64(k) * binWidth
+			weight := 0.0
+
+			if frequency <= center {
+				weight = (frequency - lower) / (center - lower)
+			} else {
+				weight = (upper - frequency) / (upper - center)
+			}
+
+			filter.weights = append(filter.weights, math.Max(weight, 0.0))
+		}
+
+		m.filters[i] = filter
+	}
+
+	return m
+}
+
+// Centers returns the center frequency of each band in Hz.
+func (m *MelBank) Centers() []float64 {
+	return m.centers
+}
+
+// Apply returns the weighted sum of the magnitudes for each band. Pass the power spectrum instead of the magnitudes to get the mel power.
+func (m *MelBank) Apply(magnitudes []float64) []float64 {
+	return m.ApplyTo(make([]float64, len(m.filters)), magnitudes)
+}
+
+// ApplyTo is the same as Apply but stores the result to the dst, which must have the length of the number of the bands.
+func (m *MelBank) ApplyTo(dst, magnitudes []float64) []float64 {
+	for i, filter := range m.filters {
+		sum := 0.0
+
+		for j, weight := range filter.weights {
+			k := filter.first + j
+
+			if k >= len(magnitudes) {
+				break
+			}
+
+			sum += weight * magnitudes[k]
+		}
+
+		dst[i] = sum
+	}
+
+	return dst
+}
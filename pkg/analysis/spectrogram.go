@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"math/cmplx"
+
+	"github.com/moutend/go-equalizer/internal/fft"
+)
+
+// SpectrogramOptions controls the short-time Fourier transform.
+type SpectrogramOptions struct {
+	// FFTSize is the FFT size. It is rounded up to the power of 2. The default is 1024.
+	FFTSize int
+
+	// Hop is the number of the samples between the frames. The default is FFTSize/4.
+	Hop int
+
+	Window Window
+}
+
+// Spectrogram holds the magnitude of each frame and each frequency bin.
+type Spectrogram struct {
+	SampleRate float64
+
+	// Times are the center of the frames in seconds.
+	Times []float64
+
+	// Frequencies are the frequencies of the bins in Hz.
+	Frequencies []float64
+
+	// Magnitudes are the levels in dB indexed by the frame and the bin. The full scale sine wave is shown as 0 dB.
+	Magnitudes [][]float64
+}
+
+// NewSpectrogram computes the spectrogram of the samples.
+//
+// Parameters:
+//
+//     - samples ... Audio signal.
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - options ... Transform options. The zero value uses the defaults.
+func NewSpectrogram(samples []float64, sampleRate float64, options SpectrogramOptions) *Spectrogram {
+	if options.FFTSize <= 0 {
+		options.FFTSize = 1024
+	}
+
+	size := fft.NextPowerOfTwo(options.FFTSize)
+
+	if options.Hop <= 0 {
+		options.Hop = size / 4
+	}
+
+	plan := fft.NewPlan(size)
+	window := options.Window.Coefficients(size)
+	gain := 0.0
+
+	for _, w := range window {
+		gain += w
+	}
+
+	s := &Spectrogram{
+		SampleRate:  sampleRate,
+		Frequencies: make([]float64, size/2+1),
+	}
+
+	for k := range s.Frequencies {
+		s.Frequencies[k] = float64(k) * sampleRate / float64(size)
+	}
+
+	frame := make([]complex128, size)
+
+	for start := 0; start+size <= len(samples); start += options.Hop {
+		for i := range frame {
+			frame[i] = complex(samples[start+i]*window[i], 0.0)
+		}
+
+		plan.Forward(frame)
+
+		magnitudes := make([]float64, size/2+1)
+
+		for k := range magnitudes {
+			magnitudes[k] = 20.0 * math.Log10(2.0*cmplx.Abs(frame[k])/gain+1e-15)
+		}
+
+		s.Times = append(s.Times, (float64(start)+float64(size)/2.0)/sampleRate)
+		s.Magnitudes = append(s.Magnitudes, magnitudes)
+	}
+
+	return s
+}
+
+// Image renders the spectrogram. The horizontal axis is the time and the vertical axis is the frequency with the lowest bin at the bottom.
+//
+// Parameters:
+//
+//     - minDB ... Level shown as the darkest color. e.g. -120.0
+//     - maxDB ... Level shown as the brightest color. e.g. 0.0
+func (s *Spectrogram) Image(minDB, maxDB float64) image.Image {
+	bins := len(s.Frequencies)
+	img := image.NewRGBA(image.Rect(0, 0, len(s.Magnitudes), bins))
+
+	for x, magnitudes := range s.Magnitudes {
+		for k, level := range magnitudes {
+			t := (level - minDB) / (maxDB - minDB)
+			img.Set(x, bins-1-k, heatColor(t))
+		}
+	}
+
+	return img
+}
+
+// WritePNG renders the spectrogram and writes it as PNG. See Image for the parameters.
+func (s *Spectrogram) WritePNG(w io.Writer, minDB, maxDB float64) error {
+	return png.Encode(w, s.Image(minDB, maxDB))
+}
+
+// heatStops are the colors of the color map from the lowest to the highest level.
+var heatStops = []color.RGBA{
+	{0, 0, 4, 255},
+	{87, 16, 110, 255},
+	{188, 55, 84, 255},
+	{249, 142, 9, 255},
+	{252, 255, 164, 255},
+}
+
+// heatColor returns the color for the value between 0 and 1.
+func heatColor(t float64) color.RGBA {
+	t = math.Max(0.0, math.Min(1.0, t))
+	position := t * float64(len(heatStops)-1)
+	i := int(position)
+
+	if i >= len(heatStops)-1 {
+		return heatStops[len(heatStops)-1]
+	}
+
+	f := position - float64(i)
+	a, b := heatStops[i], heatStops[i+1]
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*f)
+	}
+
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), 255}
+}
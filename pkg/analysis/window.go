@@ -0,0 +1,54 @@
+package analysis
+
+import "math"
+
+// Window represents the window function applied before the FFT.
+type Window int
+
+// Window constants are window functions.
+const (
+	HannWindow Window = iota
+	BlackmanHarrisWindow
+	RectangularWindow
+)
+
+// Coefficients returns the periodic window of the size.
+func (w Window) Coefficients(size int) []float64 {
+	switch w {
+	case BlackmanHarrisWindow:
+		return blackmanHarris(size)
+	case RectangularWindow:
+		window := make([]float64, size)
+
+		for i := range window {
+			window[i] = 1.0
+		}
+
+		return window
+	}
+
+	return hann(size)
+}
+
+// hann returns the periodic Hann window.
+func hann(size int) []float64 {
+	window := make([]float64, size)
+
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2.0*math.Pi*float64(i)/float64(size))
+	}
+
+	return window
+}
+
+// blackmanHarris returns the 4-term Blackman-Harris window.
+func blackmanHarris(size int) []float64 {
+	window := make([]float64, size)
+
+	for i := range window {
+		x := 2.0 * math.Pi * float64(i) / float64(size)
+		window[i] = 0.35875 - 0.48829*math.Cos(x) + 0.14128*math.Cos(2.0*x) - 0.01168*math.Cos(3.0*x)
+	}
+
+	return window
+}
@@ -0,0 +1,140 @@
+package equalizer
+
+import (
+	"math"
+	"testing"
+)
+
+const testSampleRate = 48000.0
+
+// testSignal returns the sine wave which is processed by the benchmarks and the allocation tests.
+func testSignal(length int) []float64 {
+	signal := make([]float64, length)
+
+	for i := range signal {
+		signal[i] = 0.5 * math.Sin(2.0*math.Pi*440.0*float64(i)/testSampleRate)
+	}
+
+	return signal
+}
+
+// testChain returns the chain of the typical equalizer.
+func testChain() *Chain {
+	return NewChain(
+		NewHighPass(testSampleRate, 80.0, 0.707),
+		NewPeaking(testSampleRate, 1000.0, 1.0, 3.0),
+		NewHighShelf(testSampleRate, 8000.0, 0.707, -2.0),
+	)
+}
+
+func BenchmarkFilterApply(b *testing.B) {
+	f := NewPeaking(testSampleRate, 1000.0, 1.0, 3.0)
+	signal := testSignal(1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		f.Apply(signal[i%len(signal)])
+	}
+}
+
+func BenchmarkChainProcessBuffer(b *testing.B) {
+	c := testChain()
+	signal := testSignal(1024)
+	buffer := make([]float64, len(signal))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(8 * len(buffer)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		copy(buffer, signal)
+		c.ProcessBuffer(buffer)
+	}
+}
+
+func TestFilterApplyAllocs(t *testing.T) {
+	f := NewPeaking(testSampleRate, 1000.0, 1.0, 3.0)
+	f.SetMix(50.0)
+
+	if n := testing.AllocsPerRun(100, func() { f.Apply(0.5) }); n != 0 {
+		t.Errorf("Filter.Apply allocates %v times", n)
+	}
+}
+
+func TestChainProcessBufferAllocs(t *testing.T) {
+	signal := testSignal(1024)
+	buffer := make([]float64, len(signal))
+
+	tests := []struct {
+		name  string
+		setup func(c *Chain)
+	}{
+		{"plain", func(c *Chain) {}},
+		{"hooks", func(c *Chain) { c.SetHooks(NopHooks{}) }},
+		{"mix", func(c *Chain) { c.SetMix(50.0) }},
+		{"bypass", func(c *Chain) { c.SetBypassed(true) }},
+	}
+	for _, test := range tests {
+		c := testChain()
+		test.setup(c)
+
+		// The first block allocates the buffer of the unprocessed signal.
+		c.ProcessBuffer(buffer)
+
+		n := testing.AllocsPerRun(100, func() {
+			copy(buffer, signal)
+			c.ProcessBuffer(buffer)
+		})
+
+		if n != 0 {
+			t.Errorf("%s: Chain.ProcessBuffer allocates %v times", test.name, n)
+		}
+	}
+}
+
+func TestChainApplyAllocs(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c *Chain)
+	}{
+		{"plain", func(c *Chain) {}},
+		{"mix", func(c *Chain) { c.SetMix(50.0) }},
+		{"latency", func(c *Chain) { c.Add(NewConvolver([]float64{1.0}, 64)) }},
+	}
+	for _, test := range tests {
+		c := testChain()
+		test.setup(c)
+		c.Apply(0.5)
+
+		if n := testing.AllocsPerRun(100, func() { c.Apply(0.5) }); n != 0 {
+			t.Errorf("%s: Chain.Apply allocates %v times", test.name, n)
+		}
+	}
+}
+
+func TestProcessIntoAllocs(t *testing.T) {
+	signal := testSignal(1024)
+
+	tests := []struct {
+		name    string
+		process func(dst, input []float64) []float64
+	}{
+		{"Decimator", NewDecimator(2, 31).ProcessInto},
+		{"Interpolator", NewInterpolator(2, 31).ProcessInto},
+		{"Resampler", NewResampler(44100.0, testSampleRate).ProcessInto},
+	}
+	for _, test := range tests {
+		// The first call grows dst, which is reused by the later calls.
+		dst := test.process(nil, signal)
+
+		n := testing.AllocsPerRun(100, func() {
+			dst = test.process(dst[:0], signal)
+		})
+
+		if n != 0 {
+			t.Errorf("%s.ProcessInto allocates %v times", test.name, n)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// bilinear returns the digital filter transformed from the analog second order section.
+//
+//	H(s) = (b[0] + b[1]*s + b[2]*s^2) / (a[0] + a[1]*s + a[2]*s^2)
+func bilinear(sampleRate float64, b, a [3]float64) *Filter {
+	k := 2.0 * sampleRate
+
+	return NewCustom(
+		sampleRate,
+		b[2]*k*k+b[1]*k+b[0],
+		2.0*(b[0]-b[2]*k*k),
+		b[2]*k*k-b[1]*k+b[0],
+		a[2]*k*k+a[1]*k+a[0],
+		2.0*(a[0]-a[2]*k*k),
+		a[2]*k*k-a[1]*k+a[0],
+	)
+}
+
+// bilinearFirstOrder returns the numerator and the denominator of the digital filter transformed from the analog first order section.
+//
+//	H(s) = (b[0] + b[1]*s) / (a[0] + a[1]*s)
+func bilinearFirstOrder(sampleRate float64, b, a [2]float64) ([2]float64, [2]float64) {
+	k := 2.0 * sampleRate
+
+	return [2]float64{b[1]*k + b[0], b[0] - b[1]*k}, [2]float64{a[1]*k + a[0], a[0] - a[1]*k}
+}
+
+// prewarp returns the analog angular frequency which is mapped to the frequency by the bilinear transform.
+func prewarp(sampleRate, frequency float64) float64 {
+	return 2.0 * sampleRate * math.Tan(p*frequency/sampleRate)
+}
+
+// butterworthPoles returns the poles of the analog Butterworth low-pass prototype whose cut off is 1 rad/s.
+func butterworthPoles(order int) []complex128 {
+	poles := make([]complex128, order)
+
+	for k := range poles {
+		theta := p * float64(2*k+order+1) / float64(2*order)
+		poles[k] = cmplx.Exp(complex(0.0, theta))
+	}
+
+	return poles
+}
+
+// butterworthBandPass returns the cascade of the second order sections which forms the Butterworth band-pass filter.
+// The order is the order of the low-pass prototype, so the band-pass filter has order*2 poles.
+func butterworthBandPass(sampleRate, lower, upper float64, order int) []*Filter {
+	w1 := prewarp(sampleRate, lower)
+	w2 := prewarp(sampleRate, upper)
+	w0 := math.Sqrt(w1 * w2)
+	bandwidth := w2 - w1
+
+	sections := make([]*Filter, 0, order)
+
+	for _, pole := range butterworthPoles(order) {
+		// The low-pass to band-pass transform maps the pole to the roots of s^2 - pole*bandwidth*s + w0^2.
+		pb := pole * complex(bandwidth, 0.0)
+		d := cmplx.Sqrt(pb*pb - complex(4.0*w0*w0, 0.0))
+
+		for _, root := range []complex128{(pb + d) / 2.0, (pb - d) / 2.0} {
+			// The conjugate root is covered by the same section.
+			if imag(root) <= 0.0 {
+				continue
+			}
+
+			a := [3]float64{real(root)*real(root) + imag(root)*imag(root), -2.0 * real(root), 1.0}
+			b := [3]float64{0.0, bandwidth, 0.0}
+
+			sections = append(sections, bilinear(sampleRate, b, a))
+		}
+	}
+
+	return sections
+}
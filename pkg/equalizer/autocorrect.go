@@ -0,0 +1,143 @@
+package equalizer
+
+import "math"
+
+// AutoCorrectOptions controls the automatic correction.
+type AutoCorrectOptions struct {
+	// SampleRate is the sample rate in Hz of the returned equalizer. The default is 48 kHz.
+	SampleRate float64
+
+	// Fit controls the fitting of the correction bands.
+	Fit FitOptions
+
+	// MaxBoost is the maximum boost of the correction in dB. The default is 6 dB. It limits the summed response of the
+	// bands, not only the gain of each band.
+	MaxBoost float64
+
+	// MaxCut is the maximum cut of the correction in dB. The default is 12 dB.
+	MaxCut float64
+
+	// Smoothing is the width of the smoothing window in octaves applied to the deviation. The default is 1/3 octave.
+	Smoothing float64
+
+	// PreserveLevel keeps the average level difference between the measured and the target responses.
+	// By default, the average difference is removed, so the correction does not change the overall level.
+	PreserveLevel bool
+}
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o AutoCorrectOptions) withDefaults() AutoCorrectOptions {
+	if o.SampleRate <= 0.0 {
+		o.SampleRate = 48000.0
+	}
+	if o.MaxBoost <= 0.0 {
+		o.MaxBoost = 6.0
+	}
+	if o.MaxCut <= 0.0 {
+		o.MaxCut = 12.0
+	}
+	if o.Smoothing <= 0.0 {
+		o.Smoothing = 1.0 / 3.0
+	}
+
+	return o
+}
+
+// AutoCorrect returns the parametric equalizer which corrects the measured response to the target response.
+// The deviation is smoothed and limited before the fitting, so the narrow dips and the excessive boosts are not corrected.
+//
+// Parameters:
+//
+//     - measured ... Measured magnitude response.
+//     - target ... Target magnitude response. The empty target means the flat response.
+//     - options ... Correction options. The zero value uses the defaults.
+//
+// NOTE: The overlapping bands of the fitted equalizer can boost more than each band, so the gains of all bands are scaled
+// down until the summed response up to the Nyquist frequency is within MaxBoost.
+func AutoCorrect(measured, target Response, options AutoCorrectOptions) (*ParametricEQ, error) {
+	options = options.withDefaults()
+	sampleRate := options.SampleRate
+	fit := options.Fit.withDefaults(sampleRate)
+
+	measured = append(Response{}, measured...)
+	measured.Sort()
+	target = append(Response{}, target...)
+	target.Sort()
+
+	frequencies := logFrequencies(fit.MinFrequency, fit.MaxFrequency, fit.Points)
+	deviation := make([]float64, len(frequencies))
+	mean := 0.0
+
+	for i, frequency := range frequencies {
+		deviation[i] = target.At(frequency) - measured.At(frequency)
+		mean += deviation[i] / float64(len(deviation))
+	}
+	if !options.PreserveLevel {
+		for i := range deviation {
+			deviation[i] -= mean
+		}
+	}
+
+	// Average the deviation within the window on the logarithmic frequency axis.
+	octaves := math.Log2(fit.MaxFrequency / fit.MinFrequency)
+	half := int(options.Smoothing / 2.0 / octaves * float64(len(frequencies)-1))
+	correction := make(Response, len(frequencies))
+
+	for i, frequency := range frequencies {
+		sum := 0.0
+		count := 0
+
+		for k := i - half; k <= i+half; k++ {
+			if k >= 0 && k < len(deviation) {
+				sum += deviation[k]
+				count++
+			}
+		}
+
+		correction[i] = ResponsePoint{
+			Frequency: frequency,
+			Gain:      math.Max(-options.MaxCut, math.Min(options.MaxBoost, sum/float64(count))),
+		}
+	}
+
+	if fit.MaxGain < math.Max(options.MaxBoost, options.MaxCut) {
+		fit.MaxGain = math.Max(options.MaxBoost, options.MaxCut)
+	}
+
+	e, err := Fit(sampleRate, correction, fit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return limitBoost(e, logFrequencies(fit.MinFrequency/4.0, clampRatio*sampleRate, 2*fit.Points), options.MaxBoost), nil
+}
+
+// limitBoost returns the equalizer whose summed response at the frequencies does not exceed the boost in dB. The gains of
+// the bands are scaled by the same factor, so the shape of the correction is kept.
+func limitBoost(e *ParametricEQ, frequencies []float64, boost float64) *ParametricEQ {
+	// The response in dB is nearly proportional to the gains, so a few iterations reach the limit.
+	for iteration := 0; iteration < 20; iteration++ {
+		peak := math.Inf(-1)
+
+		for _, point := range e.Response(frequencies) {
+			peak = math.Max(peak, point.Gain)
+		}
+		if peak <= boost {
+			break
+		}
+
+		// The margin makes the last iteration land below the limit instead of approaching it forever.
+		scale := boost / peak * (1.0 - 1e-6)
+		bands := make([]Band, len(e.Bands()))
+
+		for i, band := range e.Bands() {
+			band.Gain *= scale
+			bands[i] = band
+		}
+
+		e = NewParametricEQ(e.SampleRate(), bands...)
+	}
+
+	return e
+}
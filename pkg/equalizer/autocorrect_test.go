@@ -0,0 +1,73 @@
+package equalizer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLimitBoost(t *testing.T) {
+	// Each band is within the limit, but the overlapping bands boost 6 dB at 1 kHz.
+	e := NewParametricEQ(48000.0,
+		Band{Name: Peaking, Frequency: 1000.0, Q: 1.0, Gain: 3.0},
+		Band{Name: Peaking, Frequency: 1000.0, Q: 2.0, Gain: 3.0},
+		Band{Name: Peaking, Frequency: 8000.0, Q: 1.0, Gain: -6.0},
+	)
+	frequencies := logFrequencies(20.0, 20000.0, 400)
+	limited := limitBoost(e, frequencies, 4.0)
+	peak := math.Inf(-1)
+
+	for _, point := range limited.Response(frequencies) {
+		peak = math.Max(peak, point.Gain)
+	}
+	if peak > 4.0 || peak < 3.9 {
+		t.Errorf("peak is %v dB, want 4 dB", peak)
+	}
+
+	// The gains are scaled by the same factor, so the cut is scaled with the boosts.
+	bands := limited.Bands()
+
+	if r := bands[2].Gain / bands[0].Gain; math.Abs(r-(-2.0)) > 1e-12 {
+		t.Errorf("ratio of the gains is %v, want -2", r)
+	}
+}
+
+func TestAutoCorrectMaxBoost(t *testing.T) {
+	// The broad dip of 12 dB is filled with the several overlapping bands.
+	var measured Response
+
+	for _, frequency := range logFrequencies(20.0, 20000.0, 200) {
+		gain := 0.0
+
+		if frequency > 300.0 && frequency < 3000.0 {
+			gain = -12.0
+		}
+
+		measured = append(measured, ResponsePoint{Frequency: frequency, Gain: gain})
+	}
+
+	options := AutoCorrectOptions{
+		SampleRate:    44100.0,
+		MaxBoost:      3.0,
+		PreserveLevel: true,
+	}
+	e, err := AutoCorrect(measured, nil, options)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.SampleRate() != 44100.0 {
+		t.Errorf("sample rate is %v Hz, want 44100 Hz", e.SampleRate())
+	}
+
+	peak := math.Inf(-1)
+
+	for _, point := range e.Response(logFrequencies(5.0, 22000.0, 1000)) {
+		peak = math.Max(peak, point.Gain)
+	}
+	if peak > options.MaxBoost+0.01 {
+		t.Errorf("summed response boosts %v dB, more than %v dB", peak, options.MaxBoost)
+	}
+	if peak < options.MaxBoost/2.0 {
+		t.Errorf("summed response boosts only %v dB", peak)
+	}
+}
@@ -0,0 +1,108 @@
+package equalizer
+
+import "sort"
+
+// Parameter represents the filter parameter which can be automated.
+type Parameter int
+
+// Parameter constants are automatable filter parameters.
+const (
+	FrequencyParameter Parameter = iota
+	QParameter
+	GainParameter
+)
+
+// AutomationEvent is the parameter change at the sample position.
+type AutomationEvent struct {
+	// Position is the number of samples from the start of the processing.
+	Position  int64
+	Parameter Parameter
+	Value     float64
+}
+
+// Automation applies the parameter changes to the filter at the exact sample positions.
+type Automation struct {
+	filter   *Filter
+	events   []AutomationEvent
+	position int64
+}
+
+// NewAutomation returns the automation for the filter.
+func NewAutomation(filter *Filter) *Automation {
+	return &Automation{
+		filter: filter,
+	}
+}
+
+// Filter returns the automated filter.
+func (a *Automation) Filter() *Filter {
+	return a.filter
+}
+
+// Position returns the number of samples processed so far.
+func (a *Automation) Position() int64 {
+	return a.position
+}
+
+// Add schedules the events. The events scheduled before the current position are applied at the next sample.
+func (a *Automation) Add(events ...AutomationEvent) {
+	a.events = append(a.events, events...)
+
+	sort.SliceStable(a.events, func(i, j int) bool {
+		return a.events[i].Position < a.events[j].Position
+	})
+}
+
+// Pending returns the number of events not applied yet.
+func (a *Automation) Pending() int {
+	return len(a.events)
+}
+
+// Apply applies the due events and the filter, then returns the value.
+func (a *Automation) Apply(input float64) float64 {
+	a.applyEvents()
+	a.position++
+
+	return a.filter.Apply(input)
+}
+
+// ProcessBuffer applies the filter to the buffer in place. The events are applied at the exact samples during the buffer.
+func (a *Automation) ProcessBuffer(buffer []float64) {
+	for len(buffer) > 0 {
+		a.applyEvents()
+
+		n := int64(len(buffer))
+
+		if len(a.events) > 0 && a.events[0].Position-a.position < n {
+			n = a.events[0].Position - a.position
+		}
+
+		a.filter.ProcessBuffer(buffer[:n])
+		a.position += n
+		buffer = buffer[n:]
+	}
+}
+
+// Reset clears the state of the filter and rewinds the position to 0. The pending events are discarded.
+func (a *Automation) Reset() {
+	a.filter.Reset()
+	a.events = nil
+	a.position = 0
+}
+
+// applyEvents applies the events whose position is less than or equal to the current position.
+func (a *Automation) applyEvents() {
+	for len(a.events) > 0 && a.events[0].Position <= a.position {
+		e := a.events[0]
+		a.events = a.events[1:]
+
+		switch e.Parameter {
+		case FrequencyParameter:
+			a.filter.SetFrequency(e.Value)
+		case QParameter:
+			a.filter.SetQ(e.Value)
+		case GainParameter:
+			a.filter.SetGain(e.Value)
+		}
+	}
+}
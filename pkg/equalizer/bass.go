@@ -0,0 +1,114 @@
+package equalizer
+
+import "math"
+
+// BassEnhancer increases the perceived bass by adding the harmonics of the low frequencies, which are heard even when the speaker cannot reproduce the fundamental (missing fundamental effect).
+type BassEnhancer struct {
+	// extracts the low frequencies
+	lowPass [2]*Filter
+
+	// shapes the generated harmonics
+	harmonicHighPass [2]*Filter
+	harmonicLowPass  *Filter
+
+	// removes the fundamental from the dry signal
+	dryHighPass [2]*Filter
+
+	envelope          *EnvelopeFollower
+	amount            float64
+	removeFundamental bool
+}
+
+// NewBassEnhancer returns the bass enhancer.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - frequency ... Highest frequency treated as the bass in Hz. e.g. 120.0
+//     - amount ... Level of the harmonics in dB relative to the bass. e.g. -6.0
+//
+// NOTE: The harmonics are band-limited between the frequency and 4 times of the frequency.
+func NewBassEnhancer(sampleRate, frequency, amount float64) *BassEnhancer {
+	const q = 1.0 / math.Sqrt2
+
+	b := &BassEnhancer{
+		harmonicLowPass: NewLowPass(sampleRate, math.Min(4.0*frequency, 0.45*sampleRate), q),
+		envelope:        NewEnvelopeFollower(sampleRate, 0.001, 0.05, PeakEnvelope),
+	}
+
+	for n := 0; n < 2; n++ {
+		b.lowPass[n] = NewLowPass(sampleRate, frequency, q)
+		b.harmonicHighPass[n] = NewHighPass(sampleRate, frequency, q)
+		b.dryHighPass[n] = NewHighPass(sampleRate, frequency, q)
+	}
+
+	b.SetAmount(amount)
+
+	return b
+}
+
+// Amount returns the level of the harmonics in dB.
+func (b *BassEnhancer) Amount() float64 {
+	return 20.0 * math.Log10(b.amount)
+}
+
+// SetAmount sets the level of the harmonics in dB.
+func (b *BassEnhancer) SetAmount(amount float64) {
+	b.amount = math.Pow(10.0, amount/20.0)
+}
+
+// RemoveFundamental returns true when the bass is removed from the dry signal.
+func (b *BassEnhancer) RemoveFundamental() bool {
+	return b.removeFundamental
+}
+
+// SetRemoveFundamental removes the bass from the dry signal, which saves the headroom of the small speaker.
+func (b *BassEnhancer) SetRemoveFundamental(remove bool) {
+	b.removeFundamental = remove
+}
+
+// Apply applies the bass enhancer and returns the value.
+func (b *BassEnhancer) Apply(input float64) float64 {
+	low := b.lowPass[1].Apply(b.lowPass[0].Apply(input))
+	envelope := b.envelope.Apply(low)
+
+	// Normalize the bass before the nonlinearity, so the harmonic structure does not depend on the level.
+	harmonics := 0.0
+
+	if envelope > 1e-9 {
+		x := low / envelope
+
+		// The rectifier generates the even harmonics and the saturation generates the odd harmonics.
+		harmonics = envelope * (math.Abs(x) + math.Tanh(3.0*x)) / 2.0
+	}
+
+	harmonics = b.harmonicHighPass[1].Apply(b.harmonicHighPass[0].Apply(harmonics))
+	harmonics = b.harmonicLowPass.Apply(harmonics)
+
+	dry := b.dryHighPass[1].Apply(b.dryHighPass[0].Apply(input))
+
+	if !b.removeFundamental {
+		dry = input
+	}
+
+	return dry + b.amount*harmonics
+}
+
+// ProcessBuffer applies the bass enhancer to the buffer in place.
+func (b *BassEnhancer) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = b.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables.
+func (b *BassEnhancer) Reset() {
+	b.harmonicLowPass.Reset()
+	b.envelope.Reset()
+
+	for n := 0; n < 2; n++ {
+		b.lowPass[n].Reset()
+		b.harmonicHighPass[n].Reset()
+		b.dryHighPass[n].Reset()
+	}
+}
@@ -0,0 +1,110 @@
+package equalizer
+
+import "math"
+
+// Baxandall is the two-band bass and treble tone control modeled on the Baxandall tone stack.
+// Each band is the first order shelf whose transition is centered on the hinge frequency, so the curves are gentle and overlap each other.
+type Baxandall struct {
+	filter *Filter
+
+	bassFrequency   float64
+	trebleFrequency float64
+	bass            float64
+	treble          float64
+}
+
+// NewBaxandall returns the Baxandall tone control with flat response.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - bassFrequency ... Hinge frequency of the bass control in Hz. e.g. 250.0
+//     - trebleFrequency ... Hinge frequency of the treble control in Hz. e.g. 2500.0
+func NewBaxandall(sampleRate, bassFrequency, trebleFrequency float64) *Baxandall {
+	b := &Baxandall{
+		filter:          NewCustom(sampleRate, 1.0, 0.0, 0.0, 1.0, 0.0, 0.0),
+		bassFrequency:   bassFrequency,
+		trebleFrequency: trebleFrequency,
+	}
+
+	b.design()
+
+	return b
+}
+
+// Bass returns the bass gain in dB.
+func (b *Baxandall) Bass() float64 {
+	return b.bass
+}
+
+// SetBass sets the bass gain in dB. The state variables are preserved.
+func (b *Baxandall) SetBass(gain float64) {
+	b.bass = gain
+	b.design()
+}
+
+// Treble returns the treble gain in dB.
+func (b *Baxandall) Treble() float64 {
+	return b.treble
+}
+
+// SetTreble sets the treble gain in dB. The state variables are preserved.
+func (b *Baxandall) SetTreble(gain float64) {
+	b.treble = gain
+	b.design()
+}
+
+// Filter returns the biquad filter which implements the tone control.
+func (b *Baxandall) Filter() *Filter {
+	return b.filter
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (b *Baxandall) FrequencyResponse(frequency float64) complex128 {
+	return b.filter.FrequencyResponse(frequency)
+}
+
+// GroupDelay returns the group delay in seconds at the frequency in Hz.
+func (b *Baxandall) GroupDelay(frequency float64) float64 {
+	return b.filter.GroupDelay(frequency)
+}
+
+// Apply applies the tone control and returns the value.
+func (b *Baxandall) Apply(input float64) float64 {
+	return b.filter.Apply(input)
+}
+
+// ProcessBuffer applies the tone control to the buffer in place.
+func (b *Baxandall) ProcessBuffer(buffer []float64) {
+	b.filter.ProcessBuffer(buffer)
+}
+
+// Reset clears the state variables.
+func (b *Baxandall) Reset() {
+	b.filter.Reset()
+}
+
+// design multiplies the bass and the treble shelves into one biquad.
+func (b *Baxandall) design() {
+	sampleRate := b.filter.sampleRate
+
+	// H(s) = (s + w*sqrt(G)) / (s + w/sqrt(G)) has gain G below w and unity gain above w.
+	wb := prewarp(sampleRate, b.bassFrequency)
+	gb := math.Sqrt(math.Pow(10.0, b.bass/20.0))
+	bassB, bassA := bilinearFirstOrder(sampleRate, [2]float64{wb * gb, 1.0}, [2]float64{wb / gb, 1.0})
+
+	// H(s) = (s*sqrt(G) + w) / (s/sqrt(G) + w) has unity gain below w and gain G above w.
+	wt := prewarp(sampleRate, b.trebleFrequency)
+	gt := math.Sqrt(math.Pow(10.0, b.treble/20.0))
+	trebleB, trebleA := bilinearFirstOrder(sampleRate, [2]float64{wt, gt}, [2]float64{wt, 1.0 / gt})
+
+	f := b.filter
+	f.b0 = bassB[0] * trebleB[0]
+	f.b1 = bassB[0]*trebleB[1] + bassB[1]*trebleB[0]
+	f.b2 = bassB[1] * trebleB[1]
+	f.scale = bassA[0] * trebleA[0]
+	f.a1 = bassA[0]*trebleA[1] + bassA[1]*trebleA[0]
+	f.a2 = bassA[1] * trebleA[1]
+
+	normalized(f)
+}
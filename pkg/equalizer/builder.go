@@ -0,0 +1,163 @@
+package equalizer
+
+import "fmt"
+
+// Builder builds the chain with the method chaining, e.g.
+//
+//	chain, err := equalizer.Build(48000).HighPass(80, 0.707).Peaking(2500, 1.4, -3).LowShelf(120, 0.707, 2).Chain()
+//
+// The first invalid parameter is kept and returned by Chain or ParametricEQ, so the error is checked once at the end.
+type Builder struct {
+	sampleRate float64
+
+	// stages are the bands, the gains and the processors in the order they are appended. One of them is set.
+	stages []builderStage
+	err    error
+}
+
+type builderStage struct {
+	band      *Band
+	gain      *float64
+	processor Processor
+}
+
+// Build returns the builder of the filters at the sample rate in Hz. e.g. 44100.0
+func Build(sampleRate float64) *Builder {
+	return &Builder{
+		sampleRate: sampleRate,
+	}
+}
+
+// LowPass appends the low-pass filter.
+func (b *Builder) LowPass(frequency, q float64) *Builder {
+	return b.Band(Band{Name: LowPass, Frequency: frequency, Q: q})
+}
+
+// HighPass appends the high-pass filter.
+func (b *Builder) HighPass(frequency, q float64) *Builder {
+	return b.Band(Band{Name: HighPass, Frequency: frequency, Q: q})
+}
+
+// AllPass appends the all-pass filter.
+func (b *Builder) AllPass(frequency, q float64) *Builder {
+	return b.Band(Band{Name: AllPass, Frequency: frequency, Q: q})
+}
+
+// BandPass appends the band-pass filter. The width is the band width in octaves.
+func (b *Builder) BandPass(frequency, width float64) *Builder {
+	return b.Band(Band{Name: BandPass, Frequency: frequency, Q: width})
+}
+
+// BandReject appends the band-reject filter. The width is the band width in octaves.
+func (b *Builder) BandReject(frequency, width float64) *Builder {
+	return b.Band(Band{Name: BandReject, Frequency: frequency, Q: width})
+}
+
+// LowShelf appends the low-shelf filter. The gain is in dB.
+func (b *Builder) LowShelf(frequency, q, gain float64) *Builder {
+	return b.Band(Band{Name: LowShelf, Frequency: frequency, Q: q, Gain: gain})
+}
+
+// HighShelf appends the high-shelf filter. The gain is in dB.
+func (b *Builder) HighShelf(frequency, q, gain float64) *Builder {
+	return b.Band(Band{Name: HighShelf, Frequency: frequency, Q: q, Gain: gain})
+}
+
+// Peaking appends the peaking filter. The width is the band width in octaves and the gain is in dB.
+func (b *Builder) Peaking(frequency, width, gain float64) *Builder {
+	return b.Band(Band{Name: Peaking, Frequency: frequency, Q: width, Gain: gain})
+}
+
+// Gain appends the gain stage, e.g. the input or the output trim. The gain is in dB.
+func (b *Builder) Gain(gain float64) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.stages = append(b.stages, builderStage{gain: &gain})
+
+	return b
+}
+
+// Band appends the filter of the band, e.g. of the name registered by Register.
+func (b *Builder) Band(band Band) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	i := len(b.stages)
+
+	if err := CheckFrequency(b.sampleRate, band.Frequency); err != nil {
+		b.err = fmt.Errorf("filter %d: %w", i, err)
+
+		return b
+	}
+	if !(band.Q > 0.0) {
+		b.err = fmt.Errorf("equalizer: filter %d: q %g must be positive", i, band.Q)
+
+		return b
+	}
+	if design(band.Name, b.sampleRate, band.Frequency, band.Q, band.Gain) == nil {
+		b.err = fmt.Errorf("equalizer: filter %d: filter name %d cannot be designed from the parameters", i, band.Name)
+
+		return b
+	}
+
+	b.stages = append(b.stages, builderStage{band: &band})
+
+	return b
+}
+
+// Add appends the processors, e.g. the clip guard after the filters.
+//
+// NOTE: The filters are designed for each Chain, but the processors are shared by the chains built by the same builder.
+func (b *Builder) Add(processors ...Processor) *Builder {
+	for _, processor := range processors {
+		b.stages = append(b.stages, builderStage{processor: processor})
+	}
+
+	return b
+}
+
+// Chain returns the chain of the filters and the processors in the order they are appended. Each filter is one stage,
+// and each call returns the new filters, e.g. for the left and the right channels.
+func (b *Builder) Chain() (*Chain, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	processors := make([]Processor, len(b.stages))
+
+	for i, stage := range b.stages {
+		switch {
+		case stage.band != nil:
+			processors[i] = newBandFilter(b.sampleRate, *stage.band)
+		case stage.gain != nil:
+			processors[i] = NewGain(b.sampleRate, *stage.gain)
+		default:
+			processors[i] = stage.processor
+		}
+	}
+
+	return NewChain(processors...), nil
+}
+
+// ParametricEQ returns the parametric equalizer of the bands, so the bands can be changed later by SetBand.
+// It fails when the gain or the processor is appended.
+func (b *Builder) ParametricEQ() (*ParametricEQ, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	bands := make([]Band, len(b.stages))
+
+	for i, stage := range b.stages {
+		if stage.band == nil {
+			return nil, fmt.Errorf("equalizer: stage %d is not the band, so the parametric equalizer cannot be built", i)
+		}
+
+		bands[i] = *stage.band
+	}
+
+	return NewParametricEQ(b.sampleRate, bands...), nil
+}
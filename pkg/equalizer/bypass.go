@@ -0,0 +1,105 @@
+package equalizer
+
+import "time"
+
+// BypassTime is the time in which SetBypassed ramps the effect in or out, which is short enough for the A/B comparison
+// and long enough not to click.
+const BypassTime = 10 * time.Millisecond
+
+// ramp moves the amount of the unprocessed signal linearly toward the target. The zero value is fully processed.
+type ramp struct {
+	value  float64
+	target float64
+	step   float64
+}
+
+// set starts the ramp to the target over the length in samples. The length 0 jumps to the target.
+func (r *ramp) set(target float64, length int) {
+	r.target = target
+
+	if length <= 0 {
+		r.value = target
+		r.step = 0.0
+
+		return
+	}
+
+	r.step = (target - r.value) / float64(length)
+}
+
+// active returns true when the unprocessed signal is mixed, so the mix can be skipped in the common case.
+func (r *ramp) active() bool {
+	return r.value != 0.0 || r.target != 0.0
+}
+
+// advance advances the ramp by one sample and returns the value.
+func (r *ramp) advance() float64 {
+	if r.value != r.target {
+		r.value += r.step
+
+		if (r.step > 0.0 && r.value > r.target) || (r.step <= 0.0 && r.value < r.target) {
+			r.value = r.target
+		}
+	}
+
+	return r.value
+}
+
+// mixDry returns the processed value mixed with the unprocessed one by the amounts of the dry signal and the bypass.
+func mixDry(unprocessed, processed, dry, bypass float64) float64 {
+	amount := 1.0 - (1.0-dry)*(1.0-bypass)
+
+	return processed + amount*(unprocessed-processed)
+}
+
+// SetBypassed ramps the effect of the filter out, or in when bypassed is false, over BypassTime. The filter keeps
+// processing while it is bypassed, so it comes back without the click of the stale state.
+//
+// NOTE: The responses, e.g. FrequencyResponse, are of the filter regardless of the bypass.
+func (f *Filter) SetBypassed(bypassed bool) {
+	f.bypass.set(bypassAmount(bypassed), int(BypassTime.Seconds()*f.sampleRate))
+}
+
+// Bypassed returns true when the filter is bypassed or being bypassed.
+func (f *Filter) Bypassed() bool {
+	return f.bypass.target == 1.0
+}
+
+// SetBypassed ramps the effect of the chain out, or in when bypassed is false, over the ramp set by SetBypassRamp.
+// The processors keep processing while the chain is bypassed, so they come back without the click of the stale state.
+//
+// NOTE: The unprocessed signal is delayed by the latency of the chain, so the bypassed chain has the same latency.
+func (c *Chain) SetBypassed(bypassed bool) {
+	c.bypass.set(bypassAmount(bypassed), c.rampLength())
+}
+
+// Bypassed returns true when the chain is bypassed or being bypassed.
+func (c *Chain) Bypassed() bool {
+	return c.bypass.target == 1.0
+}
+
+// SetBypassRamp sets the length of the ramp of SetBypassed and SetMix in samples, because the chain does not know the sample rate.
+// The default is 480 samples, which is BypassTime at 48 kHz. The negative length bypasses immediately.
+func (c *Chain) SetBypassRamp(samples int) {
+	c.bypassRamp = samples
+}
+
+// defaultBypassRamp is BypassTime at 48 kHz in samples.
+const defaultBypassRamp = 480
+
+// rampLength returns the length of the ramp in samples.
+func (c *Chain) rampLength() int {
+	if c.bypassRamp == 0 {
+		return defaultBypassRamp
+	}
+
+	return c.bypassRamp
+}
+
+func bypassAmount(bypassed bool) float64 {
+	if bypassed {
+		return 1.0
+	}
+
+	return 0.0
+}
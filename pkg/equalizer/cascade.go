@@ -0,0 +1,106 @@
+package equalizer
+
+import "math"
+
+// ButterworthQ returns the Q values of the second order sections which form the Butterworth filter of the order, e.g.
+// 0.541 and 1.307 for the 4th order (24 dB/oct) filter. The odd order filter has the first order section in addition.
+//
+// NOTE: Cascading the cookbook filters with Q = 0.707 does not make the higher order Butterworth filter. Two sections
+// with Q = 0.707 are -6 dB at the cut off and roll off early, because each pair of the poles must have its own Q.
+// The k-th section of the n-th order filter has Q = 1 / (2 sin((2k - 1) pi / (2n))).
+func ButterworthQ(order int) []float64 {
+	if order < 2 {
+		return nil
+	}
+
+	qs := make([]float64, order/2)
+
+	for k := range qs {
+		qs[k] = 1.0 / (2.0 * math.Sin(float64(2*k+1)*p/float64(2*order)))
+	}
+
+	return qs
+}
+
+// LinkwitzRileyQ returns the Q values of the second order sections which form the Linkwitz-Riley filter of the order,
+// e.g. 0.707 and 0.707 for the 4th order (24 dB/oct) filter. The order must be even.
+//
+// NOTE: The Linkwitz-Riley filter is the Butterworth filter of the half order applied twice, so it is -6 dB at the cut
+// off and its low-pass and high-pass sum to the flat magnitude. The two first order sections of the odd half order are
+// combined to the section with Q = 0.5.
+func LinkwitzRileyQ(order int) []float64 {
+	if order < 2 || order%2 != 0 {
+		return nil
+	}
+
+	half := ButterworthQ(order / 2)
+	qs := append(append([]float64{}, half...), half...)
+
+	if (order/2)%2 == 1 {
+		qs = append(qs, 0.5)
+	}
+
+	return qs
+}
+
+// CascadeButterLowPass returns the cascade of the filters which forms the Butterworth low-pass filter, e.g. the order 4
+// for 24 dB/oct and 8 for 48 dB/oct. It is -3 dB at the cut off.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - frequency ... Cut off frequency in Hz.
+//     - order ... Order of the filter. e.g. 4
+//
+// NOTE: The odd order has the first order section at the end, which is the custom filter.
+func CascadeButterLowPass(sampleRate, frequency float64, order int) []*Filter {
+	return cascade(LowPass, sampleRate, frequency, ButterworthQ(order), order%2 == 1)
+}
+
+// CascadeButterHighPass returns the cascade of the filters which forms the Butterworth high-pass filter. See CascadeButterLowPass for the parameters.
+func CascadeButterHighPass(sampleRate, frequency float64, order int) []*Filter {
+	return cascade(HighPass, sampleRate, frequency, ButterworthQ(order), order%2 == 1)
+}
+
+// CascadeLinkwitzRileyLowPass returns the cascade of the filters which forms the Linkwitz-Riley low-pass filter of the
+// even order, e.g. 4 for 24 dB/oct and 8 for 48 dB/oct. It is -6 dB at the cut off, so it is used for the crossover.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - frequency ... Crossover frequency in Hz.
+//     - order ... Even order of the filter. e.g. 4
+//
+// NOTE: The low-pass and the high-pass of the 4th and 8th order are in phase and sum to the flat magnitude. Those of the 2nd and the 6th order are out of phase, so invert one of them.
+func CascadeLinkwitzRileyLowPass(sampleRate, frequency float64, order int) []*Filter {
+	return cascade(LowPass, sampleRate, frequency, LinkwitzRileyQ(order), false)
+}
+
+// CascadeLinkwitzRileyHighPass returns the cascade of the filters which forms the Linkwitz-Riley high-pass filter. See CascadeLinkwitzRileyLowPass for the parameters.
+func CascadeLinkwitzRileyHighPass(sampleRate, frequency float64, order int) []*Filter {
+	return cascade(HighPass, sampleRate, frequency, LinkwitzRileyQ(order), false)
+}
+
+// cascade returns the low-pass or high-pass sections of the Q values followed by the first order section if odd is true.
+func cascade(name FilterName, sampleRate, frequency float64, qs []float64, odd bool) []*Filter {
+	filters := make([]*Filter, 0, len(qs)+1)
+
+	for _, q := range qs {
+		filters = append(filters, design(name, sampleRate, frequency, q, 0.0))
+	}
+	if !odd {
+		return filters
+	}
+
+	// H(s) = w / (s + w) for the low-pass and s / (s + w) for the high-pass.
+	w := prewarp(sampleRate, frequency)
+	b := [2]float64{w, 0.0}
+
+	if name == HighPass {
+		b = [2]float64{0.0, 1.0}
+	}
+
+	b, a := bilinearFirstOrder(sampleRate, b, [2]float64{w, 1.0})
+
+	return append(filters, NewCustom(sampleRate, b[0], b[1], 0.0, a[0], a[1], 0.0))
+}
@@ -0,0 +1,185 @@
+package equalizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CCodeOptions is the options of the generated C source.
+type CCodeOptions struct {
+	// Name is the prefix of the functions, the types and the macros. It must be the C identifier. The default is "equalizer".
+	Name string
+
+	// Channels is the number of the interleaved channels processed by the function. The same filters are applied to every channel. The default is 1.
+	Channels int
+
+	// Float32 uses float instead of double for the coefficients and the samples, e.g. for the microcontroller with the single precision FPU.
+	Float32 bool
+}
+
+// cIdentifier matches the valid C identifier.
+var cIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o CCodeOptions) withDefaults() CCodeOptions {
+	if o.Name == "" {
+		o.Name = "equalizer"
+	}
+	if o.Channels <= 0 {
+		o.Channels = 1
+	}
+
+	return o
+}
+
+// WriteCCode writes the self-contained C source which has the coefficient table and the functions to process the
+// interleaved samples, so the chain designed by this package runs on the firmware without the dynamic allocation.
+//
+// The source defines the following, where name is the Name of the options:
+//
+//     - name_state ... Type of the state variables. Declare one per stream.
+//     - name_reset(name_state *state) ... Clears the state variables.
+//     - name_process(name_state *state, double *samples, size_t frames) ... Filters the interleaved samples in place.
+//
+// Parameters:
+//
+//     - w ... Destination of the source. e.g. the file equalizer.c
+//     - processor ... *Filter, *ParametricEQ or *Chain of them.
+//     - options ... Name, channels and precision of the source.
+//
+// NOTE: The filters are processed in the transposed direct form II, which keeps the precision with the float. The
+// coefficients are for the sample rate of the filters, so design the processor at the sample rate of the firmware.
+func WriteCCode(w io.Writer, processor Processor, options CCodeOptions) error {
+	filters, err := biquads(processor)
+
+	if err != nil {
+		return err
+	}
+
+	options = options.withDefaults()
+
+	if !cIdentifier.MatchString(options.Name) {
+		return fmt.Errorf("equalizer: %q is not the C identifier", options.Name)
+	}
+
+	// The array of the size 0 is invalid in C, so the flat response is the identity filter.
+	if len(filters) == 0 {
+		filters = []*Filter{NewCustom(48000.0, 1.0, 0.0, 0.0, 1.0, 0.0, 0.0)}
+	}
+
+	name := options.Name
+	macro := strings.ToUpper(name)
+	real, zero := "double", "0.0"
+
+	if options.Float32 {
+		real, zero = "float", "0.0f"
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "/* Generated by go-equalizer. */\n")
+	fmt.Fprintf(b, "#include <stddef.h>\n")
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "#define %s_SAMPLE_RATE %s\n", macro, strconv.FormatFloat(filters[0].sampleRate, 'g', -1, 64))
+	fmt.Fprintf(b, "#define %s_CHANNELS %d\n", macro, options.Channels)
+	fmt.Fprintf(b, "#define %s_SECTIONS %d\n", macro, len(filters))
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "/* b0, b1, b2, a1 and a2 of each section normalized by a0. */\n")
+	fmt.Fprintf(b, "static const %s %s_coefficients[%s_SECTIONS][5] = {\n", real, name, macro)
+
+	for _, f := range filters {
+		fmt.Fprintf(b, "    /* %s */\n", describeFilter(f))
+		fmt.Fprintf(b, "    { %s, %s, %s, %s, %s },\n",
+			formatCCoefficient(f.b0, options.Float32), formatCCoefficient(f.b1, options.Float32), formatCCoefficient(f.b2, options.Float32),
+			formatCCoefficient(f.a1, options.Float32), formatCCoefficient(f.a2, options.Float32))
+	}
+
+	fmt.Fprintf(b, "};\n")
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "typedef struct {\n")
+	fmt.Fprintf(b, "    %s z[%s_CHANNELS][%s_SECTIONS][2];\n", real, macro, macro)
+	fmt.Fprintf(b, "} %s_state;\n", name)
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "void %s_reset(%s_state *state)\n", name, name)
+	fmt.Fprintf(b, "{\n")
+	fmt.Fprintf(b, "    size_t c, s;\n")
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "    for (c = 0; c < %s_CHANNELS; c++) {\n", macro)
+	fmt.Fprintf(b, "        for (s = 0; s < %s_SECTIONS; s++) {\n", macro)
+	fmt.Fprintf(b, "            state->z[c][s][0] = %s;\n", zero)
+	fmt.Fprintf(b, "            state->z[c][s][1] = %s;\n", zero)
+	fmt.Fprintf(b, "        }\n")
+	fmt.Fprintf(b, "    }\n")
+	fmt.Fprintf(b, "}\n")
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "/* Filters the interleaved frames in place. */\n")
+	fmt.Fprintf(b, "void %s_process(%s_state *state, %s *samples, size_t frames)\n", name, name, real)
+	fmt.Fprintf(b, "{\n")
+	fmt.Fprintf(b, "    size_t i, c, s;\n")
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "    for (i = 0; i < frames; i++) {\n")
+	fmt.Fprintf(b, "        for (c = 0; c < %s_CHANNELS; c++) {\n", macro)
+	fmt.Fprintf(b, "            %s x = samples[i * %s_CHANNELS + c];\n", real, macro)
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "            for (s = 0; s < %s_SECTIONS; s++) {\n", macro)
+	fmt.Fprintf(b, "                const %s *k = %s_coefficients[s];\n", real, name)
+	fmt.Fprintf(b, "                %s *z = state->z[c][s];\n", real)
+	fmt.Fprintf(b, "                %s y = k[0] * x + z[0];\n", real)
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "                z[0] = k[1] * x - k[3] * y + z[1];\n")
+	fmt.Fprintf(b, "                z[1] = k[2] * x - k[4] * y;\n")
+	fmt.Fprintf(b, "                x = y;\n")
+	fmt.Fprintf(b, "            }\n")
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "            samples[i * %s_CHANNELS + c] = x;\n", macro)
+	fmt.Fprintf(b, "        }\n")
+	fmt.Fprintf(b, "    }\n")
+	fmt.Fprintf(b, "}\n")
+
+	return b.Flush()
+}
+
+// WriteCCodeFile is the same as WriteCCode but writes the source to the file.
+func WriteCCodeFile(path string, processor Processor, options CCodeOptions) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+	if err := WriteCCode(file, processor, options); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// describeFilter returns the design of the filter for the comment, e.g. "Peaking 2500 Hz, Q 1.4, -3 dB".
+func describeFilter(f *Filter) string {
+	if f.name == Custom {
+		return "Custom"
+	}
+
+	return fmt.Sprintf("%s %.6g Hz, Q %.6g, %.6g dB", filterNameString(f.name), f.frequency, f.q, f.gain)
+}
+
+// formatCCoefficient formats the coefficient as the C literal of double, or float with the suffix f.
+func formatCCoefficient(value float64, float32 bool) string {
+	if float32 {
+		s := strconv.FormatFloat(value, 'g', -1, 32)
+
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+
+		return s + "f"
+	}
+
+	return formatCoefficient(value)
+}
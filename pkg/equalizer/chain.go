@@ -0,0 +1,325 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+// Processor is the interface implemented by the filters which can be placed in the Chain.
+type Processor interface {
+	Apply(input float64) float64
+}
+
+// bufferProcessor is implemented by the processors which can process the buffer faster than calling Apply for each sample.
+type bufferProcessor interface {
+	ProcessBuffer(buffer []float64)
+}
+
+// responder is implemented by the processors whose frequency response is known.
+type responder interface {
+	FrequencyResponse(frequency float64) complex128
+}
+
+// groupDelayer is implemented by the processors whose group delay is known analytically.
+type groupDelayer interface {
+	GroupDelay(frequency float64) float64
+}
+
+// latencyReporter is implemented by the processors which delay the signal, e.g. the block based processors.
+type latencyReporter interface {
+	Latency() int
+}
+
+// latencyOwned is implemented by the processors whose latency can change after they are added, e.g. the nested Chain,
+// so they tell the chain to recompute the cached latency.
+type latencyOwned interface {
+	setLatencyOwner(owner *Chain)
+}
+
+// resetter is implemented by the processors which have the state variables.
+type resetter interface {
+	Reset()
+}
+
+// Chain applies the processors in series.
+type Chain struct {
+	processors []Processor
+	hooks      Hooks
+
+	// bypass and dry are the amounts of the unprocessed signal ramped by SetBypassed and SetMix. The unprocessed signal
+	// is delayed by the latency and held in the unprocessed buffer.
+	bypass      ramp
+	dry         ramp
+	bypassRamp  int
+	delay       delayLine
+	unprocessed []float64
+
+	// makeup is the automatic makeup gain enabled by SetAutoGain.
+	makeup *makeupGain
+
+	// latency is the cached sum of the latencies of the processors, which is recomputed when latencyChanged is set by
+	// Add or by the nested chain. owner is the chain which contains this chain.
+	latency        int
+	latencyChanged bool
+	owner          *Chain
+}
+
+// NewChain returns the chain of the processors. The input is processed in the given order.
+func NewChain(processors ...Processor) *Chain {
+	c := &Chain{
+		processors:     processors,
+		latencyChanged: true,
+	}
+
+	setLatencyOwner(processors, c)
+
+	return c
+}
+
+// Add appends the processors to the end of the chain.
+func (c *Chain) Add(processors ...Processor) {
+	c.processors = append(c.processors, processors...)
+	setLatencyOwner(processors, c)
+	c.invalidateLatency()
+
+	if c.hooks != nil {
+		setHooks(processors, c.hooks)
+	}
+}
+
+func setLatencyOwner(processors []Processor, owner *Chain) {
+	for _, processor := range processors {
+		if o, ok := processor.(latencyOwned); ok {
+			o.setLatencyOwner(owner)
+		}
+	}
+}
+
+// setLatencyOwner sets the chain which contains this chain, so the latency of the owner is recomputed when this chain is changed.
+//
+// NOTE: The chain placed in the several chains only reports the change to the last one.
+func (c *Chain) setLatencyOwner(owner *Chain) {
+	c.owner = owner
+
+	if owner != nil {
+		owner.invalidateLatency()
+	}
+}
+
+// invalidateLatency marks the cached latency of the chain and its owners to be recomputed.
+func (c *Chain) invalidateLatency() {
+	for chain := c; chain != nil && !chain.latencyChanged; chain = chain.owner {
+		chain.latencyChanged = true
+	}
+}
+
+// SetHooks sets the hooks which are called around each stage of ProcessBuffer. The hooks are also set to the processors
+// which accept them, e.g. ParametricEQ and the nested Chain, so the changes of the bands are reported. nil removes the hooks.
+//
+// NOTE: Apply does not call the hooks, because calling them for each sample is too expensive. The stage of the nested Chain is counted in it.
+func (c *Chain) SetHooks(hooks Hooks) {
+	c.hooks = hooks
+	setHooks(c.processors, hooks)
+}
+
+func setHooks(processors []Processor, hooks Hooks) {
+	for _, processor := range processors {
+		if s, ok := processor.(hooksSetter); ok {
+			s.SetHooks(hooks)
+		}
+	}
+}
+
+// Processors returns the processors in the chain.
+func (c *Chain) Processors() []Processor {
+	return c.processors
+}
+
+// Apply applies the processors in series and returns the value.
+func (c *Chain) Apply(input float64) float64 {
+	output := input
+
+	for _, processor := range c.processors {
+		output = processor.Apply(output)
+	}
+	if c.makeup != nil {
+		output *= c.makeup.next(c)
+	}
+
+	// The delay line is fed on every sample, see ProcessBuffer.
+	c.updateLatency()
+	delayed := c.delay.process(input)
+
+	if !c.bypass.active() && !c.dry.active() {
+		return output
+	}
+
+	return mixDry(delayed, output, c.dry.advance(), c.bypass.advance())
+}
+
+// ProcessBuffer applies the processors to the buffer in place.
+func (c *Chain) ProcessBuffer(buffer []float64) {
+	mixing := c.bypass.active() || c.dry.active()
+	c.updateLatency()
+
+	if mixing {
+		if cap(c.unprocessed) < len(buffer) {
+			c.unprocessed = make([]float64, len(buffer))
+		}
+
+		c.unprocessed = c.unprocessed[:len(buffer)]
+		copy(c.unprocessed, buffer)
+	} else {
+		// The delay line is fed even when the chain is not mixing, so the unprocessed signal is aligned as soon as the mix starts.
+		c.delay.feed(buffer)
+	}
+	for stage, processor := range c.processors {
+		if c.hooks == nil {
+			processBuffer(processor, buffer)
+
+			continue
+		}
+
+		c.hooks.OnBlockStart(stage, processor, len(buffer))
+		start := time.Now()
+		processBuffer(processor, buffer)
+		c.hooks.OnBlockEnd(stage, processor, len(buffer), time.Since(start))
+	}
+	if c.makeup != nil {
+		for i := range buffer {
+			buffer[i] *= c.makeup.next(c)
+		}
+	}
+	if mixing {
+		for i, value := range c.unprocessed {
+			buffer[i] = mixDry(c.delay.process(value), buffer[i], c.dry.advance(), c.bypass.advance())
+		}
+	}
+}
+
+// processBuffer applies the processor to the buffer in place.
+func processBuffer(processor Processor, buffer []float64) {
+	if p, ok := processor.(bufferProcessor); ok {
+		p.ProcessBuffer(buffer)
+
+		return
+	}
+	for i := range buffer {
+		buffer[i] = processor.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables of the processors and the delayed unprocessed signal of the mix.
+func (c *Chain) Reset() {
+	for _, processor := range c.processors {
+		if r, ok := processor.(resetter); ok {
+			r.Reset()
+		}
+	}
+
+	c.delay.reset()
+}
+
+// Latency returns the total latency of the processors in samples, which the host should compensate.
+//
+// NOTE: The minimum-phase IIR filters are treated as no latency. The convolver reports the block size, plus the half length of the impulse response when it is linear-phase.
+func (c *Chain) Latency() int {
+	c.updateLatency()
+
+	return c.latency
+}
+
+// updateLatency recomputes the cached latency and resizes the delay line of the unprocessed signal when the processors are changed.
+func (c *Chain) updateLatency() {
+	if !c.latencyChanged {
+		return
+	}
+
+	latency := 0
+
+	for _, processor := range c.processors {
+		if l, ok := processor.(latencyReporter); ok {
+			latency += l.Latency()
+		}
+	}
+
+	c.latency = latency
+	c.latencyChanged = false
+	c.delay.resize(latency)
+}
+
+// FrequencyResponse returns the complex frequency response of the chain at the frequency in Hz.
+//
+// NOTE: The processors which do not provide FrequencyResponse, e.g. the nonlinear processors, are treated as unity gain.
+func (c *Chain) FrequencyResponse(frequency float64) complex128 {
+	response := complex(1.0, 0.0)
+
+	for _, processor := range c.processors {
+		if r, ok := processor.(responder); ok {
+			response *= r.FrequencyResponse(frequency)
+		}
+	}
+
+	return response
+}
+
+// GroupDelay returns the group delay of the chain in seconds at the frequency in Hz.
+//
+// NOTE: The group delay of the processors which provide FrequencyResponse but not GroupDelay is derived from the phase numerically. The other processors are treated as no delay.
+func (c *Chain) GroupDelay(frequency float64) float64 {
+	delay := 0.0
+
+	for _, processor := range c.processors {
+		switch r := processor.(type) {
+		case groupDelayer:
+			delay += r.GroupDelay(frequency)
+		case responder:
+			delay += numericalGroupDelay(r, frequency)
+		}
+	}
+
+	return delay
+}
+
+// GroupDelayCurve returns the group delay of the chain in seconds at each frequency in Hz.
+func (c *Chain) GroupDelayCurve(frequencies []float64) []float64 {
+	delays := make([]float64, len(frequencies))
+
+	for i, frequency := range frequencies {
+		delays[i] = c.GroupDelay(frequency)
+	}
+
+	return delays
+}
+
+// numericalGroupDelay returns -d(phase)/d(omega) in seconds by the central difference.
+func numericalGroupDelay(r responder, frequency float64) float64 {
+	h := math.Max(frequency*1e-4, 1e-3)
+	lower := frequency - h
+
+	if lower < 0.0 {
+		lower = 0.0
+	}
+
+	difference := cmplx.Phase(r.FrequencyResponse(frequency+h) / r.FrequencyResponse(lower))
+
+	return -difference / (2.0 * math.Pi * (frequency + h - lower))
+}
+
+// ImpulseResponse returns the impulse response of the chain of the length in samples.
+//
+// NOTE: The state variables of the processors are cleared before and after the measurement.
+func (c *Chain) ImpulseResponse(length int) []float64 {
+	response := make([]float64, length)
+
+	if length > 0 {
+		response[0] = 1.0
+	}
+
+	c.Reset()
+	c.ProcessBuffer(response)
+	c.Reset()
+
+	return response
+}
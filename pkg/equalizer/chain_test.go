@@ -0,0 +1,101 @@
+package equalizer
+
+import "testing"
+
+// rampSignal returns the samples 1, 2, 3, ... from the start, so the delayed sample tells the delay.
+func rampSignal(start, length int) []float64 {
+	signal := make([]float64, length)
+
+	for i := range signal {
+		signal[i] = float64(start + i + 1)
+	}
+
+	return signal
+}
+
+func TestChainBypassLatency(t *testing.T) {
+	// The convolver with the unit impulse delays the signal by the block size.
+	c := NewChain(NewConvolver([]float64{1.0}, 4))
+	c.SetBypassRamp(-1)
+
+	if latency := c.Latency(); latency != 4 {
+		t.Fatalf("latency is %d, want 4", latency)
+	}
+
+	// The unprocessed signal is delayed before the bypass starts, so the bypassed chain has the same latency.
+	c.ProcessBuffer(rampSignal(0, 8))
+	c.SetBypassed(true)
+
+	buffer := rampSignal(8, 8)
+	c.ProcessBuffer(buffer)
+
+	for i, value := range buffer {
+		if want := float64(8 + i + 1 - 4); value != want {
+			t.Errorf("bypassed sample %d is %v, want %v", i, value, want)
+		}
+	}
+
+	// The same for Apply.
+	for i := 16; i < 24; i++ {
+		c.SetBypassed(i >= 20)
+
+		if value, want := c.Apply(float64(i+1)), float64(i+1-4); value != want {
+			t.Errorf("applied sample %d is %v, want %v", i, value, want)
+		}
+	}
+
+	// Reset clears the delayed unprocessed signal as well as the processors.
+	c.Reset()
+	buffer = rampSignal(0, 8)
+	c.ProcessBuffer(buffer)
+
+	for i, value := range buffer {
+		want := 0.0
+
+		if i >= 4 {
+			want = float64(i + 1 - 4)
+		}
+		if value != want {
+			t.Errorf("sample %d after Reset is %v, want %v", i, value, want)
+		}
+	}
+}
+
+func TestChainLatencyCache(t *testing.T) {
+	inner := NewChain()
+	c := NewChain(NewConvolver([]float64{1.0}, 4), NewMetrics(inner))
+
+	if latency := c.Latency(); latency != 4 {
+		t.Fatalf("latency is %d, want 4", latency)
+	}
+
+	// Add recomputes the latency of the chain, and of the chain which contains it.
+	c.Add(NewConvolver([]float64{1.0}, 8))
+
+	if latency := c.Latency(); latency != 12 {
+		t.Fatalf("latency after Add is %d, want 12", latency)
+	}
+
+	inner.Add(NewConvolver([]float64{1.0}, 2))
+
+	if latency := c.Latency(); latency != 14 {
+		t.Fatalf("latency after Add to the nested chain is %d, want 14", latency)
+	}
+
+	// The delay line of the unprocessed signal follows the latency.
+	c.SetBypassRamp(-1)
+	c.SetBypassed(true)
+	buffer := rampSignal(0, 16)
+	c.ProcessBuffer(buffer)
+
+	for i, value := range buffer {
+		want := 0.0
+
+		if i >= 14 {
+			want = float64(i + 1 - 14)
+		}
+		if value != want {
+			t.Errorf("bypassed sample %d is %v, want %v", i, value, want)
+		}
+	}
+}
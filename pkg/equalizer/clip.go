@@ -0,0 +1,108 @@
+package equalizer
+
+import "math"
+
+// ClipMode represents what the ClipGuard does with the samples above the full scale.
+type ClipMode int
+
+// ClipMode constants are the ways to treat the samples above the full scale.
+const (
+	// DetectClip only counts the samples whose absolute value exceeds 1.0 and passes the signal as it is.
+	DetectClip ClipMode = iota
+
+	// SoftClip bends the samples above the knee smoothly toward 1.0, so the output never exceeds the full scale.
+	SoftClip
+
+	// HardClip limits the samples between -1.0 and 1.0.
+	HardClip
+)
+
+// ClipGuard counts the samples exceeding the full scale after the equalizer and optionally clips them, so the boost does
+// not silently hard-clip the integer output file.
+type ClipGuard struct {
+	mode ClipMode
+	knee float64
+
+	position int64
+	clipped  int64
+	first    int64
+	peak     float64
+}
+
+// NewClipGuard returns the clip guard.
+//
+// Parameters:
+//
+//     - mode ... DetectClip, SoftClip or HardClip.
+//     - knee ... Level where SoftClip starts bending the signal, between 0 and 1. e.g. 0.9
+//
+// NOTE: The samples are counted before they are clipped, so the count tells how often the clipping happened.
+func NewClipGuard(mode ClipMode, knee float64) *ClipGuard {
+	return &ClipGuard{
+		mode:  mode,
+		knee:  math.Max(0.0, math.Min(knee, 0.999)),
+		first: -1,
+	}
+}
+
+// Apply counts the input above the full scale and returns it clipped by the mode.
+func (c *ClipGuard) Apply(input float64) float64 {
+	x := math.Abs(input)
+
+	if x > 1.0 {
+		if c.first < 0 {
+			c.first = c.position
+		}
+
+		c.clipped++
+	}
+
+	c.peak = math.Max(c.peak, x)
+	c.position++
+
+	switch c.mode {
+	case SoftClip:
+		if x <= c.knee {
+			return input
+		}
+
+		// The tanh curve continues the slope 1 at the knee and approaches 1.0.
+		width := 1.0 - c.knee
+
+		return math.Copysign(c.knee+width*math.Tanh((x-c.knee)/width), input)
+	case HardClip:
+		return math.Max(-1.0, math.Min(1.0, input))
+	}
+
+	return input
+}
+
+// ProcessBuffer applies the clip guard to the buffer in place.
+func (c *ClipGuard) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = c.Apply(buffer[i])
+	}
+}
+
+// Clipped returns the number of the input samples whose absolute value exceeded 1.0.
+func (c *ClipGuard) Clipped() int64 {
+	return c.clipped
+}
+
+// First returns the position of the first clipped sample counted from the start or Reset. It returns -1 when nothing is clipped.
+func (c *ClipGuard) First() int64 {
+	return c.first
+}
+
+// Peak returns the largest absolute input in dBFS before the clipping.
+func (c *ClipGuard) Peak() float64 {
+	return 20.0 * math.Log10(c.peak)
+}
+
+// Reset clears the counters.
+func (c *ClipGuard) Reset() {
+	c.position = 0
+	c.clipped = 0
+	c.first = -1
+	c.peak = 0.0
+}
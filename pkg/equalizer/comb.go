@@ -0,0 +1,147 @@
+package equalizer
+
+import "math"
+
+// CombName represents the kind of comb filters.
+type CombName int
+
+// CombName constants are comb filter names.
+const (
+	Feedforward CombName = iota
+	Feedback
+)
+
+// Comb holds the comb filter parameters.
+type Comb struct {
+	name  CombName
+	delay float64
+	gain  float64
+
+	// delay line
+	buffer   []float64
+	position int
+}
+
+// NewFeedforwardComb returns the feedforward comb filter. y[n] = x[n] + gain * x[n-delay]
+//
+// Parameters:
+//
+//     - delay ... Delay length in samples. Fractional delay is linearly interpolated.
+//     - gain ... Feedforward gain.
+//
+// NOTE: delay must be greater than or equal to 1.
+func NewFeedforwardComb(delay, gain float64) *Comb {
+	return newComb(Feedforward, delay, gain)
+}
+
+// NewFeedbackComb returns the feedback comb filter. y[n] = x[n] + gain * y[n-delay]
+//
+// Parameters:
+//
+//     - delay ... Delay length in samples. Fractional delay is linearly interpolated.
+//     - gain ... Feedback gain.
+//
+// NOTE: delay must be greater than or equal to 1. The absolute value of gain must be less than 1, otherwise the filter is unstable.
+func NewFeedbackComb(delay, gain float64) *Comb {
+	return newComb(Feedback, delay, gain)
+}
+
+// DelayFromFrequency returns the delay length in samples whose comb teeth are spaced by the frequency in Hz.
+func DelayFromFrequency(sampleRate, frequency float64) float64 {
+	return sampleRate / frequency
+}
+
+func newComb(name CombName, delay, gain float64) *Comb {
+	c := &Comb{
+		name: name,
+		gain: gain,
+	}
+
+	c.SetDelay(delay)
+
+	return c
+}
+
+// Name returns the comb filter name.
+func (c *Comb) Name() CombName {
+	return c.name
+}
+
+// Delay returns the delay length in samples.
+func (c *Comb) Delay() float64 {
+	return c.delay
+}
+
+// SetDelay sets the delay length in samples. The delay line grows when needed, so the delay can be modulated while processing, e.g. flanging.
+func (c *Comb) SetDelay(delay float64) {
+	if delay < 1.0 {
+		delay = 1.0
+	}
+
+	c.delay = delay
+
+	size := int(math.Floor(delay)) + 2
+
+	if size <= len(c.buffer) {
+		return
+	}
+
+	// Keep the delayed samples in order, so growing the delay line does not click.
+	buffer := make([]float64, size)
+
+	for i := 1; i <= len(c.buffer); i++ {
+		buffer[size-i] = c.buffer[(c.position-i+len(c.buffer))%len(c.buffer)]
+	}
+
+	c.buffer = buffer
+	c.position = 0
+}
+
+// Gain returns the feedforward or feedback gain.
+func (c *Comb) Gain() float64 {
+	return c.gain
+}
+
+// SetGain sets the feedforward or feedback gain.
+func (c *Comb) SetGain(gain float64) {
+	c.gain = gain
+}
+
+// Apply applies the comb filter and returns the value.
+func (c *Comb) Apply(input float64) float64 {
+	n := len(c.buffer)
+	integer := int(c.delay)
+	fraction := c.delay - float64(integer)
+
+	x0 := c.buffer[(c.position-integer+n)%n]
+	x1 := c.buffer[(c.position-integer-1+n)%n]
+	delayed := x0 + (x1-x0)*fraction
+
+	output := input + c.gain*delayed
+
+	if c.name == Feedback {
+		c.buffer[c.position] = output
+	} else {
+		c.buffer[c.position] = input
+	}
+
+	c.position = (c.position + 1) % n
+
+	return output
+}
+
+// ProcessBuffer applies the comb filter to the buffer in place.
+func (c *Comb) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = c.Apply(buffer[i])
+	}
+}
+
+// Reset clears the delay line.
+func (c *Comb) Reset() {
+	for i := range c.buffer {
+		c.buffer[i] = 0.0
+	}
+
+	c.position = 0
+}
@@ -0,0 +1,180 @@
+package equalizer
+
+import (
+	"math"
+
+	"github.com/moutend/go-equalizer/internal/fft"
+)
+
+// Convolver convolves the signal with the impulse response, e.g. the measured room or headphone correction.
+// It uses the uniformly partitioned overlap-save method, so the long impulse response is processed with the latency of one block.
+type Convolver struct {
+	blockSize int
+	plan      *fft.Plan
+
+	// delay of the linear-phase impulse response in samples
+	linearPhaseDelay int
+
+	// spectra of the impulse response partitions
+	partitions [][]complex128
+
+	// frequency domain delay line of the input blocks
+	delayLine [][]complex128
+	index     int
+
+	previous    []float64
+	input       []float64
+	output      []float64
+	position    int
+	accumulator []complex128
+}
+
+// NewConvolver returns the convolver.
+//
+// Parameters:
+//
+//     - ir ... Impulse response.
+//     - blockSize ... Block size in samples. It is rounded up to the power of 2 and it is the latency of the convolver. e.g. 512
+func NewConvolver(ir []float64, blockSize int) *Convolver {
+	blockSize = fft.NextPowerOfTwo(blockSize)
+	size := 2 * blockSize
+	count := (len(ir) + blockSize - 1) / blockSize
+
+	if count == 0 {
+		count = 1
+	}
+
+	c := &Convolver{
+		blockSize:   blockSize,
+		plan:        fft.NewPlan(size),
+		partitions:  make([][]complex128, count),
+		delayLine:   make([][]complex128, count),
+		previous:    make([]float64, blockSize),
+		input:       make([]float64, blockSize),
+		output:      make([]float64, blockSize),
+		accumulator: make([]complex128, size),
+	}
+
+	if symmetric(ir) {
+		c.linearPhaseDelay = (len(ir) - 1) / 2
+	}
+
+	for k := range c.partitions {
+		partition := make([]complex128, size)
+
+		for i := 0; i < blockSize && k*blockSize+i < len(ir); i++ {
+			partition[i] = complex(ir[k*blockSize+i], 0.0)
+		}
+
+		c.plan.Forward(partition)
+		c.partitions[k] = partition
+		c.delayLine[k] = make([]complex128, size)
+	}
+
+	return c
+}
+
+// Latency returns the latency in samples. It is the block size plus the delay of the impulse response when the impulse response is symmetric, i.e. the linear-phase FIR filter.
+func (c *Convolver) Latency() int {
+	return c.blockSize + c.linearPhaseDelay
+}
+
+// Apply applies the convolution and returns the value delayed by the block size.
+func (c *Convolver) Apply(input float64) float64 {
+	output := c.output[c.position]
+
+	c.input[c.position] = input
+	c.position++
+
+	if c.position == c.blockSize {
+		c.process()
+		c.position = 0
+	}
+
+	return output
+}
+
+// ProcessBuffer applies the convolution to the buffer in place.
+func (c *Convolver) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = c.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables.
+func (c *Convolver) Reset() {
+	for _, spectrum := range c.delayLine {
+		for i := range spectrum {
+			spectrum[i] = 0
+		}
+	}
+	for i := 0; i < c.blockSize; i++ {
+		c.previous[i] = 0.0
+		c.input[i] = 0.0
+		c.output[i] = 0.0
+	}
+
+	c.index = 0
+	c.position = 0
+}
+
+// process convolves the input block and stores the result to the output block.
+func (c *Convolver) process() {
+	b := c.blockSize
+	spectrum := c.delayLine[c.index]
+
+	for i := 0; i < b; i++ {
+		spectrum[i] = complex(c.previous[i], 0.0)
+		spectrum[b+i] = complex(c.input[i], 0.0)
+	}
+
+	c.plan.Forward(spectrum)
+
+	for i := range c.accumulator {
+		c.accumulator[i] = 0
+	}
+
+	count := len(c.partitions)
+
+	for k, partition := range c.partitions {
+		x := c.delayLine[(c.index-k+count)%count]
+
+		for i := range c.accumulator {
+			c.accumulator[i] += x[i] * partition[i]
+		}
+	}
+
+	c.plan.Inverse(c.accumulator)
+
+	// The first half is aliased by the circular convolution, so the second half is the output.
+	for i := 0; i < b; i++ {
+		c.output[i] = real(c.accumulator[b+i])
+	}
+
+	copy(c.previous, c.input)
+	c.index = (c.index + 1) % count
+}
+
+// symmetric returns true when the impulse response is symmetric or antisymmetric around its center, which means the constant group delay of (len(ir)-1)/2 samples.
+func symmetric(ir []float64) bool {
+	if len(ir) < 2 {
+		return false
+	}
+
+	peak := 0.0
+
+	for _, x := range ir {
+		peak = math.Max(peak, math.Abs(x))
+	}
+
+	even, odd := true, true
+	tolerance := 1e-9 * peak
+
+	for i := 0; i < len(ir)/2; i++ {
+		j := len(ir) - 1 - i
+		even = even && math.Abs(ir[i]-ir[j]) <= tolerance
+		odd = odd && math.Abs(ir[i]+ir[j]) <= tolerance
+	}
+
+	return peak > 0.0 && (even || odd)
+}
@@ -0,0 +1,87 @@
+package equalizer
+
+import "math"
+
+// EnvelopeMode represents the detection mode of the envelope follower.
+type EnvelopeMode int
+
+// EnvelopeMode constants are detection modes.
+const (
+	PeakEnvelope EnvelopeMode = iota
+	RMSEnvelope
+)
+
+// EnvelopeFollower tracks the amplitude envelope of the signal.
+type EnvelopeFollower struct {
+	mode EnvelopeMode
+
+	// smoothing coefficients
+	attack  float64
+	release float64
+
+	// state variables
+	meanSquare float64
+	envelope   float64
+}
+
+// NewEnvelopeFollower returns the envelope follower.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - attack ... Attack time in seconds. e.g. 0.01
+//     - release ... Release time in seconds. e.g. 0.1
+//     - mode ... PeakEnvelope or RMSEnvelope.
+//
+// NOTE: attack and release less than or equal to 0 mean that the envelope follows the signal immediately. In RMSEnvelope mode, the mean square is averaged over the release time and the attack time limits how fast the envelope rises.
+func NewEnvelopeFollower(sampleRate, attack, release float64, mode EnvelopeMode) *EnvelopeFollower {
+	return &EnvelopeFollower{
+		mode:    mode,
+		attack:  smoothingCoefficient(sampleRate, attack),
+		release: smoothingCoefficient(sampleRate, release),
+	}
+}
+
+// Mode returns the detection mode.
+func (e *EnvelopeFollower) Mode() EnvelopeMode {
+	return e.mode
+}
+
+// Apply feeds the input to the envelope follower and returns the current envelope.
+func (e *EnvelopeFollower) Apply(input float64) float64 {
+	x := math.Abs(input)
+	coefficient := e.release
+
+	if e.mode == RMSEnvelope {
+		e.meanSquare = e.release*e.meanSquare + (1.0-e.release)*input*input
+		x = math.Sqrt(e.meanSquare)
+		coefficient = 0.0
+	}
+	if x > e.envelope {
+		coefficient = e.attack
+	}
+
+	e.envelope = coefficient*e.envelope + (1.0-coefficient)*x
+
+	return e.envelope
+}
+
+// Value returns the current envelope without feeding the input.
+func (e *EnvelopeFollower) Value() float64 {
+	return e.envelope
+}
+
+// Reset clears the state of the envelope follower.
+func (e *EnvelopeFollower) Reset() {
+	e.meanSquare = 0.0
+	e.envelope = 0.0
+}
+
+// smoothingCoefficient returns the one-pole coefficient which reaches 1-1/e of the target after the given time.
+func smoothingCoefficient(sampleRate, time float64) float64 {
+	if time <= 0.0 || sampleRate <= 0.0 {
+		return 0.0
+	}
+
+	return math.Exp(-1.0 / (time * sampleRate))
+}
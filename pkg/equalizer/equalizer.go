@@ -10,9 +10,20 @@
 //     - Low-shelf
 //     - High-shelf
 //     - Peaking
+//
+// The filters are not limited to the audio. Any sample rate works as long as the frequency is in the same unit,
+// e.g. the high-pass filter at 0.005 Hz for the sensor sampled every minute is NewHighPass(1.0/60.0, 0.005, 0.707).
+// The frequency must be between 0 and the Nyquist frequency, which is the half of the sample rate, see CheckFrequency.
+// The coefficients are computed without the cancellation at the low frequency, but the state of the filter still loses
+// the precision when the frequency is below about 1/100000 of the sample rate, so decimate such signal before filtering.
+// WithHighPrecision designs the coefficients with the extended precision, and NewErrorFeedback processes them with the
+// smaller rounding error.
 package equalizer
 
-import "math"
+import (
+	"math"
+	"math/cmplx"
+)
 
 // Mode represents the kind of digital filters.
 type FilterName int
@@ -28,6 +39,7 @@ const (
 	LowShelf
 	HighShelf
 	Peaking
+	Custom
 )
 
 // Pi value is used as the default pi value in this package.
@@ -38,11 +50,16 @@ var (
 )
 
 // SetPi sets the pi value. After calling this function, call the constructor function such as NewLowPass().
+//
+// Deprecated: The pi value only changes the rounding of the design. Use New with WithHighPrecision, which designs the
+// coefficients with the extended precision, for the very low frequency relative to the sample rate.
 func SetPi(value float64) {
 	p = value
 }
 
 // UnsetPi sets the pi value to default value.
+//
+// Deprecated: See SetPi.
 func UnsetPi() {
 	p = Pi
 }
@@ -51,19 +68,35 @@ func UnsetPi() {
 type Filter struct {
 	name FilterName
 
+	// design parameters
+	sampleRate float64
+	frequency  float64
+	q          float64 // Q value or band width, depending on the filter
+	gain       float64
+
 	// state variables
 	in1  float64
 	in2  float64
 	out1 float64
 	out2 float64
 
-	// digital filter parameters
-	a0 float64
+	// scale is a0 of the designed coefficients. The coefficients below are divided by it once at the design time,
+	// so a0 is 1 and Apply does not divide per sample.
+	scale float64
+
+	// digital filter parameters normalized by a0
 	a1 float64
 	a2 float64
 	b0 float64
 	b1 float64
 	b2 float64
+
+	// amounts of the unprocessed signal ramped by SetBypassed and SetMix
+	bypass ramp
+	dry    ramp
+
+	// method is how the filter is designed. It is kept when the filter is redesigned.
+	method designMethod
 }
 
 // IsZero returns true when the f is not initialized.
@@ -76,13 +109,58 @@ func (f *Filter) Name() FilterName {
 	return f.name
 }
 
+// SampleRate returns the sample rate in Hz.
+func (f *Filter) SampleRate() float64 {
+	return f.sampleRate
+}
+
+// Frequency returns the cut off or center frequency in Hz.
+func (f *Filter) Frequency() float64 {
+	return f.frequency
+}
+
+// SetFrequency redesigns the filter with the new frequency. The state variables are preserved, so it can be called while processing the signal.
+func (f *Filter) SetFrequency(frequency float64) {
+	f.setCoefficients(designWith(f.method, f.name, f.sampleRate, frequency, f.q, f.gain))
+}
+
+// Q returns the Q value, or the band width for the band-pass, band-reject and peaking filters.
+func (f *Filter) Q() float64 {
+	return f.q
+}
+
+// SetQ redesigns the filter with the new Q value, or the new band width for the band-pass, band-reject and peaking filters. The state variables are preserved.
+func (f *Filter) SetQ(q float64) {
+	f.setCoefficients(designWith(f.method, f.name, f.sampleRate, f.frequency, q, f.gain))
+}
+
+// Gain returns the gain in dB. It is used by the low-shelf, high-shelf and peaking filters.
+func (f *Filter) Gain() float64 {
+	return f.gain
+}
+
+// SetGain redesigns the filter with the new gain in dB. The state variables are preserved.
+func (f *Filter) SetGain(gain float64) {
+	f.setCoefficients(designWith(f.method, f.name, f.sampleRate, f.frequency, f.q, gain))
+}
+
+// Reset clears the state variables.
+func (f *Filter) Reset() {
+	f.in1 = 0.0
+	f.in2 = 0.0
+	f.out1 = 0.0
+	f.out2 = 0.0
+}
+
 // Apply applies the current filter and returns the value.
+//
+// NOTE: NaN or Inf input makes all the following outputs NaN until Reset. Wrap the filter with NewGapHandler for the data with the gaps.
 func (f *Filter) Apply(input float64) float64 {
-	output := (f.b0/f.a0)*input +
-		(f.b1/f.a0)*f.in1 +
-		(f.b2/f.a0)*f.in2 -
-		(f.a1/f.a0)*f.out1 -
-		(f.a2/f.a0)*f.out2
+	output := f.b0*input +
+		f.b1*f.in1 +
+		f.b2*f.in2 -
+		f.a1*f.out1 -
+		f.a2*f.out2
 
 	f.in2 = f.in1
 	f.in1 = input
@@ -90,9 +168,45 @@ func (f *Filter) Apply(input float64) float64 {
 	f.out2 = f.out1
 	f.out1 = output
 
+	if f.bypass.active() || f.dry.active() {
+		return mixDry(input, output, f.dry.advance(), f.bypass.advance())
+	}
+
 	return output
 }
 
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (f *Filter) FrequencyResponse(frequency float64) complex128 {
+	w := 2.0 * p * frequency / f.sampleRate
+	z1 := cmplx.Exp(complex(0.0, -w))
+	z2 := z1 * z1
+
+	numerator := complex(f.b0, 0.0) + complex(f.b1, 0.0)*z1 + complex(f.b2, 0.0)*z2
+	denominator := 1.0 + complex(f.a1, 0.0)*z1 + complex(f.a2, 0.0)*z2
+
+	return numerator / denominator
+}
+
+// GroupDelay returns the group delay at the frequency in Hz. The delay is in seconds, multiply it by the sample rate to get the delay in samples.
+func (f *Filter) GroupDelay(frequency float64) float64 {
+	w := 2.0 * p * frequency / f.sampleRate
+	z1 := cmplx.Exp(complex(0.0, -w))
+	z2 := z1 * z1
+
+	// The group delay of the polynomial sum(c[k]*z^-k) is Re(sum(k*c[k]*z^-k) / sum(c[k]*z^-k)) in samples.
+	numerator := (complex(f.b1, 0.0)*z1 + complex(2.0*f.b2, 0.0)*z2) / (complex(f.b0, 0.0) + complex(f.b1, 0.0)*z1 + complex(f.b2, 0.0)*z2)
+	denominator := (complex(f.a1, 0.0)*z1 + complex(2.0*f.a2, 0.0)*z2) / (1.0 + complex(f.a1, 0.0)*z1 + complex(f.a2, 0.0)*z2)
+
+	return (real(numerator) - real(denominator)) / f.sampleRate
+}
+
+// ProcessBuffer applies the current filter to the buffer in place.
+func (f *Filter) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = f.Apply(buffer[i])
+	}
+}
+
 // NewLowPass returns the low-pass filter.
 //
 // Parameters:
@@ -101,20 +215,25 @@ func (f *Filter) Apply(input float64) float64 {
 //     - frequency ... Cut off frequency in Hz.
 //     - q ... Q value.
 //
-// NOTE: q must be greater than 0.
+// NOTE: q must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewLowPass(sampleRate, frequency, q float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	alpha := math.Sin(w0) / (2.0 * q)
 
-	return &Filter{
-		name: LowPass,
-		a0:   1.0 + alpha,
-		a1:   -2.0 * math.Cos(w0),
-		a2:   1.0 - alpha,
-		b0:   (1.0 - math.Cos(w0)) / 2.0,
-		b1:   1.0 - math.Cos(w0),
-		b2:   (1.0 - math.Cos(w0)) / 2.0,
-	}
+	return normalized(&Filter{
+		name:       LowPass,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		scale:      1.0 + alpha,
+		a1:         -2.0 * math.Cos(w0),
+		a2:         1.0 - alpha,
+		b0:         versine(w0) / 2.0,
+		b1:         versine(w0),
+		b2:         versine(w0) / 2.0,
+	})
 }
 
 // NewHighPass returns the high-pass filter.
@@ -125,20 +244,25 @@ func NewLowPass(sampleRate, frequency, q float64) *Filter {
 //     - frequency ... Cut off frequency in Hz.
 //     - q ... Q value.
 //
-// NOTE: q must be greater than 0.
+// NOTE: q must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewHighPass(sampleRate, frequency, q float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	alpha := math.Sin(w0) / (2.0 * q)
 
-	return &Filter{
-		name: HighPass,
-		a0:   1.0 + alpha,
-		a1:   -2.0 * math.Cos(w0),
-		a2:   1.0 - alpha,
-		b0:   (1.0 + math.Cos(w0)) / 2.0,
-		b1:   -1.0 * (1.0 + math.Cos(w0)),
-		b2:   (1.0 + math.Cos(w0)) / 2.0,
-	}
+	return normalized(&Filter{
+		name:       HighPass,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		scale:      1.0 + alpha,
+		a1:         -2.0 * math.Cos(w0),
+		a2:         1.0 - alpha,
+		b0:         (1.0 + math.Cos(w0)) / 2.0,
+		b1:         -1.0 * (1.0 + math.Cos(w0)),
+		b2:         (1.0 + math.Cos(w0)) / 2.0,
+	})
 }
 
 // NewAllPass returns the all-pass filter.
@@ -149,20 +273,25 @@ func NewHighPass(sampleRate, frequency, q float64) *Filter {
 //     - frequency ... Cut off frequency in Hz.
 //     - q ... Q value.
 //
-// NOTE: q must be greater than 0.
+// NOTE: q must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewAllPass(sampleRate, frequency, q float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	alpha := math.Sin(w0) / (2.0 * q)
 
-	return &Filter{
-		name: AllPass,
-		a0:   1.0 + alpha,
-		a1:   -2.0 * math.Cos(w0),
-		a2:   1.0 - alpha,
-		b0:   1.0 - alpha,
-		b1:   -2.0 * math.Cos(w0),
-		b2:   1.0 + alpha,
-	}
+	return normalized(&Filter{
+		name:       AllPass,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		scale:      1.0 + alpha,
+		a1:         -2.0 * math.Cos(w0),
+		a2:         1.0 - alpha,
+		b0:         1.0 - alpha,
+		b1:         -2.0 * math.Cos(w0),
+		b2:         1.0 + alpha,
+	})
 }
 
 // NewBandPass returns the band-pass filter.
@@ -173,20 +302,25 @@ func NewAllPass(sampleRate, frequency, q float64) *Filter {
 //     - frequency ... Cut off frequency in Hz.
 //     - width ... Band width.
 //
-// NOTE: width must be greater than 0.
+// NOTE: width must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewBandPass(sampleRate, frequency, width float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	alpha := math.Sin(w0) * math.Sinh(math.Log(2.0)/2.0*width*w0/math.Sin(w0))
 
-	return &Filter{
-		name: BandPass,
-		a0:   1.0 + alpha,
-		a1:   -2.0 * math.Cos(w0),
-		a2:   1.0 - alpha,
-		b0:   alpha,
-		b1:   0.0,
-		b2:   -1.0 * alpha,
-	}
+	return normalized(&Filter{
+		name:       BandPass,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          width,
+		scale:      1.0 + alpha,
+		a1:         -2.0 * math.Cos(w0),
+		a2:         1.0 - alpha,
+		b0:         alpha,
+		b1:         0.0,
+		b2:         -1.0 * alpha,
+	})
 }
 
 // NewBandReject returns the band-reject filter.
@@ -197,20 +331,25 @@ func NewBandPass(sampleRate, frequency, width float64) *Filter {
 //     - frequency ... Cut off frequency in Hz.
 //     - width ... Band width.
 //
-// NOTE: width must be greater than 0.
+// NOTE: width must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewBandReject(sampleRate, frequency, width float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	alpha := math.Sin(w0) * math.Sinh(math.Log(2.0)/2.0*width*w0/math.Sin(w0))
 
-	return &Filter{
-		name: BandReject,
-		a0:   1.0 + alpha,
-		a1:   -2.0 * math.Cos(w0),
-		a2:   1.0 - alpha,
-		b0:   1.0,
-		b1:   -2.0 * math.Cos(w0),
-		b2:   1.0,
-	}
+	return normalized(&Filter{
+		name:       BandReject,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          width,
+		scale:      1.0 + alpha,
+		a1:         -2.0 * math.Cos(w0),
+		a2:         1.0 - alpha,
+		b0:         1.0,
+		b1:         -2.0 * math.Cos(w0),
+		b2:         1.0,
+	})
 }
 
 // NewLowShelf returns the low-shelf filter.
@@ -222,21 +361,27 @@ func NewBandReject(sampleRate, frequency, width float64) *Filter {
 //     - q ... Q value.
 //     - gain ... Gain value in dB.
 //
-// NOTE: q must be greater than 0.
+// NOTE: q must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewLowShelf(sampleRate, frequency, q, gain float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	a := math.Pow(10.0, (gain / 40.0))
 	beta := math.Sqrt(a) / q
 
-	return &Filter{
-		name: LowShelf,
-		a0:   (a + 1.0) + (a-1.0)*math.Cos(w0) + beta*math.Sin(w0),
-		a1:   -2.0 * ((a - 1.0) + (a+1.0)*math.Cos(w0)),
-		a2:   (a + 1.0) + (a-1.0)*math.Cos(w0) - beta*math.Sin(w0),
-		b0:   a * ((a + 1.0) - (a-1.0)*math.Cos(w0) + beta*math.Sin(w0)),
-		b1:   2.0 * a * ((a - 1.0) - (a+1.0)*math.Cos(w0)),
-		b2:   a * ((a + 1.0) - (a-1.0)*math.Cos(w0) - beta*math.Sin(w0)),
-	}
+	return normalized(&Filter{
+		name:       LowShelf,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		gain:       gain,
+		scale:      (a + 1.0) + (a-1.0)*math.Cos(w0) + beta*math.Sin(w0),
+		a1:         -2.0 * ((a - 1.0) + (a+1.0)*math.Cos(w0)),
+		a2:         (a + 1.0) + (a-1.0)*math.Cos(w0) - beta*math.Sin(w0),
+		b0:         a * ((a + 1.0) - (a-1.0)*math.Cos(w0) + beta*math.Sin(w0)),
+		b1:         2.0 * a * ((a - 1.0) - (a+1.0)*math.Cos(w0)),
+		b2:         a * ((a + 1.0) - (a-1.0)*math.Cos(w0) - beta*math.Sin(w0)),
+	})
 }
 
 // NewHighShelf returns the high-shelf filter.
@@ -248,21 +393,27 @@ func NewLowShelf(sampleRate, frequency, q, gain float64) *Filter {
 //     - q ... Q value.
 //     - gain ... Gain value in dB.
 //
-// NOTE: q must be greater than 0.
+// NOTE: q must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewHighShelf(sampleRate, frequency, q, gain float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	a := math.Pow(10.0, (gain / 40.0))
 	beta := math.Sqrt(a) / q
 
-	return &Filter{
-		name: HighShelf,
-		a0:   (a + 1.0) - (a-1.0)*math.Cos(w0) + beta*math.Sin(w0),
-		a1:   2.0 * ((a - 1.0) - (a+1.0)*math.Cos(w0)),
-		a2:   (a + 1.0) - (a-1.0)*math.Cos(w0) - beta*math.Sin(w0),
-		b0:   a * ((a + 1.0) + (a-1.0)*math.Cos(w0) + beta*math.Sin(w0)),
-		b1:   -2.0 * a * ((a - 1.0) + (a+1.0)*math.Cos(w0)),
-		b2:   a * ((a + 1.0) + (a-1.0)*math.Cos(w0) - beta*math.Sin(w0)),
-	}
+	return normalized(&Filter{
+		name:       HighShelf,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		gain:       gain,
+		scale:      (a + 1.0) - (a-1.0)*math.Cos(w0) + beta*math.Sin(w0),
+		a1:         2.0 * ((a - 1.0) - (a+1.0)*math.Cos(w0)),
+		a2:         (a + 1.0) - (a-1.0)*math.Cos(w0) - beta*math.Sin(w0),
+		b0:         a * ((a + 1.0) + (a-1.0)*math.Cos(w0) + beta*math.Sin(w0)),
+		b1:         -2.0 * a * ((a - 1.0) + (a+1.0)*math.Cos(w0)),
+		b2:         a * ((a + 1.0) + (a-1.0)*math.Cos(w0) - beta*math.Sin(w0)),
+	})
 }
 
 // NewPeaking returns the peaking-shelf filter.
@@ -274,19 +425,134 @@ func NewHighShelf(sampleRate, frequency, q, gain float64) *Filter {
 //     - width ... Width value.
 //     - gain ... Gain value in dB.
 //
-// NOTE: width must be greater than 0.
+// NOTE: width must be greater than 0. The frequency not below the Nyquist frequency is designed at 0.49 of the sample
+// rate, so use New or CheckFrequency to reject the user input instead.
 func NewPeaking(sampleRate, frequency, width, gain float64) *Filter {
+	frequency = nyquistLimit(sampleRate, frequency)
 	w0 := 2.0 * p * frequency / sampleRate
 	alpha := math.Sin(w0) * math.Sinh(math.Log(2.0)/2.0*width*w0/math.Sin(w0))
 	a := math.Pow(10.0, (gain / 40.0))
 
-	return &Filter{
-		name: Peaking,
-		a0:   1.0 + alpha/a,
-		a1:   -2.0 * math.Cos(w0),
-		a2:   1.0 - alpha/a,
-		b0:   1.0 + alpha*a,
-		b1:   -2.0 * math.Cos(w0),
-		b2:   1.0 - alpha*a,
+	return normalized(&Filter{
+		name:       Peaking,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          width,
+		gain:       gain,
+		scale:      1.0 + alpha/a,
+		a1:         -2.0 * math.Cos(w0),
+		a2:         1.0 - alpha/a,
+		b0:         1.0 + alpha*a,
+		b1:         -2.0 * math.Cos(w0),
+		b2:         1.0 - alpha*a,
+	})
+}
+
+// NewCustom returns the filter with the given coefficients.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - b0, b1, b2 ... Numerator coefficients.
+//     - a0, a1, a2 ... Denominator coefficients.
+//
+// NOTE: a0 must not be 0. The custom filter cannot be redesigned with SetFrequency, SetQ or SetGain.
+func NewCustom(sampleRate, b0, b1, b2, a0, a1, a2 float64) *Filter {
+	return normalized(&Filter{
+		name:       Custom,
+		sampleRate: sampleRate,
+		scale:      a0,
+		a1:         a1,
+		a2:         a2,
+		b0:         b0,
+		b1:         b1,
+		b2:         b2,
+	})
+}
+
+// normalized divides the coefficients of the f by its scale, which is a0 of the design, and returns the f.
+func normalized(f *Filter) *Filter {
+	f.a1 /= f.scale
+	f.a2 /= f.scale
+	f.b0 /= f.scale
+	f.b1 /= f.scale
+	f.b2 /= f.scale
+
+	return f
+}
+
+// Coefficients returns the coefficients as designed, e.g. a0 = 1 + alpha of the cookbook. They are the normalized
+// coefficients multiplied by a0, so the rounding error of the last digit may differ from the design.
+func (f *Filter) Coefficients() (b0, b1, b2, a0, a1, a2 float64) {
+	return f.b0 * f.scale, f.b1 * f.scale, f.b2 * f.scale, f.scale, f.a1 * f.scale, f.a2 * f.scale
+}
+
+// NormalizedCoefficients returns the coefficients divided by a0, which most formats expect, e.g. the biquads of
+// PipeWire, CamillaDSP and SciPy. a0 is always 1.
+func (f *Filter) NormalizedCoefficients() (b0, b1, b2, a0, a1, a2 float64) {
+	return f.b0, f.b1, f.b2, 1.0, f.a1, f.a2
+}
+
+// versine returns 1 - cos(w) without the cancellation for the small w, e.g. the low cut off frequency at the high sample rate.
+func versine(w float64) float64 {
+	s := math.Sin(w / 2.0)
+
+	return 2.0 * s * s
+}
+
+// design returns the filter designed with the given parameters. It returns nil when the filter cannot be designed from the parameters.
+func design(name FilterName, sampleRate, frequency, q, gain float64) *Filter {
+	switch name {
+	case LowPass:
+		return NewLowPass(sampleRate, frequency, q)
+	case HighPass:
+		return NewHighPass(sampleRate, frequency, q)
+	case AllPass:
+		return NewAllPass(sampleRate, frequency, q)
+	case BandPass:
+		return NewBandPass(sampleRate, frequency, q)
+	case BandReject:
+		return NewBandReject(sampleRate, frequency, q)
+	case LowShelf:
+		return NewLowShelf(sampleRate, frequency, q, gain)
+	case HighShelf:
+		return NewHighShelf(sampleRate, frequency, q, gain)
+	case Peaking:
+		return NewPeaking(sampleRate, frequency, q, gain)
 	}
+
+	return designRegistered(name, sampleRate, frequency, q, gain)
+}
+
+// setCoefficients copies the design parameters and the coefficients of the g to the f. It does nothing when the g is nil, e.g. the f is the custom filter which cannot be redesigned.
+func (f *Filter) setCoefficients(g *Filter) {
+	if g == nil {
+		return
+	}
+
+	f.sampleRate = g.sampleRate
+	f.frequency = g.frequency
+	f.q = g.q
+	f.gain = g.gain
+
+	f.scale = g.scale
+	f.a1 = g.a1
+	f.a2 = g.a2
+	f.b0 = g.b0
+	f.b1 = g.b1
+	f.b2 = g.b2
+}
+
+// interpolateCoefficients sets the coefficients of the f to the linear interpolation between the normalized coefficients of the from and the to.
+func (f *Filter) interpolateCoefficients(from, to *Filter, t float64) {
+	lerp := func(x, y float64) float64 {
+		return x + (y-x)*t
+	}
+
+	f.scale = 1.0
+	f.a1 = lerp(from.a1, to.a1)
+	f.a2 = lerp(from.a2, to.a2)
+	f.b0 = lerp(from.b0, to.b0)
+	f.b1 = lerp(from.b1, to.b1)
+	f.b2 = lerp(from.b2, to.b2)
 }
@@ -0,0 +1,64 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestLowSampleRate(t *testing.T) {
+	// The sensor sampled about every minute, filtered at 0.005 Hz which is 60% of the Nyquist frequency.
+	sampleRate := 0.0166
+	frequency := 0.005
+
+	if err := CheckFrequency(sampleRate, frequency); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckFrequency(sampleRate, 0.01); err == nil {
+		t.Error("0.01 Hz above the Nyquist frequency is accepted")
+	}
+
+	tests := []struct {
+		name    string
+		filter  *Filter
+		dc      float64
+		nyquist float64
+	}{
+		{"low-pass", NewLowPass(sampleRate, frequency, 1.0/math.Sqrt2), 1.0, 0.0},
+		{"high-pass", NewHighPass(sampleRate, frequency, 1.0/math.Sqrt2), 0.0, 1.0},
+	}
+	for _, test := range tests {
+		f := test.filter
+		name := test.name
+
+		if !f.Stable() {
+			t.Errorf("%s: unstable, poles %v", name, f.Poles())
+		}
+		if gain := cmplx.Abs(f.FrequencyResponse(frequency)); math.Abs(gain-1.0/math.Sqrt2) > 1e-9 {
+			t.Errorf("%s: gain at the cut off frequency is %v, want -3 dB", name, gain)
+		}
+		if gain := cmplx.Abs(f.FrequencyResponse(0.0)); math.Abs(gain-test.dc) > 1e-9 {
+			t.Errorf("%s: gain at DC is %v, want %v", name, gain, test.dc)
+		}
+		if gain := cmplx.Abs(f.FrequencyResponse(sampleRate / 2.0)); math.Abs(gain-test.nyquist) > 1e-9 {
+			t.Errorf("%s: gain at the Nyquist frequency is %v, want %v", name, gain, test.nyquist)
+		}
+
+		// The impulse response must decay, because the poles are well inside the unit circle at this ratio.
+		response := make([]float64, 200)
+		response[0] = 1.0
+
+		f.ProcessBuffer(response)
+
+		if math.Abs(response[0]) < 1e-3 {
+			t.Errorf("%s: impulse response starts with %v", name, response[0])
+		}
+		for i, value := range response[100:] {
+			if math.IsNaN(value) || math.Abs(value) > 1e-12 {
+				t.Errorf("%s: impulse response %v at %d does not decay", name, value, i+100)
+
+				break
+			}
+		}
+	}
+}
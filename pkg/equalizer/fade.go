@@ -0,0 +1,74 @@
+package equalizer
+
+import "math"
+
+// FadeCurve represents the shape of the fade.
+type FadeCurve int
+
+// FadeCurve constants are fade shapes.
+const (
+	// LinearFade changes the gain linearly. The crossfade keeps the level of the correlated signals, e.g. the same source filtered twice.
+	LinearFade FadeCurve = iota
+
+	// CosineFade is the raised cosine which starts and ends smoothly.
+	CosineFade
+
+	// EqualPowerFade is the quarter sine. The crossfade keeps the power of the uncorrelated signals.
+	EqualPowerFade
+)
+
+// gain returns the fade-in gain at t between 0 and 1.
+func (c FadeCurve) gain(t float64) float64 {
+	switch c {
+	case CosineFade:
+		return 0.5 - 0.5*math.Cos(math.Pi*t)
+	case EqualPowerFade:
+		return math.Sin(0.5 * math.Pi * t)
+	}
+
+	return t
+}
+
+// FadeIn applies the fade-in to the first length samples in place. The length is limited by the number of the samples.
+func FadeIn(samples []float64, length int, curve FadeCurve) {
+	if length > len(samples) {
+		length = len(samples)
+	}
+	for i := 0; i < length; i++ {
+		samples[i] *= curve.gain(float64(i) / float64(length))
+	}
+}
+
+// FadeOut applies the fade-out to the last length samples in place. The length is limited by the number of the samples.
+func FadeOut(samples []float64, length int, curve FadeCurve) {
+	if length > len(samples) {
+		length = len(samples)
+	}
+	for i := 0; i < length; i++ {
+		samples[len(samples)-1-i] *= curve.gain(float64(i) / float64(length))
+	}
+}
+
+// Crossfade writes the transition from the from to the to into dst and returns the number of the samples written, which
+// is the shortest length of the three. The dst may be the same slice as the from or the to.
+//
+// NOTE: Use EqualPowerFade for the unrelated signals and LinearFade for the overlapping chunks of the same signal, e.g.
+// around the boundaries of the chunks processed separately.
+func Crossfade(dst, from, to []float64, curve FadeCurve) int {
+	n := len(dst)
+
+	if len(from) < n {
+		n = len(from)
+	}
+	if len(to) < n {
+		n = len(to)
+	}
+	for i := 0; i < n; i++ {
+		// The center of each sample keeps the transition symmetric.
+		t := (float64(i) + 0.5) / float64(n)
+
+		dst[i] = from[i]*curve.gain(1.0-t) + to[i]*curve.gain(t)
+	}
+
+	return n
+}
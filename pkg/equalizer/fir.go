@@ -0,0 +1,58 @@
+package equalizer
+
+import "math"
+
+// LowPassFIR returns the coefficients of the linear-phase low-pass FIR filter designed with the Blackman windowed sinc.
+// Pass them to NewConvolver to apply the filter. The stopband attenuation is about 74 dB.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - cutoff ... Cut off frequency in Hz where the gain is -6 dB.
+//     - taps ... Number of the coefficients. The even number is rounded up to the odd number. The transition band is about 5.5*sampleRate/taps Hz wide.
+//
+// NOTE: The gain at 0 Hz is normalized to 1. The delay is (taps-1)/2 samples.
+func LowPassFIR(sampleRate, cutoff float64, taps int) []float64 {
+	if taps < 1 {
+		taps = 1
+	}
+	if taps%2 == 0 {
+		taps++
+	}
+
+	half := float64(taps-1) / 2.0
+	fc := cutoff / sampleRate
+	coefficients := make([]float64, taps)
+	sum := 0.0
+
+	for i := range coefficients {
+		x := float64(i) - half
+		coefficients[i] = 2.0 * fc * sinc(2.0*fc*x) * blackman(x, half+1.0)
+		sum += coefficients[i]
+	}
+	for i := range coefficients {
+		coefficients[i] /= sum
+	}
+
+	return coefficients
+}
+
+// sinc returns sin(pi*x)/(pi*x).
+func sinc(x float64) float64 {
+	if x == 0.0 {
+		return 1.0
+	}
+
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the Blackman window centered at 0 which becomes 0 at -half and half.
+func blackman(x, half float64) float64 {
+	if math.Abs(x) >= half {
+		return 0.0
+	}
+
+	t := math.Pi * x / half
+
+	return 0.42 + 0.5*math.Cos(t) + 0.08*math.Cos(2.0*t)
+}
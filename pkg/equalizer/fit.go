@@ -0,0 +1,260 @@
+package equalizer
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+)
+
+// FitOptions controls the fitting of the parametric equalizer.
+type FitOptions struct {
+	// Bands is the maximum number of the bands. The default is 5.
+	Bands int
+
+	// MinFrequency and MaxFrequency are the frequency range in Hz to fit. The defaults are 20 Hz and 20 kHz.
+	MinFrequency float64
+	MaxFrequency float64
+
+	// MaxGain is the maximum absolute gain of each band in dB. The default is 12 dB.
+	MaxGain float64
+
+	// Shelves allows the low-shelf and high-shelf filters at the ends of the frequency range.
+	Shelves bool
+
+	// Points is the number of the frequencies evaluated on the logarithmic axis. The default is 200.
+	Points int
+
+	// Iterations is the maximum number of the refinement sweeps. The default is 100.
+	Iterations int
+}
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o FitOptions) withDefaults(sampleRate float64) FitOptions {
+	if o.Bands <= 0 {
+		o.Bands = 5
+	}
+	if o.MinFrequency <= 0.0 {
+		o.MinFrequency = 20.0
+	}
+	if o.MaxFrequency <= 0.0 {
+		o.MaxFrequency = 20000.0
+	}
+	if o.MaxFrequency > 0.45*sampleRate {
+		o.MaxFrequency = 0.45 * sampleRate
+	}
+	if o.MaxGain <= 0.0 {
+		o.MaxGain = 12.0
+	}
+	if o.Points <= 1 {
+		o.Points = 200
+	}
+	if o.Iterations <= 0 {
+		o.Iterations = 100
+	}
+
+	return o
+}
+
+// Fit returns the parametric equalizer whose magnitude response approximates the response.
+// The bands are placed one by one on the largest remaining error, then all bands are refined together to minimize the squared error in dB.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - response ... Magnitude response to approximate.
+//     - options ... Fitting options. The zero value uses the defaults.
+func Fit(sampleRate float64, response Response, options FitOptions) (*ParametricEQ, error) {
+	if len(response) < 2 {
+		return nil, errors.New("equalizer: response needs at least 2 points")
+	}
+
+	options = options.withDefaults(sampleRate)
+
+	if options.MinFrequency >= options.MaxFrequency {
+		return nil, errors.New("equalizer: invalid frequency range")
+	}
+
+	sorted := append(Response{}, response...)
+	sorted.Sort()
+
+	f := &fitter{
+		sampleRate:  sampleRate,
+		options:     options,
+		frequencies: logFrequencies(options.MinFrequency, options.MaxFrequency, options.Points),
+	}
+
+	f.target = make([]float64, len(f.frequencies))
+	f.total = make([]float64, len(f.frequencies))
+
+	for i, frequency := range f.frequencies {
+		f.target[i] = sorted.At(frequency)
+	}
+	for len(f.bands) < options.Bands {
+		if !f.addBand() {
+			break
+		}
+
+		f.refine(options.Iterations / 4)
+	}
+
+	f.refine(options.Iterations)
+
+	e := NewParametricEQ(sampleRate)
+
+	for _, band := range f.bands {
+		e.AddBand(band)
+	}
+
+	return e, nil
+}
+
+// fitter holds the state of the fitting.
+type fitter struct {
+	sampleRate  float64
+	options     FitOptions
+	frequencies []float64
+	target      []float64
+
+	bands []Band
+	gains [][]float64
+	total []float64
+}
+
+// bandGains returns the magnitude response of the band in dB.
+func (f *fitter) bandGains(band Band) []float64 {
+	filter := design(band.Name, f.sampleRate, band.Frequency, band.Q, band.Gain)
+	gains := make([]float64, len(f.frequencies))
+
+	for i, frequency := range f.frequencies {
+		gains[i] = 20.0 * math.Log10(cmplx.Abs(filter.FrequencyResponse(frequency)))
+	}
+
+	return gains
+}
+
+// addBand places the new band on the largest error. It returns false when the error is small enough.
+func (f *fitter) addBand() bool {
+	index := 0
+	largest := 0.0
+
+	for i := range f.target {
+		if e := math.Abs(f.target[i] - f.total[i]); e > largest {
+			index = i
+			largest = e
+		}
+	}
+	if largest < 0.1 {
+		return false
+	}
+
+	band := Band{
+		Name:      Peaking,
+		Frequency: f.frequencies[index],
+		Q:         1.0,
+		Gain:      f.target[index] - f.total[index],
+	}
+
+	edge := len(f.frequencies) / 10
+
+	if f.options.Shelves && index < edge {
+		band.Name = LowShelf
+		band.Q = 1.0 / math.Sqrt2
+	}
+	if f.options.Shelves && index >= len(f.frequencies)-edge {
+		band.Name = HighShelf
+		band.Q = 1.0 / math.Sqrt2
+	}
+
+	band = f.clamp(band)
+	gains := f.bandGains(band)
+
+	f.bands = append(f.bands, band)
+	f.gains = append(f.gains, gains)
+
+	for i := range f.total {
+		f.total[i] += gains[i]
+	}
+
+	return true
+}
+
+// clamp limits the band parameters.
+func (f *fitter) clamp(band Band) Band {
+	band.Frequency = math.Max(f.options.MinFrequency, math.Min(f.options.MaxFrequency, band.Frequency))
+	band.Gain = math.Max(-f.options.MaxGain, math.Min(f.options.MaxGain, band.Gain))
+
+	if band.Name == Peaking {
+		// The band width in octaves.
+		band.Q = math.Max(0.05, math.Min(4.0, band.Q))
+	} else {
+		band.Q = math.Max(0.3, math.Min(2.0, band.Q))
+	}
+
+	return band
+}
+
+// cost returns the squared error when the gains of the i-th band are replaced.
+func (f *fitter) cost(i int, gains []float64) float64 {
+	sum := 0.0
+
+	for k := range f.target {
+		e := f.total[k] - f.gains[i][k] + gains[k] - f.target[k]
+		sum += e * e
+	}
+
+	return sum
+}
+
+// refine adjusts the parameters of all bands with the pattern search.
+func (f *fitter) refine(iterations int) {
+	// Steps of log2(frequency), log2(Q) and gain in dB.
+	steps := [3]float64{0.25, 0.25, 1.0}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		improved := false
+
+		for i := range f.bands {
+			best := f.cost(i, f.gains[i])
+
+			for parameter := range steps {
+				for _, direction := range []float64{1.0, -1.0} {
+					candidate := f.bands[i]
+					step := direction * steps[parameter]
+
+					switch parameter {
+					case 0:
+						candidate.Frequency *= math.Pow(2.0, step)
+					case 1:
+						candidate.Q *= math.Pow(2.0, step)
+					case 2:
+						candidate.Gain += step
+					}
+
+					candidate = f.clamp(candidate)
+					gains := f.bandGains(candidate)
+
+					if c := f.cost(i, gains); c < best {
+						for k := range f.total {
+							f.total[k] += gains[k] - f.gains[i][k]
+						}
+
+						f.bands[i] = candidate
+						f.gains[i] = gains
+						best = c
+						improved = true
+
+						break
+					}
+				}
+			}
+		}
+		if !improved {
+			for k := range steps {
+				steps[k] /= 2.0
+			}
+			if steps[2] < 0.01 {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package equalizer
+
+import "math"
+
+// Vowel represents the vowel preset of the formant filter.
+type Vowel int
+
+// Vowel constants are vowel presets.
+const (
+	VowelA Vowel = iota
+	VowelE
+	VowelI
+	VowelO
+	VowelU
+)
+
+// formantParameter holds the frequencies, band widths and amplitudes of the formants.
+type formantParameter struct {
+	frequencies [5]float64 // in Hz
+	bandwidths  [5]float64 // in Hz
+	amplitudes  [5]float64 // in dB
+}
+
+// vowels are the formants of the male bass voice.
+var vowels = map[Vowel]formantParameter{
+	VowelA: {
+		frequencies: [5]float64{600, 1040, 2250, 2450, 2750},
+		bandwidths:  [5]float64{60, 70, 110, 120, 130},
+		amplitudes:  [5]float64{0, -7, -9, -9, -20},
+	},
+	VowelE: {
+		frequencies: [5]float64{400, 1620, 2400, 2800, 3100},
+		bandwidths:  [5]float64{40, 80, 100, 120, 120},
+		amplitudes:  [5]float64{0, -12, -9, -12, -18},
+	},
+	VowelI: {
+		frequencies: [5]float64{250, 1750, 2600, 3050, 3340},
+		bandwidths:  [5]float64{60, 90, 100, 120, 120},
+		amplitudes:  [5]float64{0, -30, -16, -22, -28},
+	},
+	VowelO: {
+		frequencies: [5]float64{400, 750, 2400, 2600, 2900},
+		bandwidths:  [5]float64{40, 80, 100, 120, 120},
+		amplitudes:  [5]float64{0, -11, -21, -20, -40},
+	},
+	VowelU: {
+		frequencies: [5]float64{350, 600, 2400, 2675, 2950},
+		bandwidths:  [5]float64{40, 80, 100, 120, 120},
+		amplitudes:  [5]float64{0, -20, -32, -28, -36},
+	},
+}
+
+// Formant is the formant filter which consists of the parallel band-pass filters.
+type Formant struct {
+	sampleRate float64
+	filters    [5]*Filter
+	amplitudes [5]float64
+}
+
+// NewFormant returns the formant filter.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - vowel ... Vowel preset. e.g. VowelA
+func NewFormant(sampleRate float64, vowel Vowel) *Formant {
+	f := &Formant{
+		sampleRate: sampleRate,
+	}
+
+	for i := range f.filters {
+		f.filters[i] = NewBandPass(sampleRate, 1000.0, 1.0)
+	}
+
+	f.SetVowel(vowel)
+
+	return f
+}
+
+// SetVowel sets the formants to the vowel preset.
+func (f *Formant) SetVowel(vowel Vowel) {
+	f.Morph(vowel, vowel, 0.0)
+}
+
+// Morph sets the formants between the two vowel presets. t is the position between from (0.0) and to (1.0).
+func (f *Formant) Morph(from, to Vowel, t float64) {
+	a := vowels[from]
+	b := vowels[to]
+
+	for i := range f.filters {
+		frequency := a.frequencies[i] * math.Pow(b.frequencies[i]/a.frequencies[i], t)
+		bandwidth := a.bandwidths[i] + (b.bandwidths[i]-a.bandwidths[i])*t
+		amplitude := a.amplitudes[i] + (b.amplitudes[i]-a.amplitudes[i])*t
+
+		if frequency > 0.49*f.sampleRate {
+			frequency = 0.49 * f.sampleRate
+		}
+
+		// Convert the band width in Hz to the band width in octaves.
+		width := 2.0 / math.Log(2.0) * math.Asinh(bandwidth/(2.0*frequency))
+
+		f.filters[i].setCoefficients(design(BandPass, f.sampleRate, frequency, width, 0.0))
+		f.amplitudes[i] = math.Pow(10.0, amplitude/20.0)
+	}
+}
+
+// Apply applies the formant filter and returns the value.
+func (f *Formant) Apply(input float64) float64 {
+	output := 0.0
+
+	for i, filter := range f.filters {
+		output += f.amplitudes[i] * filter.Apply(input)
+	}
+
+	return output
+}
+
+// ProcessBuffer applies the formant filter to the buffer in place.
+func (f *Formant) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = f.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables.
+func (f *Formant) Reset() {
+	for _, filter := range f.filters {
+		filter.Reset()
+	}
+}
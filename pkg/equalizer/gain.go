@@ -0,0 +1,79 @@
+package equalizer
+
+import (
+	"math"
+	"time"
+)
+
+// GainSmoothing is the time constant in which the Gain follows the change, so the change does not click or zipper.
+const GainSmoothing = 10 * time.Millisecond
+
+// Gain is the gain or trim stage, so the whole signal path, e.g. the input trim, the equalizer and the output trim, is expressed in one chain.
+type Gain struct {
+	gain   float64
+	target float64
+	k      float64
+}
+
+// NewGain returns the gain stage.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - gain ... Gain in dB. e.g. -6.0
+func NewGain(sampleRate, gain float64) *Gain {
+	g := &Gain{
+		k: 1.0 - math.Exp(-1.0/(GainSmoothing.Seconds()*sampleRate)),
+	}
+
+	g.target = math.Pow(10.0, gain/20.0)
+	g.gain = g.target
+
+	return g
+}
+
+// Gain returns the gain in dB.
+func (g *Gain) Gain() float64 {
+	return 20.0 * math.Log10(g.target)
+}
+
+// SetGain changes the gain in dB. The gain moves smoothly with GainSmoothing, so it can be called while processing the signal.
+func (g *Gain) SetGain(gain float64) {
+	g.target = math.Pow(10.0, gain/20.0)
+}
+
+// Apply applies the gain and returns the value.
+func (g *Gain) Apply(input float64) float64 {
+	if g.gain != g.target {
+		g.gain += g.k * (g.target - g.gain)
+
+		// Snap to the target when the rest is inaudible, so the gain is exact.
+		if math.Abs(g.target-g.gain) < 1e-9 {
+			g.gain = g.target
+		}
+	}
+
+	return g.gain * input
+}
+
+// ProcessBuffer applies the gain to the buffer in place.
+func (g *Gain) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = g.Apply(buffer[i])
+	}
+}
+
+// Reset jumps to the target gain.
+func (g *Gain) Reset() {
+	g.gain = g.target
+}
+
+// FrequencyResponse returns the target gain, which is flat.
+func (g *Gain) FrequencyResponse(frequency float64) complex128 {
+	return complex(g.target, 0.0)
+}
+
+// GroupDelay returns 0, because the gain does not delay the signal.
+func (g *Gain) GroupDelay(frequency float64) float64 {
+	return 0.0
+}
@@ -0,0 +1,147 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// ERBRate converts the frequency in Hz to the ERB-rate scale (Glasberg and Moore, 1990).
+func ERBRate(frequency float64) float64 {
+	return 21.4 * math.Log10(1.0+0.00437*frequency)
+}
+
+// ERBRateToHz converts the ERB-rate scale to the frequency in Hz.
+func ERBRateToHz(rate float64) float64 {
+	return (math.Pow(10.0, rate/21.4) - 1.0) / 0.00437
+}
+
+// ERB returns the equivalent rectangular bandwidth in Hz at the frequency in Hz.
+func ERB(frequency float64) float64 {
+	return 24.7 + frequency/9.26449
+}
+
+// GammatoneBank is the ERB-spaced 4th order gammatone filter bank.
+// Each channel is the cascade of the four second order sections (Slaney, 1993).
+type GammatoneBank struct {
+	centers []float64
+	filters [][]*Filter
+	outputs []float64
+}
+
+// NewGammatoneBank returns the gammatone filter bank.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 16000.0
+//     - channels ... Number of the channels. e.g. 64
+//     - minFrequency ... Lowest center frequency in Hz. e.g. 50.0
+//     - maxFrequency ... Highest center frequency in Hz. e.g. 8000.0
+//
+// NOTE: The center frequencies are spaced equally on the ERB-rate scale. Each channel has 0 dB gain at its center frequency.
+func NewGammatoneBank(sampleRate float64, channels int, minFrequency, maxFrequency float64) *GammatoneBank {
+	g := &GammatoneBank{
+		centers: make([]float64, channels),
+		filters: make([][]*Filter, channels),
+		outputs: make([]float64, channels),
+	}
+
+	minRate := ERBRate(minFrequency)
+	maxRate := ERBRate(maxFrequency)
+
+	for i := range g.centers {
+		rate := minRate
+
+		if channels > 1 {
+			rate += (maxRate - minRate) * float64(i) / float64(channels-1)
+		}
+
+		g.centers[i] = ERBRateToHz(rate)
+		g.filters[i] = gammatone(sampleRate, g.centers[i])
+	}
+
+	return g
+}
+
+// Centers returns the center frequency of each channel in Hz.
+func (g *GammatoneBank) Centers() []float64 {
+	return g.centers
+}
+
+// Filters returns the second order sections of the channel.
+func (g *GammatoneBank) Filters(channel int) []*Filter {
+	return g.filters[channel]
+}
+
+// Process feeds the input to every channel.
+func (g *GammatoneBank) Process(input float64) {
+	for i, filters := range g.filters {
+		output := input
+
+		for _, filter := range filters {
+			output = filter.Apply(output)
+		}
+
+		g.outputs[i] = output
+	}
+}
+
+// ProcessBuffer feeds the buffer to every channel and stores the output of the i-th channel to outputs[i]. Each output must be as long as the buffer.
+//
+// NOTE: The empty buffer does nothing and keeps the latest outputs.
+func (g *GammatoneBank) ProcessBuffer(buffer []float64, outputs [][]float64) {
+	if len(buffer) == 0 {
+		return
+	}
+	for i, filters := range g.filters {
+		output := outputs[i][:len(buffer)]
+		copy(output, buffer)
+
+		for _, filter := range filters {
+			filter.ProcessBuffer(output)
+		}
+
+		g.outputs[i] = output[len(output)-1]
+	}
+}
+
+// Outputs returns the latest output of each channel.
+func (g *GammatoneBank) Outputs() []float64 {
+	return g.outputs
+}
+
+// Reset clears the state variables.
+func (g *GammatoneBank) Reset() {
+	for i, filters := range g.filters {
+		for _, filter := range filters {
+			filter.Reset()
+		}
+
+		g.outputs[i] = 0.0
+	}
+}
+
+// gammatone returns the four second order sections which form the 4th order gammatone filter.
+func gammatone(sampleRate, frequency float64) []*Filter {
+	t := 1.0 / sampleRate
+	b := 1.019 * 2.0 * p * ERB(frequency)
+	w := 2.0 * p * frequency * t
+	decay := math.Exp(-b * t)
+
+	filters := make([]*Filter, 4)
+	response := complex(1.0, 0.0)
+
+	for i, sign := range [][2]float64{{1.0, 1.0}, {1.0, -1.0}, {-1.0, 1.0}, {-1.0, -1.0}} {
+		root := math.Sqrt(3.0 + sign[0]*math.Pow(2.0, 1.5))
+		a1 := -(2.0*t*math.Cos(w)*decay + sign[1]*2.0*root*t*math.Sin(w)*decay) / 2.0
+
+		filters[i] = NewCustom(sampleRate, t, a1, 0.0, 1.0, -2.0*math.Cos(w)*decay, math.Exp(-2.0*b*t))
+		response *= filters[i].FrequencyResponse(frequency)
+	}
+
+	// Normalize the gain at the center frequency.
+	gain := cmplx.Abs(response)
+	filters[0].b0 /= gain
+	filters[0].b1 /= gain
+
+	return filters
+}
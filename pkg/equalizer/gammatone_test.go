@@ -0,0 +1,42 @@
+package equalizer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGammatoneBankProcessBuffer(t *testing.T) {
+	g := NewGammatoneBank(16000.0, 4, 100.0, 4000.0)
+	reference := NewGammatoneBank(16000.0, 4, 100.0, 4000.0)
+	buffer := make([]float64, 64)
+	outputs := make([][]float64, 4)
+
+	for i := range buffer {
+		buffer[i] = math.Sin(2.0 * math.Pi * 1000.0 * float64(i) / 16000.0)
+	}
+	for i := range outputs {
+		outputs[i] = make([]float64, len(buffer))
+	}
+
+	g.ProcessBuffer(buffer, outputs)
+
+	for _, value := range buffer {
+		reference.Process(value)
+	}
+	for i, want := range reference.Outputs() {
+		if got := g.Outputs()[i]; math.Abs(got-want) > 1e-12 {
+			t.Errorf("output of channel %d is %v, want %v", i, got, want)
+		}
+	}
+
+	// The empty buffer keeps the latest outputs instead of panicking.
+	latest := append([]float64(nil), g.Outputs()...)
+	g.ProcessBuffer(nil, outputs)
+	g.ProcessBuffer(buffer[:0], outputs)
+
+	for i, want := range latest {
+		if got := g.Outputs()[i]; got != want {
+			t.Errorf("output of channel %d after the empty buffer is %v, want %v", i, got, want)
+		}
+	}
+}
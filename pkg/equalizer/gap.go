@@ -0,0 +1,132 @@
+package equalizer
+
+import "math"
+
+// GapPolicy represents how the GapHandler treats NaN and Inf inputs, e.g. the missing values of the sensor data.
+type GapPolicy int
+
+// GapPolicy constants are the ways to fill the gaps.
+const (
+	// SkipAndReset outputs NaN during the gap and resets the processor, so the processing restarts after the gap.
+	SkipAndReset GapPolicy = iota
+
+	// HoldLast replaces the gap with the last finite input. The gap before the first finite input is filled with 0.
+	HoldLast
+
+	// Interpolate fills the gap with the straight line between the finite inputs around it. The line needs the input
+	// after the gap, so only ProcessBuffer interpolates the gap inside the buffer. Apply and the gap at the end of the
+	// buffer fall back to HoldLast.
+	Interpolate
+)
+
+// GapHandler protects the processor from NaN and Inf inputs. Without it, a single NaN poisons the state variables of
+// the IIR filters and every output after it becomes NaN until Reset.
+type GapHandler struct {
+	processor Processor
+	policy    GapPolicy
+	last      float64
+	inGap     bool
+}
+
+// NewGapHandler returns the processor which applies the processor to the inputs with the gaps filled by the policy.
+func NewGapHandler(processor Processor, policy GapPolicy) *GapHandler {
+	return &GapHandler{
+		processor: processor,
+		policy:    policy,
+	}
+}
+
+// Processor returns the wrapped processor.
+func (g *GapHandler) Processor() Processor {
+	return g.processor
+}
+
+// Apply fills the gap and applies the processor.
+func (g *GapHandler) Apply(input float64) float64 {
+	if isFinite(input) {
+		g.last = input
+		g.inGap = false
+
+		return g.processor.Apply(input)
+	}
+	if g.policy == SkipAndReset {
+		if !g.inGap {
+			if r, ok := g.processor.(resetter); ok {
+				r.Reset()
+			}
+		}
+
+		g.inGap = true
+
+		return math.NaN()
+	}
+
+	return g.processor.Apply(g.last)
+}
+
+// ProcessBuffer applies the processor to the buffer in place. With Interpolate, the gaps between the finite samples in
+// the buffer are interpolated linearly.
+func (g *GapHandler) ProcessBuffer(buffer []float64) {
+	if g.policy == Interpolate {
+		interpolateGaps(buffer, g.last)
+	}
+	for i := range buffer {
+		buffer[i] = g.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables of the processor and forgets the last input.
+func (g *GapHandler) Reset() {
+	g.last = 0.0
+	g.inGap = false
+
+	if r, ok := g.processor.(resetter); ok {
+		r.Reset()
+	}
+}
+
+// FrequencyResponse returns the frequency response of the processor, or 1 when it is unknown.
+func (g *GapHandler) FrequencyResponse(frequency float64) complex128 {
+	if r, ok := g.processor.(responder); ok {
+		return r.FrequencyResponse(frequency)
+	}
+
+	return 1.0
+}
+
+// interpolateGaps replaces the runs of the non-finite samples followed by the finite sample in place.
+// The run at the start of the buffer is interpolated from the last value before the buffer.
+func interpolateGaps(buffer []float64, last float64) {
+	start := -1
+
+	for i, value := range buffer {
+		if !isFinite(value) {
+			if start < 0 {
+				start = i
+			}
+
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+
+		from := last
+
+		if start > 0 {
+			from = buffer[start-1]
+		}
+
+		steps := float64(i - start + 1)
+
+		for j := start; j < i; j++ {
+			buffer[j] = from + (value-from)*float64(j-start+1)/steps
+		}
+
+		start = -1
+	}
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
@@ -0,0 +1,180 @@
+package equalizer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// GoCodeOptions is the options of the generated Go source.
+type GoCodeOptions struct {
+	// Package is the package name of the source. The default is "filters".
+	Package string
+
+	// Name is the suffix of the generated identifiers, e.g. "Loudness" generates LoudnessCoefficients and NewLoudness.
+	// It must be the exported Go identifier. The default is "Equalizer".
+	Name string
+
+	// Rates are the sample rates in Hz for which the filters are designed in addition to their own sample rate,
+	// e.g. 44100 and 48000.
+	Rates []float64
+}
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o GoCodeOptions) withDefaults() GoCodeOptions {
+	if o.Package == "" {
+		o.Package = "filters"
+	}
+	if o.Name == "" {
+		o.Name = "Equalizer"
+	}
+
+	return o
+}
+
+// WriteGoCode writes the Go source which has the coefficient table of the processor for each sample rate and the
+// constructor of the chain, so the application embeds the fixed equalizer without designing the filters at the startup.
+// Use it with go generate and the gocode subcommand of the equalizer command.
+//
+// The source defines the following, where Name is the Name of the options:
+//
+//     - NameCoefficients ... b0, b1, b2, a1 and a2 normalized by a0 of each filter keyed by the sample rate.
+//     - NewName(sampleRate float64) *equalizer.Chain ... Returns the chain for the sample rate, or nil if the sample rate is not in the table.
+//
+// Parameters:
+//
+//     - w ... Destination of the source. e.g. the file filters_gen.go
+//     - processor ... *Filter, *ParametricEQ or *Chain of them.
+//     - options ... Package, name and sample rates of the source.
+//
+// NOTE: Every filter must be designed at every sample rate, so the custom filters are allowed only at their own sample
+// rate and the frequency must be below the Nyquist frequency of every sample rate. The generated filters are the custom
+// filters, so they cannot be redesigned with SetFrequency, SetQ or SetGain.
+func WriteGoCode(w io.Writer, processor Processor, options GoCodeOptions) error {
+	filters, err := biquads(processor)
+
+	if err != nil {
+		return err
+	}
+
+	options = options.withDefaults()
+
+	if !token.IsIdentifier(options.Package) {
+		return fmt.Errorf("equalizer: %q is not the Go package name", options.Package)
+	}
+	if !token.IsIdentifier(options.Name) || !token.IsExported(options.Name) {
+		return fmt.Errorf("equalizer: %q is not the exported Go identifier", options.Name)
+	}
+
+	tables, err := designTables(filters, options.Rates)
+
+	if err != nil {
+		return err
+	}
+
+	rates := make([]float64, 0, len(tables))
+
+	for rate := range tables {
+		rates = append(rates, rate)
+	}
+
+	sort.Float64s(rates)
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by go-equalizer. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", options.Package)
+	fmt.Fprintf(&b, "import \"github.com/moutend/go-equalizer/pkg/equalizer\"\n\n")
+	fmt.Fprintf(&b, "// %sCoefficients are b0, b1, b2, a1 and a2 normalized by a0 of each filter keyed by the sample rate.\n", options.Name)
+	fmt.Fprintf(&b, "var %sCoefficients = map[float64][][5]float64{\n", options.Name)
+
+	for _, rate := range rates {
+		fmt.Fprintf(&b, "%s: {\n", strconv.FormatFloat(rate, 'g', -1, 64))
+
+		for _, f := range tables[rate] {
+			fmt.Fprintf(&b, "// %s\n", describeFilter(f))
+			fmt.Fprintf(&b, "{%s, %s, %s, %s, %s},\n",
+				formatCoefficient(f.b0), formatCoefficient(f.b1), formatCoefficient(f.b2),
+				formatCoefficient(f.a1), formatCoefficient(f.a2))
+		}
+
+		fmt.Fprintf(&b, "},\n")
+	}
+
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "// New%s returns the chain of the filters for the sample rate. It returns nil when the sample rate is not in %sCoefficients.\n", options.Name, options.Name)
+	fmt.Fprintf(&b, "func New%s(sampleRate float64) *equalizer.Chain {\n", options.Name)
+	fmt.Fprintf(&b, "coefficients, ok := %sCoefficients[sampleRate]\n\n", options.Name)
+	fmt.Fprintf(&b, "if !ok {\nreturn nil\n}\n\n")
+	fmt.Fprintf(&b, "processors := make([]equalizer.Processor, len(coefficients))\n\n")
+	fmt.Fprintf(&b, "for i, c := range coefficients {\n")
+	fmt.Fprintf(&b, "processors[i] = equalizer.NewCustom(sampleRate, c[0], c[1], c[2], 1.0, c[3], c[4])\n")
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "return equalizer.NewChain(processors...)\n")
+	fmt.Fprintf(&b, "}\n")
+
+	source, err := format.Source(b.Bytes())
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(source)
+
+	return err
+}
+
+// WriteGoCodeFile is the same as WriteGoCode but writes the source to the file.
+func WriteGoCodeFile(path string, processor Processor, options GoCodeOptions) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+	if err := WriteGoCode(file, processor, options); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// designTables returns the filters designed for their own sample rates and each of the rates, keyed by the sample rate.
+// It returns the error when any filter cannot be designed at any rate, because the table of the rate would be incomplete.
+func designTables(filters []*Filter, rates []float64) (map[float64][]*Filter, error) {
+	tables := map[float64][]*Filter{}
+
+	for _, f := range filters {
+		tables[f.sampleRate] = nil
+	}
+	for _, rate := range rates {
+		tables[rate] = nil
+	}
+	for rate := range tables {
+		for _, f := range filters {
+			g := f
+
+			if rate != f.sampleRate {
+				if f.name == Custom {
+					return nil, fmt.Errorf("equalizer: custom filter of %g Hz cannot be designed at %g Hz", f.sampleRate, rate)
+				}
+				if err := CheckFrequency(rate, f.frequency); err != nil {
+					return nil, err
+				}
+				if g = designWith(f.method, f.name, rate, f.frequency, f.q, f.gain); g == nil {
+					return nil, fmt.Errorf("equalizer: %s cannot be designed at %g Hz", describeFilter(f), rate)
+				}
+			}
+
+			tables[rate] = append(tables[rate], g)
+		}
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,128 @@
+package equalizer
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Hooks receives the activity of the chain and the equalizers, so the host can log or trace it without changing this package,
+// e.g. to find the stage which causes the glitches in the production stream. Embed NopHooks to implement some of the methods.
+//
+// NOTE: The hooks are called on the goroutine which processes the samples, so they must return quickly and must not block.
+type Hooks interface {
+	// OnBlockStart is called before the stage of the chain processes the buffer. stage is the index of the processor in the chain.
+	OnBlockStart(stage int, processor Processor, samples int)
+
+	// OnBlockEnd is called after the stage of the chain processed the buffer with the time spent in it.
+	OnBlockEnd(stage int, processor Processor, samples int, elapsed time.Duration)
+
+	// OnParamChange is called after the band of the equalizer is changed.
+	OnParamChange(change ParamChange)
+}
+
+// ParamChange describes the change of the band.
+type ParamChange struct {
+	// Processor is the changed equalizer.
+	Processor Processor
+
+	// Band is the index of the band, and Old and New are the band before and after the change.
+	Band int
+	Old  Band
+	New  Band
+}
+
+// hooksSetter is implemented by the processors which call the hooks, so the chain passes the hooks to them.
+type hooksSetter interface {
+	SetHooks(hooks Hooks)
+}
+
+// NopHooks implements Hooks with the methods which do nothing.
+type NopHooks struct{}
+
+// OnBlockStart does nothing.
+func (NopHooks) OnBlockStart(stage int, processor Processor, samples int) {}
+
+// OnBlockEnd does nothing.
+func (NopHooks) OnBlockEnd(stage int, processor Processor, samples int, elapsed time.Duration) {}
+
+// OnParamChange does nothing.
+func (NopHooks) OnParamChange(change ParamChange) {}
+
+// LogHooks writes the parameter changes and the slow blocks to the logger as the key=value pairs, e.g.
+//
+//	event=param_change band=0 name=Peaking frequency=1000->2000 q=1 gain=0->3
+//	event=slow_block stage=2 processor=*equalizer.Convolver samples=1024 elapsed=12.3ms
+type LogHooks struct {
+	logger    *log.Logger
+	threshold time.Duration
+}
+
+// NewLogHooks returns the hooks which write to the logger.
+//
+// Parameters:
+//
+//     - logger ... Destination of the lines. e.g. log.New(os.Stderr, "equalizer: ", log.LstdFlags)
+//     - threshold ... The block which takes longer is logged. 0 logs every block, which is useful only for debugging.
+func NewLogHooks(logger *log.Logger, threshold time.Duration) *LogHooks {
+	return &LogHooks{
+		logger:    logger,
+		threshold: threshold,
+	}
+}
+
+// OnBlockStart does nothing. The block is logged when it ends.
+func (h *LogHooks) OnBlockStart(stage int, processor Processor, samples int) {}
+
+// OnBlockEnd logs the block which took longer than the threshold.
+func (h *LogHooks) OnBlockEnd(stage int, processor Processor, samples int, elapsed time.Duration) {
+	if elapsed < h.threshold {
+		return
+	}
+
+	event := "block"
+
+	if h.threshold > 0 {
+		event = "slow_block"
+	}
+
+	h.logger.Printf("event=%s stage=%d processor=%T samples=%d elapsed=%s", event, stage, processor, samples, elapsed)
+}
+
+// OnParamChange logs the change.
+func (h *LogHooks) OnParamChange(change ParamChange) {
+	h.logger.Printf("event=param_change band=%d name=%s frequency=%s q=%s gain=%s", change.Band, filterNameString(change.New.Name),
+		changed(change.Old.Frequency, change.New.Frequency), changed(change.Old.Q, change.New.Q), changed(change.Old.Gain, change.New.Gain))
+}
+
+func changed(old, new float64) string {
+	if old == new {
+		return fmt.Sprint(new)
+	}
+
+	return fmt.Sprintf("%v->%v", old, new)
+}
+
+// filterNameString returns the name of the filter constant for the log.
+func filterNameString(name FilterName) string {
+	names := map[FilterName]string{
+		LowPass:    "LowPass",
+		HighPass:   "HighPass",
+		AllPass:    "AllPass",
+		BandPass:   "BandPass",
+		BandReject: "BandReject",
+		LowShelf:   "LowShelf",
+		HighShelf:  "HighShelf",
+		Peaking:    "Peaking",
+		Custom:     "Custom",
+	}
+
+	if s, ok := names[name]; ok {
+		return s
+	}
+	if s := RegisteredName(name); s != "" {
+		return s
+	}
+
+	return "Undefined"
+}
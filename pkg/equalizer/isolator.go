@@ -0,0 +1,126 @@
+package equalizer
+
+import "math"
+
+// IsolatorBand represents the band of the isolator.
+type IsolatorBand int
+
+// IsolatorBand constants are isolator bands.
+const (
+	IsolatorLow IsolatorBand = iota
+	IsolatorMid
+	IsolatorHigh
+)
+
+// isolatorSmoothingTime is the time in seconds taken by the band gain to follow the new value.
+const isolatorSmoothingTime = 0.005
+
+// Isolator is the DJ-style three-band isolator. The bands are split by the 4th order Linkwitz-Riley crossovers, so the bands sum to the flat magnitude response at unity gain.
+type Isolator struct {
+	// low band
+	lowPass  [2]*Filter
+	allPass  *Filter
+	highPass [2]*Filter
+
+	// mid and high bands
+	midLowPass   [2]*Filter
+	highHighPass [2]*Filter
+
+	gains     [3]float64
+	kills     [3]bool
+	targets   [3]float64
+	current   [3]float64
+	smoothing float64
+}
+
+// NewIsolator returns the isolator at unity gain.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - lowFrequency ... Crossover frequency between the low and mid bands in Hz. e.g. 300.0
+//     - highFrequency ... Crossover frequency between the mid and high bands in Hz. e.g. 3000.0
+func NewIsolator(sampleRate, lowFrequency, highFrequency float64) *Isolator {
+	const q = 1.0 / math.Sqrt2
+
+	i := &Isolator{
+		// The low band passes the all-pass which matches the phase of the mid and high crossover.
+		allPass:   NewAllPass(sampleRate, highFrequency, q),
+		targets:   [3]float64{1.0, 1.0, 1.0},
+		current:   [3]float64{1.0, 1.0, 1.0},
+		smoothing: smoothingCoefficient(sampleRate, isolatorSmoothingTime),
+	}
+
+	for n := 0; n < 2; n++ {
+		i.lowPass[n] = NewLowPass(sampleRate, lowFrequency, q)
+		i.highPass[n] = NewHighPass(sampleRate, lowFrequency, q)
+		i.midLowPass[n] = NewLowPass(sampleRate, highFrequency, q)
+		i.highHighPass[n] = NewHighPass(sampleRate, highFrequency, q)
+	}
+
+	return i
+}
+
+// Gain returns the gain of the band in dB.
+func (i *Isolator) Gain(band IsolatorBand) float64 {
+	return i.gains[band]
+}
+
+// SetGain sets the gain of the band in dB. Use math.Inf(-1) to remove the band completely.
+func (i *Isolator) SetGain(band IsolatorBand, gain float64) {
+	i.gains[band] = gain
+	i.update(band)
+}
+
+// Killed returns true when the band is killed.
+func (i *Isolator) Killed(band IsolatorBand) bool {
+	return i.kills[band]
+}
+
+// SetKill kills or restores the band regardless of its gain.
+func (i *Isolator) SetKill(band IsolatorBand, kill bool) {
+	i.kills[band] = kill
+	i.update(band)
+}
+
+// update computes the linear gain of the band.
+func (i *Isolator) update(band IsolatorBand) {
+	i.targets[band] = 0.0
+
+	if !i.kills[band] {
+		i.targets[band] = math.Pow(10.0, i.gains[band]/20.0)
+	}
+}
+
+// Apply applies the isolator and returns the value.
+func (i *Isolator) Apply(input float64) float64 {
+	low := i.allPass.Apply(i.lowPass[1].Apply(i.lowPass[0].Apply(input)))
+	rest := i.highPass[1].Apply(i.highPass[0].Apply(input))
+	mid := i.midLowPass[1].Apply(i.midLowPass[0].Apply(rest))
+	high := i.highHighPass[1].Apply(i.highHighPass[0].Apply(rest))
+
+	for band, target := range i.targets {
+		i.current[band] = i.smoothing*i.current[band] + (1.0-i.smoothing)*target
+	}
+
+	return i.current[IsolatorLow]*low + i.current[IsolatorMid]*mid + i.current[IsolatorHigh]*high
+}
+
+// ProcessBuffer applies the isolator to the buffer in place.
+func (i *Isolator) ProcessBuffer(buffer []float64) {
+	for n := range buffer {
+		buffer[n] = i.Apply(buffer[n])
+	}
+}
+
+// Reset clears the state variables.
+func (i *Isolator) Reset() {
+	i.allPass.Reset()
+
+	for n := 0; n < 2; n++ {
+		i.lowPass[n].Reset()
+		i.highPass[n].Reset()
+		i.midLowPass[n].Reset()
+		i.highHighPass[n].Reset()
+	}
+}
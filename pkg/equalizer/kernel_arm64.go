@@ -0,0 +1,10 @@
+//go:build arm64 && neon
+// +build arm64,neon
+
+package equalizer
+
+// biquadPair applies the biquad to the first two channels of the buffer with the NEON instructions, the two channels
+// in the two lanes of the vector. See biquadPairGeneric for the arguments.
+//
+//go:noescape
+func biquadPair(coefficients *[10]float64, state *[8]float64, buffer []float64, frames, stride int)
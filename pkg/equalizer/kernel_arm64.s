@@ -0,0 +1,48 @@
+//go:build arm64 && neon
+// +build arm64,neon
+
+#include "textflag.h"
+
+// func biquadPair(coefficients *[10]float64, state *[8]float64, buffer []float64, frames, stride int)
+TEXT ·biquadPair(SB), NOSPLIT, $0-56
+	MOVD coefficients+0(FP), R0
+	MOVD state+8(FP), R1
+	MOVD buffer_base+16(FP), R2
+	MOVD frames+40(FP), R3
+	MOVD stride+48(FP), R4
+	LSL  $3, R4, R4
+
+	// V0 to V4 are b0, b1, b2, a1 and a2 in both lanes.
+	VLD1 (R0), [V0.D2, V1.D2, V2.D2, V3.D2]
+	ADD  $64, R0, R5
+	VLD1 (R5), [V4.D2]
+
+	// V5 to V8 are x1, x2, y1 and y2 of the two channels.
+	VLD1 (R1), [V5.D2, V6.D2, V7.D2, V8.D2]
+
+	CBZ R3, done
+
+loop:
+	// y = b0*x + b1*x1 + b2*x2 - a1*y1 - a2*y2
+	VLD1  (R2), [V9.D2]
+	VEOR  V10.B16, V10.B16, V10.B16
+	VFMLA V0.D2, V9.D2, V10.D2
+	VFMLA V1.D2, V5.D2, V10.D2
+	VFMLA V2.D2, V6.D2, V10.D2
+	VFMLS V3.D2, V7.D2, V10.D2
+	VFMLS V4.D2, V8.D2, V10.D2
+	VST1  [V10.D2], (R2)
+
+	// Shift the delays.
+	VORR V5.B16, V5.B16, V6.B16
+	VORR V9.B16, V9.B16, V5.B16
+	VORR V7.B16, V7.B16, V8.B16
+	VORR V10.B16, V10.B16, V7.B16
+
+	ADD  R4, R2, R2
+	SUBS $1, R3, R3
+	BNE  loop
+
+done:
+	VST1 [V5.D2, V6.D2, V7.D2, V8.D2], (R1)
+	RET
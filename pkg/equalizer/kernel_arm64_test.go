@@ -0,0 +1,158 @@
+//go:build arm64 && neon
+// +build arm64,neon
+
+package equalizer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// fusedBiquadPair is biquadPairGeneric with the fused multiply-adds in the order of the NEON kernel, so it is the
+// exact model of the kernel.
+func fusedBiquadPair(coefficients *[10]float64, state *[8]float64, buffer []float64, frames, stride int) {
+	c := coefficients
+	s := state
+
+	for i := 0; i < frames; i++ {
+		for lane := 0; lane < 2; lane++ {
+			x := buffer[i*stride+lane]
+			y := math.FMA(c[0], x, 0.0)
+			y = math.FMA(c[2], s[lane], y)
+			y = math.FMA(c[4], s[2+lane], y)
+			y = math.FMA(-c[6], s[4+lane], y)
+			y = math.FMA(-c[8], s[6+lane], y)
+
+			s[2+lane] = s[lane]
+			s[lane] = x
+			s[6+lane] = s[4+lane]
+			s[4+lane] = y
+			buffer[i*stride+lane] = y
+		}
+	}
+}
+
+// testCoefficients returns the coefficients of the kernel of the filter.
+func testCoefficients(f *Filter) [10]float64 {
+	return [10]float64{f.b0, f.b0, f.b1, f.b1, f.b2, f.b2, f.a1, f.a1, f.a2, f.a2}
+}
+
+// randomSignal returns the noise between -1 and 1.
+func randomSignal(random *rand.Rand, length int) []float64 {
+	signal := make([]float64, length)
+
+	for i := range signal {
+		signal[i] = 2.0*random.Float64() - 1.0
+	}
+
+	return signal
+}
+
+func TestBiquadPairNEON(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	filters := []*Filter{
+		NewLowPass(48000.0, 1000.0, 0.707),
+		NewHighPass(48000.0, 20.0, 0.707),
+		NewPeaking(48000.0, 3000.0, 1.0, 6.0),
+		NewHighShelf(44100.0, 16000.0, 0.707, -12.0),
+	}
+
+	for _, f := range filters {
+		coefficients := testCoefficients(f)
+
+		for stride := 2; stride <= 9; stride++ {
+			for offset := 0; offset+2 <= stride; offset++ {
+				const frames = 257
+
+				signal := randomSignal(random, frames*stride)
+				neon := append([]float64(nil), signal...)
+				fused := append([]float64(nil), signal...)
+
+				var neonState, fusedState [8]float64
+
+				// The two calls continue from the state of the first one.
+				for _, block := range [][2]int{{0, 100}, {100, frames}} {
+					start := block[0]*stride + offset
+					biquadPair(&coefficients, &neonState, neon[start:], block[1]-block[0], stride)
+					fusedBiquadPair(&coefficients, &fusedState, fused[start:], block[1]-block[0], stride)
+				}
+				for i := range neon {
+					if math.Float64bits(neon[i]) != math.Float64bits(fused[i]) {
+						t.Fatalf("%s, stride %d, offset %d: sample %d is %v, want %v", filterNameString(f.name), stride, offset, i, neon[i], fused[i])
+					}
+				}
+				if neonState != fusedState {
+					t.Fatalf("%s, stride %d, offset %d: state is %v, want %v", filterNameString(f.name), stride, offset, neonState, fusedState)
+				}
+			}
+		}
+	}
+}
+
+func TestBiquadPairNEONGeneric(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	f := NewPeaking(48000.0, 3000.0, 1.0, 6.0)
+	coefficients := testCoefficients(f)
+
+	for stride := 2; stride <= 9; stride++ {
+		const frames = 1000
+
+		signal := randomSignal(random, frames*stride)
+
+		var neonState, genericState [8]float64
+
+		// The kernels run frame by frame from the same state, so the difference is the rounding of one frame, which is
+		// bounded by the few ulps of the sum of the absolute values of the terms.
+		for i := 0; i < frames; i++ {
+			frame := signal[i*stride : i*stride+2]
+			neon := append([]float64(nil), frame...)
+			generic := append([]float64(nil), frame...)
+
+			genericState = neonState
+			previous := neonState
+
+			biquadPair(&coefficients, &neonState, neon, 1, stride)
+			biquadPairGeneric(&coefficients, &genericState, generic, 1, stride)
+
+			for lane := 0; lane < 2; lane++ {
+				c, s := coefficients, previous
+				bound := 8.0 * 0x1p-52 * (math.Abs(c[0]*frame[lane]) + math.Abs(c[2]*s[lane]) + math.Abs(c[4]*s[2+lane]) +
+					math.Abs(c[6]*s[4+lane]) + math.Abs(c[8]*s[6+lane]))
+
+				if d := math.Abs(neon[lane] - generic[lane]); d > bound {
+					t.Fatalf("stride %d: frame %d of lane %d is %v, want %v within %v", stride, i, lane, neon[lane], generic[lane], bound)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiChannelNEON(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	f := NewLowShelf(48000.0, 200.0, 0.707, 9.0)
+
+	// The odd channels process the last one with the scalar filter, and the others in the pairs with the kernel.
+	for channels := 1; channels <= 8; channels++ {
+		const frames = 512
+
+		signal := randomSignal(random, frames*channels)
+		interleaved := append([]float64(nil), signal...)
+		m := NewMultiChannel(f, channels)
+		m.ProcessInterleaved(interleaved[:frames/2*channels])
+		m.ProcessInterleaved(interleaved[frames/2*channels:])
+
+		for channel := 0; channel < channels; channel++ {
+			reference := NewLowShelf(48000.0, 200.0, 0.707, 9.0)
+
+			for i := 0; i < frames; i++ {
+				want := reference.Apply(signal[i*channels+channel])
+
+				// The filter is stable, so the rounding of the kernel stays near the rounding of one sample.
+				if d := math.Abs(interleaved[i*channels+channel] - want); d > 1e-12*math.Max(1.0, math.Abs(want)) {
+					t.Fatalf("%d channels: frame %d of channel %d is %v, want %v", channels, i, channel, interleaved[i*channels+channel], want)
+				}
+			}
+		}
+	}
+}
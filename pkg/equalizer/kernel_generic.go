@@ -0,0 +1,22 @@
+package equalizer
+
+// biquadPairGeneric applies the biquad to the first two channels of the buffer, whose frames repeat every stride samples.
+// The coefficients are b0, b1, b2, a1 and a2 normalized by a0, each repeated twice, and the state is x1, x2, y1 and y2
+// of the two channels side by side. It is built on every platform, so the test compares the NEON kernel with it.
+func biquadPairGeneric(coefficients *[10]float64, state *[8]float64, buffer []float64, frames, stride int) {
+	c := coefficients
+	s := state
+
+	for i := 0; i < frames; i++ {
+		for lane := 0; lane < 2; lane++ {
+			x := buffer[i*stride+lane]
+			y := c[0]*x + c[2]*s[lane] + c[4]*s[2+lane] - c[6]*s[4+lane] - c[8]*s[6+lane]
+
+			s[2+lane] = s[lane]
+			s[lane] = x
+			s[6+lane] = s[4+lane]
+			s[4+lane] = y
+			buffer[i*stride+lane] = y
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !arm64 || !neon
+// +build !arm64 !neon
+
+package equalizer
+
+// biquadPair applies the biquad to the first two channels of the buffer. See biquadPairGeneric for the arguments.
+func biquadPair(coefficients *[10]float64, state *[8]float64, buffer []float64, frames, stride int) {
+	biquadPairGeneric(coefficients, state, buffer, frames, stride)
+}
@@ -0,0 +1,110 @@
+package equalizer
+
+import "math"
+
+// Ladder is the Moog-style 4-pole resonant low-pass filter.
+// It is modeled as the cascade of the four zero-delay feedback one-pole filters with the saturating feedback loop.
+type Ladder struct {
+	sampleRate float64
+	frequency  float64
+	resonance  float64
+	drive      float64
+
+	// g/(1+g) of the one-pole filters
+	g float64
+
+	// state variables
+	s [4]float64
+}
+
+// NewLadder returns the ladder filter.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - frequency ... Cut off frequency in Hz.
+//     - resonance ... Resonance. 0 is no resonance, 1 is the edge of the self-oscillation.
+//     - drive ... Input gain before the saturation. e.g. 1.0 for the clean sound.
+//
+// NOTE: Set resonance to 1 or greater to make the filter self-oscillate at the cut off frequency.
+func NewLadder(sampleRate, frequency, resonance, drive float64) *Ladder {
+	l := &Ladder{
+		sampleRate: sampleRate,
+		resonance:  resonance,
+		drive:      drive,
+	}
+
+	l.SetFrequency(frequency)
+
+	return l
+}
+
+// Frequency returns the cut off frequency in Hz.
+func (l *Ladder) Frequency() float64 {
+	return l.frequency
+}
+
+// SetFrequency sets the cut off frequency in Hz. The state variables are preserved.
+func (l *Ladder) SetFrequency(frequency float64) {
+	g := math.Tan(p * frequency / l.sampleRate)
+
+	l.frequency = frequency
+	l.g = g / (1.0 + g)
+}
+
+// Resonance returns the resonance.
+func (l *Ladder) Resonance() float64 {
+	return l.resonance
+}
+
+// SetResonance sets the resonance.
+func (l *Ladder) SetResonance(resonance float64) {
+	l.resonance = resonance
+}
+
+// Drive returns the input gain.
+func (l *Ladder) Drive() float64 {
+	return l.drive
+}
+
+// SetDrive sets the input gain.
+func (l *Ladder) SetDrive(drive float64) {
+	l.drive = drive
+}
+
+// Apply applies the ladder filter and returns the value.
+func (l *Ladder) Apply(input float64) float64 {
+	g := l.g
+	k := 4.0 * l.resonance
+
+	// Solve the feedback loop for the linear part: y4 = g^4*u + sum.
+	sum := 0.0
+
+	for _, s := range l.s {
+		sum = sum*g + s*(1.0-g)
+	}
+
+	u := math.Tanh((l.drive*input - k*sum) / (1.0 + k*g*g*g*g))
+
+	for i := range l.s {
+		v := (u - l.s[i]) * g
+		y := v + l.s[i]
+
+		l.s[i] = y + v
+		u = y
+	}
+
+	return u
+}
+
+// ProcessBuffer applies the ladder filter to the buffer in place.
+func (l *Ladder) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = l.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables.
+func (l *Ladder) Reset() {
+	l.s = [4]float64{}
+}
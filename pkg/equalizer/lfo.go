@@ -0,0 +1,181 @@
+package equalizer
+
+import (
+	"math"
+	"math/rand"
+)
+
+// LFOShape represents the waveform of the low frequency oscillator.
+type LFOShape int
+
+// LFOShape constants are waveforms.
+const (
+	SineLFO LFOShape = iota
+	TriangleLFO
+	RandomLFO
+)
+
+// LFO is the low frequency oscillator. It generates the value between -1.0 and 1.0.
+type LFO struct {
+	shape      LFOShape
+	sampleRate float64
+	rate       float64
+	phase      float64
+
+	// random waveform state
+	random   *rand.Rand
+	previous float64
+	next     float64
+}
+
+// NewLFO returns the low frequency oscillator.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - rate ... Oscillation rate in Hz. e.g. 2.0
+//     - shape ... SineLFO, TriangleLFO or RandomLFO.
+//
+// NOTE: RandomLFO moves smoothly to the new random value every cycle.
+func NewLFO(sampleRate, rate float64, shape LFOShape) *LFO {
+	l := &LFO{
+		shape:      shape,
+		sampleRate: sampleRate,
+		rate:       rate,
+		random:     rand.New(rand.NewSource(1)),
+	}
+
+	l.next = l.random.Float64()*2.0 - 1.0
+
+	return l
+}
+
+// SetRate sets the oscillation rate in Hz.
+func (l *LFO) SetRate(rate float64) {
+	l.rate = rate
+}
+
+// Value returns the current value without advancing the oscillator.
+func (l *LFO) Value() float64 {
+	switch l.shape {
+	case TriangleLFO:
+		return 4.0*math.Abs(math.Mod(l.phase+0.75, 1.0)-0.5) - 1.0
+	case RandomLFO:
+		return l.previous + (l.next-l.previous)*l.phase
+	}
+
+	return math.Sin(2.0 * math.Pi * l.phase)
+}
+
+// Next advances the oscillator by one sample and returns the value.
+func (l *LFO) Next() float64 {
+	l.phase += l.rate / l.sampleRate
+
+	for l.phase >= 1.0 {
+		l.phase -= 1.0
+		l.previous = l.next
+		l.next = l.random.Float64()*2.0 - 1.0
+	}
+
+	return l.Value()
+}
+
+// Reset sets the phase to 0.
+func (l *LFO) Reset() {
+	l.phase = 0.0
+}
+
+// ModulatedFilter sweeps the frequency of the filter with the LFO, e.g. auto-wah.
+type ModulatedFilter struct {
+	filter    *Filter
+	lfo       *LFO
+	frequency float64
+	depth     float64
+	interval  int
+	counter   int
+
+	// coefficients interpolated during the interval
+	from Filter
+	to   Filter
+}
+
+// NewModulatedFilter returns the filter whose frequency is modulated by the LFO.
+//
+// Parameters:
+//
+//     - filter ... Filter to be modulated. Its frequency is used as the center frequency.
+//     - lfo ... LFO which modulates the frequency.
+//     - depth ... Modulation depth in octaves. e.g. 2.0 sweeps between 1/4 and 4 times of the center frequency.
+//     - interval ... Number of samples between the coefficient updates. 1 means updating every sample.
+//
+// NOTE: The coefficients are interpolated between the updates, so the large interval does not cause the zipper noise.
+func NewModulatedFilter(filter *Filter, lfo *LFO, depth float64, interval int) *ModulatedFilter {
+	if interval < 1 {
+		interval = 1
+	}
+
+	return &ModulatedFilter{
+		filter:    filter,
+		lfo:       lfo,
+		frequency: filter.Frequency(),
+		depth:     depth,
+		interval:  interval,
+		to:        *filter,
+	}
+}
+
+// Filter returns the modulated filter.
+func (m *ModulatedFilter) Filter() *Filter {
+	return m.filter
+}
+
+// SetDepth sets the modulation depth in octaves.
+func (m *ModulatedFilter) SetDepth(depth float64) {
+	m.depth = depth
+}
+
+// Apply applies the modulated filter and returns the value.
+func (m *ModulatedFilter) Apply(input float64) float64 {
+	if m.counter == 0 {
+		value := 0.0
+
+		for i := 0; i < m.interval; i++ {
+			value = m.lfo.Next()
+		}
+
+		m.from = m.to
+
+		if g := designWith(m.filter.method, m.filter.name, m.filter.sampleRate, m.modulatedFrequency(value), m.filter.q, m.filter.gain); g != nil {
+			m.to = *g
+			m.filter.frequency = g.frequency
+		}
+	}
+
+	m.counter++
+	m.filter.interpolateCoefficients(&m.from, &m.to, float64(m.counter)/float64(m.interval))
+
+	if m.counter == m.interval {
+		m.counter = 0
+	}
+
+	return m.filter.Apply(input)
+}
+
+// Reset clears the state of the filter and the LFO.
+func (m *ModulatedFilter) Reset() {
+	m.filter.Reset()
+	m.lfo.Reset()
+	m.counter = 0
+}
+
+// modulatedFrequency returns the frequency for the LFO value. It is kept below the Nyquist frequency.
+func (m *ModulatedFilter) modulatedFrequency(value float64) float64 {
+	frequency := m.frequency * math.Pow(2.0, m.depth*value)
+	nyquist := m.filter.sampleRate / 2.0
+
+	if frequency > 0.99*nyquist {
+		frequency = 0.99 * nyquist
+	}
+
+	return frequency
+}
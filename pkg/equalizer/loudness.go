@@ -0,0 +1,153 @@
+package equalizer
+
+import "math"
+
+// NewKWeighting returns the K-weighting filter of ITU-R BS.1770, the high shelf which models the head followed by the high-pass filter.
+// The coefficients are designed for the sample rate, so they match the values in the standard at 48 kHz.
+func NewKWeighting(sampleRate float64) *Chain {
+	// The shelf of the head.
+	k := math.Tan(math.Pi * 1681.974450955533 / sampleRate)
+	q := 0.7071752369554196
+	vh := math.Pow(10.0, 3.999843853973347/20.0)
+	vb := math.Pow(vh, 0.4996667741545416)
+	shelf := NewCustom(sampleRate, vh+vb*k/q+k*k, 2.0*(k*k-vh), vh-vb*k/q+k*k, 1.0+k/q+k*k, 2.0*(k*k-1.0), 1.0-k/q+k*k)
+
+	// The RLB high-pass filter.
+	k = math.Tan(math.Pi * 38.13547087602444 / sampleRate)
+	q = 0.5003270373238773
+	highPass := NewCustom(sampleRate, 1.0, -2.0, 1.0, 1.0+k/q+k*k, 2.0*(k*k-1.0), 1.0-k/q+k*k)
+
+	return NewChain(shelf, highPass)
+}
+
+// IntegratedLoudness returns the gated loudness of the whole signal in LUFS as defined by ITU-R BS.1770-4.
+//
+// Parameters:
+//
+//     - channels ... Samples of each channel. e.g. left and right
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//
+// NOTE: All channels are weighted equally, which is correct for mono and stereo. It returns -Inf when the signal is shorter than 400 ms or silent.
+// The channels of the different lengths are measured up to the shortest one.
+func IntegratedLoudness(channels [][]float64, sampleRate float64) float64 {
+	blockSize := int(math.Round(0.4 * sampleRate))
+	hop := int(math.Round(0.1 * sampleRate))
+	length := shortest(channels)
+
+	if blockSize < 1 || hop < 1 || length < blockSize {
+		return math.Inf(-1)
+	}
+
+	// squares is the running sum of the K-weighted power of all channels.
+	squares := make([]float64, length+1)
+
+	for _, channel := range channels {
+		weighting := NewKWeighting(sampleRate)
+
+		for i, sample := range channel[:length] {
+			y := weighting.Apply(sample)
+			squares[i+1] += y * y
+		}
+	}
+	for i := 1; i < len(squares); i++ {
+		squares[i] += squares[i-1]
+	}
+
+	var powers []float64
+
+	for start := 0; start+blockSize <= length; start += hop {
+		power := (squares[start+blockSize] - squares[start]) / float64(blockSize)
+
+		// The absolute gate at -70 LUFS.
+		if blockLoudness(power) > -70.0 {
+			powers = append(powers, power)
+		}
+	}
+
+	// The relative gate 10 LU below the loudness of the blocks above the absolute gate.
+	threshold := blockLoudness(mean(powers)) - 10.0
+	gated := powers[:0]
+
+	for _, power := range powers {
+		if blockLoudness(power) > threshold {
+			gated = append(gated, power)
+		}
+	}
+
+	return blockLoudness(mean(gated))
+}
+
+// shortest returns the length of the shortest channel, or 0 when there is no channel.
+func shortest(channels [][]float64) int {
+	if len(channels) == 0 {
+		return 0
+	}
+
+	length := len(channels[0])
+
+	for _, channel := range channels[1:] {
+		if len(channel) < length {
+			length = len(channel)
+		}
+	}
+
+	return length
+}
+
+// NormalizeLUFS scales the channels in place so the integrated loudness becomes the target and returns the gain in dB.
+// The gain is lowered when the true peak would exceed the ceiling, so the result can be quieter than the target.
+//
+// Parameters:
+//
+//     - channels ... Samples of each channel. e.g. left and right
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - targetLUFS ... Target integrated loudness in LUFS. e.g. -14.0 for the music streaming services
+//     - ceiling ... Maximum true peak in dBTP. e.g. -1.0
+//
+// NOTE: The silence and the signal shorter than 400 ms are left as they are and the gain 0 is returned.
+func NormalizeLUFS(channels [][]float64, sampleRate, targetLUFS, ceiling float64) float64 {
+	loudness := IntegratedLoudness(channels, sampleRate)
+
+	if math.IsInf(loudness, -1) {
+		return 0.0
+	}
+
+	gainDB := targetLUFS - loudness
+	peak := math.Inf(-1)
+
+	for _, channel := range channels {
+		peak = math.Max(peak, TruePeak(channel, sampleRate))
+	}
+	if peak+gainDB > ceiling {
+		gainDB = ceiling - peak
+	}
+
+	gain := math.Pow(10.0, gainDB/20.0)
+
+	for _, channel := range channels {
+		for i := range channel {
+			channel[i] *= gain
+		}
+	}
+
+	return gainDB
+}
+
+// blockLoudness converts the mean square to LUFS.
+func blockLoudness(power float64) float64 {
+	return -0.691 + 10.0*math.Log10(power)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+
+	sum := 0.0
+
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
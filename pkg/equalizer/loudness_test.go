@@ -0,0 +1,33 @@
+package equalizer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegratedLoudness(t *testing.T) {
+	sampleRate := 48000.0
+	tone := make([]float64, int(sampleRate))
+
+	// The 1 kHz sine wave of the peak -20 dBFS in both channels is -20 LUFS, because the power of the channels is summed.
+	for i := range tone {
+		tone[i] = 0.1 * math.Sin(2.0*math.Pi*1000.0*float64(i)/sampleRate)
+	}
+
+	stereo := IntegratedLoudness([][]float64{tone, tone}, sampleRate)
+
+	if math.Abs(stereo-(-20.0)) > 0.1 {
+		t.Errorf("stereo loudness is %v LUFS, want -20 LUFS", stereo)
+	}
+
+	// The longer channel is measured up to the shorter one instead of panicking.
+	if got := IntegratedLoudness([][]float64{tone, tone[:len(tone)/2]}, sampleRate); math.IsInf(got, 0) || math.IsNaN(got) {
+		t.Errorf("loudness of the channels of the different lengths is %v", got)
+	}
+	if got := IntegratedLoudness([][]float64{tone, tone[:100]}, sampleRate); !math.IsInf(got, -1) {
+		t.Errorf("loudness of the channel shorter than 400 ms is %v, want -Inf", got)
+	}
+	if got := IntegratedLoudness(nil, sampleRate); !math.IsInf(got, -1) {
+		t.Errorf("loudness of no channel is %v, want -Inf", got)
+	}
+}
@@ -0,0 +1,103 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// MaxMakeupGain is the largest compensation of the automatic makeup gain in dB.
+const MaxMakeupGain = 24.0
+
+// makeupGain compensates the level change of the chain estimated from its frequency response.
+type makeupGain struct {
+	frequencies []float64
+
+	// interval is the number of the samples between the estimations, and countdown is the rest until the next one.
+	interval  int
+	countdown int
+
+	// gain follows target by the one-pole smoothing with the coefficient k.
+	gain   float64
+	target float64
+	k      float64
+}
+
+// SetAutoGain enables the automatic makeup gain, which estimates the level change of the chain from its frequency
+// response and compensates it, so the louder setting does not win the A/B comparison. The level is the average power
+// over 20 Hz to 20 kHz with the equal weight per octave, i.e. of the pink noise. The bands changed while processing are
+// followed within 0.1 seconds and the gain moves smoothly. The sample rate 0 disables it.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//
+// NOTE: The processors which do not provide FrequencyResponse, e.g. the compressor, are treated as unity gain. The compensation is limited to MaxMakeupGain.
+func (c *Chain) SetAutoGain(sampleRate float64) {
+	if !(sampleRate > 0.0) {
+		c.makeup = nil
+
+		return
+	}
+
+	m := &makeupGain{
+		interval: int(0.1 * sampleRate),
+		k:        1.0 - math.Exp(-1.0/(0.05*sampleRate)),
+	}
+
+	upper := math.Min(20000.0, 0.45*sampleRate)
+
+	// Sixth octaves are fine enough for the biquads of the parametric equalizer.
+	for f := 20.0; f <= upper; f *= math.Pow(2.0, 1.0/6.0) {
+		m.frequencies = append(m.frequencies, f)
+	}
+
+	m.target = m.estimate(c)
+	m.gain = m.target
+	m.countdown = m.interval
+	c.makeup = m
+}
+
+// MakeupGain returns the compensation of the automatic makeup gain in dB, or 0 when it is disabled.
+func (c *Chain) MakeupGain() float64 {
+	if c.makeup == nil {
+		return 0.0
+	}
+
+	return 20.0 * math.Log10(c.makeup.target)
+}
+
+// estimate returns the linear gain which cancels the average power gain of the chain.
+func (m *makeupGain) estimate(c *Chain) float64 {
+	if len(m.frequencies) == 0 {
+		return 1.0
+	}
+
+	power := 0.0
+
+	for _, frequency := range m.frequencies {
+		magnitude := cmplx.Abs(c.FrequencyResponse(frequency))
+		power += magnitude * magnitude
+	}
+
+	db := -10.0 * math.Log10(power/float64(len(m.frequencies)))
+
+	if math.IsNaN(db) {
+		return 1.0
+	}
+
+	return math.Pow(10.0, math.Max(-MaxMakeupGain, math.Min(db, MaxMakeupGain))/20.0)
+}
+
+// next returns the gain of the next sample.
+func (m *makeupGain) next(c *Chain) float64 {
+	m.countdown--
+
+	if m.countdown <= 0 {
+		m.target = m.estimate(c)
+		m.countdown = m.interval
+	}
+
+	m.gain += m.k * (m.target - m.gain)
+
+	return m.gain
+}
@@ -0,0 +1,307 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// designMatched returns the filter whose magnitude matches the analog prototype of the cookbook, or nil when the filter
+// name is not built-in. The poles are the analog poles mapped by z = exp(sT), and the zeros are chosen so the magnitude
+// is the same as the analog one at 0 Hz, at the frequency and at the Nyquist frequency, after M. Vicanek, "Matched
+// Second Order Digital Filters". Unlike the bilinear transform, the response is not cramped toward the Nyquist frequency,
+// e.g. the high-shelf at 16 kHz keeps its slope at 44.1 kHz, and the frequency may be above the Nyquist frequency.
+//
+// NOTE: The band width in octaves is converted to the Q value of the analog prototype. When the bilinear transform is
+// closer to the analog prototype, e.g. the shelf of the high Q far below the Nyquist frequency, it is used instead.
+// The peaking and shelf filters of 0 dB are the identity, whose poles would be on the unit circle near the Nyquist frequency.
+func designMatched(name FilterName, sampleRate, frequency, q, gain float64) *Filter {
+	b, a, ok := analogPrototype(name, q, gain)
+
+	if !ok {
+		return nil
+	}
+	if gain == 0.0 && (name == Peaking || name == LowShelf || name == HighShelf) {
+		return &Filter{
+			name:       name,
+			sampleRate: sampleRate,
+			frequency:  frequency,
+			q:          q,
+			scale:      1.0,
+			b0:         1.0,
+			method:     matchedDesign,
+		}
+	}
+
+	w0 := 2.0 * math.Pi * frequency / sampleRate
+	f := matchAnalog(name, sampleRate, frequency, q, gain)
+
+	if frequency < sampleRate/2.0 {
+		g := design(name, sampleRate, frequency, q, gain)
+
+		if f == nil || analogDeviation(g, b, a, w0) < analogDeviation(f, b, a, w0) {
+			g.method = matchedDesign
+
+			return g
+		}
+	}
+
+	return f
+}
+
+// matchAnalog returns the matched filter of the built-in filter name. It returns nil if the cut cannot be designed.
+func matchAnalog(name FilterName, sampleRate, frequency, q, gain float64) *Filter {
+	b, a, _ := analogPrototype(name, q, gain)
+
+	if gain < 0.0 && (name == Peaking || name == LowShelf || name == HighShelf) {
+		return invertMatched(matchAnalog(name, sampleRate, frequency, q, -gain))
+	}
+
+	w0 := 2.0 * math.Pi * frequency / sampleRate
+
+	// The poles above the Nyquist frequency would alias, so their angle is limited to pi.
+	d := cmplx.Sqrt(complex(a[1]*a[1]-4.0*a[2]*a[0], 0.0))
+	z1 := polePosition((complex(-a[1], 0.0)+d)/complex(2.0*a[2], 0.0), w0)
+	z2 := polePosition((complex(-a[1], 0.0)-d)/complex(2.0*a[2], 0.0), w0)
+	a1 := -real(z1 + z2)
+	a2 := real(z1 * z2)
+
+	// target returns the squared magnitude of the numerator which makes the digital magnitude the analog one at w.
+	target := func(w float64) float64 {
+		z := cmplx.Exp(complex(0.0, -w))
+		denominator := 1.0 + complex(a1, 0.0)*z + complex(a2, 0.0)*z*z
+		d := cmplx.Abs(denominator)
+
+		return analogMagnitude(b, a, w/w0) * d * d
+	}
+
+	// The response is matched at the frequency, or at 0.9 of the Nyquist frequency above it, where the digital
+	// response still can follow the analog one.
+	wm := math.Min(w0, 0.9*math.Pi)
+
+	if w0 >= math.Pi {
+		wm = math.Pi / 2.0
+	}
+	phi := math.Pow(math.Sin(wm/2.0), 2.0)
+
+	var b0, b1, b2 float64
+
+	switch name {
+	case AllPass:
+		b0, b1, b2 = a2, a1, 1.0
+	case HighPass:
+		// The double zero at z = 1 keeps the slope of 12 dB/oct.
+		b0 = math.Sqrt(target(wm)) / (4.0 * phi)
+		b1, b2 = -2.0*b0, b0
+	case BandReject:
+		if w0 >= math.Pi {
+			b0, b1, b2 = matchNumerator(target, wm)
+
+			break
+		}
+
+		// The zeros on the unit circle keep the notch infinitely deep.
+		b0 = math.Sqrt(target(0.0)) / (2.0 - 2.0*math.Cos(w0))
+		b1, b2 = -2.0*math.Cos(w0)*b0, b0
+	case LowPass:
+		// b2 = 0 leaves the magnitude at the Nyquist frequency free, so the low-pass above the frequency is not lifted.
+		B0 := target(0.0)
+		B1 := math.Max(0.0, (target(wm)-B0*(1.0-phi))/phi)
+		b0 = (math.Sqrt(B0) + math.Sqrt(B1)) / 2.0
+		b1 = math.Sqrt(B0) - b0
+	default:
+		b0, b1, b2 = matchNumerator(target, wm)
+	}
+
+	f := &Filter{
+		name:       name,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		scale:      1.0,
+		a1:         a1,
+		a2:         a2,
+		b0:         b0,
+		b1:         b1,
+		b2:         b2,
+		method:     matchedDesign,
+	}
+
+	if name == LowShelf || name == HighShelf || name == Peaking {
+		f.gain = gain
+	}
+
+	return f
+}
+
+// analogDeviation returns the largest ratio in dB between the magnitude of the filter and the analog prototype scaled to
+// w0 radians per sample. The frequencies where the prototype is below -60 dB are skipped.
+func analogDeviation(f *Filter, b, a [3]float64, w0 float64) float64 {
+	deviation := 0.0
+
+	for i := 0; i < 64; i++ {
+		w := math.Pi * math.Pow(1000.0, float64(i-64)/64.0)
+		target := analogMagnitude(b, a, w/w0)
+
+		if target < 1e-6 {
+			continue
+		}
+
+		magnitude := math.Pow(cmplx.Abs(f.FrequencyResponse(w*f.sampleRate/(2.0*math.Pi))), 2.0)
+		deviation = math.Max(deviation, math.Abs(10.0*math.Log10((magnitude+1e-12)/target)))
+	}
+
+	return deviation
+}
+
+// invertMatched returns the cut which is the inverse of the boost f, e.g. the peaking filter of -6 dB from that of 6 dB.
+// The analog cut is the inverse of the analog boost, and the boost is matched closer near the Nyquist frequency, because
+// its numerator does not have to reach the deep notch. It returns nil if the boost cannot be inverted.
+func invertMatched(f *Filter) *Filter {
+	if f == nil {
+		return nil
+	}
+
+	b0, b1, b2 := minimumPhase(f.b0, f.b1, f.b2)
+
+	// The zeros of the boost become the poles of the cut, so they must be inside the unit circle.
+	if !(b0 != 0.0 && math.Abs(b2/b0) < 1.0 && math.Abs(b1/b0) < 1.0+b2/b0) {
+		return nil
+	}
+
+	g := *f
+
+	g.gain = -f.gain
+	g.b0, g.b1, g.b2 = 1.0/b0, f.a1/b0, f.a2/b0
+	g.a1, g.a2 = b1/b0, b2/b0
+
+	return &g
+}
+
+// minimumPhase returns the numerator which has the same magnitude with the zeros outside the unit circle reflected inside.
+func minimumPhase(b0, b1, b2 float64) (float64, float64, float64) {
+	if b0 == 0.0 {
+		return b0, b1, b2
+	}
+
+	d := cmplx.Sqrt(complex(b1*b1-4.0*b0*b2, 0.0))
+	roots := [2]complex128{(complex(-b1, 0.0) + d) / complex(2.0*b0, 0.0), (complex(-b1, 0.0) - d) / complex(2.0*b0, 0.0)}
+	k := b0
+
+	// |1 - r z^-1| = |r| |1 - z^-1 / conj(r)| on the unit circle.
+	for i, r := range roots {
+		if cmplx.Abs(r) > 1.0 {
+			k *= cmplx.Abs(r)
+			roots[i] = 1.0 / cmplx.Conj(r)
+		}
+	}
+
+	return k, -k * real(roots[0]+roots[1]), k * real(roots[0]*roots[1])
+}
+
+// matchNumerator returns the numerator whose squared magnitude is target(w) at 0 Hz, at the matched frequency wm and
+// at the Nyquist frequency. The squared magnitude of the numerator is
+//
+//	|B(w)|^2 = B0 (1 - phi) + B1 phi + 4 B2 (1 - phi) phi, where B0 = (b0 + b1 + b2)^2, B1 = (b0 - b1 + b2)^2 and B2 = -4 b0 b2
+//
+// b0 + b2 and b0 b2 give b0 and b2 as the roots of the quadratic, which may be real with either sign of b0 - b1 + b2.
+// The first order numerator, which only matches 0 Hz and the Nyquist frequency, is always real. The candidate closest
+// to the target between the matched frequencies is chosen, because the exact match may swing between them.
+func matchNumerator(target func(w float64) float64, wm float64) (b0, b1, b2 float64) {
+	B0, B1 := target(0.0), target(math.Pi)
+	phi := math.Pow(math.Sin(wm/2.0), 2.0)
+	B2 := (target(wm) - B0*(1.0-phi) - B1*phi) / (4.0 * (1.0 - phi) * phi)
+
+	candidates := [][3]float64{
+		{(math.Sqrt(B0) + math.Sqrt(B1)) / 2.0, (math.Sqrt(B0) - math.Sqrt(B1)) / 2.0, 0.0},
+	}
+
+	for _, sign := range []float64{1.0, -1.0} {
+		W := (math.Sqrt(B0) + sign*math.Sqrt(B1)) / 2.0
+		D := W*W + B2
+
+		if D >= 0.0 {
+			b0 := (W + math.Sqrt(D)) / 2.0
+			candidates = append(candidates, [3]float64{b0, (math.Sqrt(B0) - sign*math.Sqrt(B1)) / 2.0, W - b0})
+		}
+	}
+
+	best := math.Inf(1)
+
+	for _, c := range candidates {
+		if deviation := numeratorDeviation(c, target); deviation < best {
+			best = deviation
+			b0, b1, b2 = c[0], c[1], c[2]
+		}
+	}
+
+	return b0, b1, b2
+}
+
+// numeratorDeviation returns the largest ratio in dB between the squared magnitude of the numerator b and the target.
+func numeratorDeviation(b [3]float64, target func(w float64) float64) float64 {
+	deviation := 0.0
+
+	for i := 1; i < 32; i++ {
+		w := math.Pi * float64(i) / 32.0
+		z := cmplx.Exp(complex(0.0, -w))
+		magnitude := math.Pow(cmplx.Abs(complex(b[0], 0.0)+complex(b[1], 0.0)*z+complex(b[2], 0.0)*z*z), 2.0)
+
+		deviation = math.Max(deviation, math.Abs(10.0*math.Log10((magnitude+1e-12)/(target(w)+1e-12))))
+	}
+
+	return deviation
+}
+
+// analogPrototype returns the numerator and the denominator of the analog prototype of the cookbook whose frequency is 1 rad/s.
+//
+//	H(s) = (b[0] + b[1]*s + b[2]*s^2) / (a[0] + a[1]*s + a[2]*s^2)
+func analogPrototype(name FilterName, q, gain float64) (b, a [3]float64, ok bool) {
+	// The band width in octaves of the band-pass, band-reject and peaking filters is the Q value of the prototype.
+	if name == BandPass || name == BandReject || name == Peaking {
+		q = 1.0 / (2.0 * math.Sinh(math.Log(2.0)/2.0*q))
+	}
+
+	A := math.Pow(10.0, gain/40.0)
+	a = [3]float64{1.0, 1.0 / q, 1.0}
+
+	switch name {
+	case LowPass:
+		b = [3]float64{1.0, 0.0, 0.0}
+	case HighPass:
+		b = [3]float64{0.0, 0.0, 1.0}
+	case AllPass:
+		b = [3]float64{1.0, -1.0 / q, 1.0}
+	case BandPass:
+		b = [3]float64{0.0, 1.0 / q, 0.0}
+	case BandReject:
+		b = [3]float64{1.0, 0.0, 1.0}
+	case Peaking:
+		b = [3]float64{1.0, A / q, 1.0}
+		a = [3]float64{1.0, 1.0 / (A * q), 1.0}
+	case LowShelf:
+		b = [3]float64{A * A, A * math.Sqrt(A) / q, A}
+		a = [3]float64{1.0, math.Sqrt(A) / q, A}
+	case HighShelf:
+		b = [3]float64{A, A * math.Sqrt(A) / q, A * A}
+		a = [3]float64{A, math.Sqrt(A) / q, 1.0}
+	default:
+		return b, a, false
+	}
+
+	return b, a, true
+}
+
+// analogMagnitude returns the squared magnitude of the analog prototype at the angular frequency w relative to its frequency.
+func analogMagnitude(b, a [3]float64, w float64) float64 {
+	numerator := complex(b[0]-b[2]*w*w, b[1]*w)
+	denominator := complex(a[0]-a[2]*w*w, a[1]*w)
+
+	return math.Pow(cmplx.Abs(numerator), 2.0) / math.Pow(cmplx.Abs(denominator), 2.0)
+}
+
+// polePosition returns the digital pole mapped from the analog pole s of the prototype scaled to w0 radians per sample.
+func polePosition(s complex128, w0 float64) complex128 {
+	s *= complex(w0, 0.0)
+
+	return cmplx.Exp(complex(real(s), math.Max(-math.Pi, math.Min(imag(s), math.Pi))))
+}
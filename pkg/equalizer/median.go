@@ -0,0 +1,144 @@
+package equalizer
+
+import "container/heap"
+
+// Median is the running median filter which removes the spikes and the outliers shorter than the half of the window
+// while keeping the steps. Each sample is processed in O(log size) time.
+type Median struct {
+	// nodes is the window in the order of arrival, and position is the oldest one.
+	nodes    []*medianNode
+	position int
+
+	// low holds the smaller half as the max-heap and high holds the larger half as the min-heap.
+	low  medianHeap
+	high medianHeap
+}
+
+// medianNode is the sample in the window. index is the position in the heap which holds it.
+type medianNode struct {
+	value float64
+	index int
+	low   bool
+}
+
+// NewMedian returns the median filter.
+//
+// Parameters:
+//
+//     - size ... Number of the samples in the window. e.g. 5
+//
+// NOTE: The even size is rounded up to the odd number. The output is delayed by (size-1)/2 samples.
+func NewMedian(size int) *Median {
+	if size < 1 {
+		size = 1
+	}
+	if size%2 == 0 {
+		size++
+	}
+
+	m := &Median{
+		nodes: make([]*medianNode, size),
+		low:   medianHeap{max: true},
+	}
+
+	m.Reset()
+
+	return m
+}
+
+// Size returns the number of the samples in the window.
+func (m *Median) Size() int {
+	return len(m.nodes)
+}
+
+// Apply replaces the oldest sample in the window with the input and returns the median. The missing inputs before the first one are treated as 0.
+func (m *Median) Apply(input float64) float64 {
+	node := m.nodes[m.position]
+	m.position = (m.position + 1) % len(m.nodes)
+
+	if node.low {
+		heap.Remove(&m.low, node.index)
+	} else {
+		heap.Remove(&m.high, node.index)
+	}
+
+	node.value = input
+
+	if m.low.Len() > 0 && input <= m.low.nodes[0].value {
+		heap.Push(&m.low, node)
+	} else {
+		heap.Push(&m.high, node)
+	}
+
+	// Keep one more sample in the low half than in the high half.
+	for m.low.Len() > m.high.Len()+1 {
+		heap.Push(&m.high, heap.Pop(&m.low))
+	}
+	for m.low.Len() <= m.high.Len() {
+		heap.Push(&m.low, heap.Pop(&m.high))
+	}
+
+	return m.low.nodes[0].value
+}
+
+// ProcessBuffer applies the median filter to the buffer in place.
+func (m *Median) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = m.Apply(buffer[i])
+	}
+}
+
+// Reset fills the window with 0.
+func (m *Median) Reset() {
+	m.low.nodes = m.low.nodes[:0]
+	m.high.nodes = m.high.nodes[:0]
+	m.position = 0
+
+	for i := range m.nodes {
+		m.nodes[i] = &medianNode{}
+
+		if i <= len(m.nodes)/2 {
+			heap.Push(&m.low, m.nodes[i])
+		} else {
+			heap.Push(&m.high, m.nodes[i])
+		}
+	}
+}
+
+// medianHeap implements heap.Interface and keeps the index of each node up to date, so any node can be removed.
+type medianHeap struct {
+	nodes []*medianNode
+	max   bool
+}
+
+func (h medianHeap) Len() int {
+	return len(h.nodes)
+}
+
+func (h medianHeap) Less(i, j int) bool {
+	if h.max {
+		return h.nodes[i].value > h.nodes[j].value
+	}
+
+	return h.nodes[i].value < h.nodes[j].value
+}
+
+func (h medianHeap) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.nodes[i].index = i
+	h.nodes[j].index = j
+}
+
+func (h *medianHeap) Push(x interface{}) {
+	node := x.(*medianNode)
+	node.index = len(h.nodes)
+	node.low = h.max
+	h.nodes = append(h.nodes, node)
+}
+
+func (h *medianHeap) Pop() interface{} {
+	node := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+
+	return node
+}
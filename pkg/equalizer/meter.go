@@ -0,0 +1,68 @@
+package equalizer
+
+import "math"
+
+// LevelMeter is the peak or RMS meter with the attack and release ballistics. It passes the signal through, so it can be
+// placed anywhere in the Chain to monitor the level at that point.
+type LevelMeter struct {
+	follower *EnvelopeFollower
+	max      float64
+}
+
+// NewLevelMeter returns the level meter.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - attack ... Time constant of the rising level in seconds. e.g. 0.01
+//     - release ... Time constant of the falling level in seconds. e.g. 0.3
+//     - mode ... PeakEnvelope or RMSEnvelope.
+func NewLevelMeter(sampleRate, attack, release float64, mode EnvelopeMode) *LevelMeter {
+	return &LevelMeter{
+		follower: NewEnvelopeFollower(sampleRate, attack, release, mode),
+	}
+}
+
+// NewVUMeter returns the RMS meter whose needle reaches 99% of the steady level in 300 ms like the VU meter.
+func NewVUMeter(sampleRate float64) *LevelMeter {
+	// 300 ms is ln(100) time constants. In RMSEnvelope mode, the release time averages the mean square and the attack is not needed.
+	tau := 0.3 / math.Log(100.0)
+
+	return NewLevelMeter(sampleRate, 0.0, tau, RMSEnvelope)
+}
+
+// NewPPMMeter returns the quasi-peak programme meter which rises almost fully in 10 ms and falls 24 dB in 2.8 seconds like the BBC PPM.
+func NewPPMMeter(sampleRate float64) *LevelMeter {
+	// 24 dB is 24/(20*log10(e)) time constants.
+	return NewLevelMeter(sampleRate, 0.0025, 2.8/(24.0/(20.0*math.Log10(math.E))), PeakEnvelope)
+}
+
+// Apply measures the input and returns it as it is.
+func (m *LevelMeter) Apply(input float64) float64 {
+	m.max = math.Max(m.max, m.follower.Apply(input))
+
+	return input
+}
+
+// ProcessBuffer measures the buffer without modifying it.
+func (m *LevelMeter) ProcessBuffer(buffer []float64) {
+	for _, sample := range buffer {
+		m.Apply(sample)
+	}
+}
+
+// Level returns the current reading in dBFS. It returns -Inf for the silence.
+func (m *LevelMeter) Level() float64 {
+	return 20.0 * math.Log10(m.follower.Value())
+}
+
+// Max returns the highest reading in dBFS since the start or Reset, i.e. the peak hold.
+func (m *LevelMeter) Max() float64 {
+	return 20.0 * math.Log10(m.max)
+}
+
+// Reset clears the reading and the peak hold.
+func (m *LevelMeter) Reset() {
+	m.follower.Reset()
+	m.max = 0.0
+}
@@ -0,0 +1,173 @@
+package equalizer
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Stats is the snapshot of the counters and the gauges of Metrics.
+type Stats struct {
+	// Samples is the number of the processed samples.
+	Samples int64
+
+	// Blocks is the number of the buffers processed by ProcessBuffer.
+	Blocks int64
+
+	// Clips is the number of the output samples whose magnitude exceeds 1.0.
+	Clips int64
+
+	// Peak is the largest magnitude of the output.
+	Peak float64
+
+	// ProcessingTime is the total time spent in ProcessBuffer, and MaxBlockTime and LastBlockTime are of one buffer.
+	ProcessingTime time.Duration
+	MaxBlockTime   time.Duration
+	LastBlockTime  time.Duration
+}
+
+// PeakDBFS returns the peak in dBFS, or -Inf before any sound.
+func (s Stats) PeakDBFS() float64 {
+	return 20.0 * math.Log10(s.Peak)
+}
+
+// AverageBlockTime returns the average time spent in ProcessBuffer per buffer.
+func (s Stats) AverageBlockTime() time.Duration {
+	if s.Blocks == 0 {
+		return 0
+	}
+
+	return s.ProcessingTime / time.Duration(s.Blocks)
+}
+
+// Metrics measures the processor for the long-running services. The stats can be read from any goroutine while processing.
+type Metrics struct {
+	processor Processor
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// NewMetrics returns the metrics which wraps the processor. Place it in the chain instead of the processor.
+//
+// NOTE: The time is measured per buffer, so the samples processed by Apply are counted but not timed.
+func NewMetrics(processor Processor) *Metrics {
+	return &Metrics{
+		processor: processor,
+	}
+}
+
+// Processor returns the measured processor.
+func (m *Metrics) Processor() Processor {
+	return m.processor
+}
+
+// Stats returns the snapshot of the stats.
+func (m *Metrics) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.stats
+}
+
+// ResetStats clears the stats.
+func (m *Metrics) ResetStats() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats = Stats{}
+}
+
+// Apply applies the processor and counts the value.
+func (m *Metrics) Apply(input float64) float64 {
+	output := m.processor.Apply(input)
+
+	m.mu.Lock()
+	m.stats.Samples++
+	m.measure(output)
+	m.mu.Unlock()
+
+	return output
+}
+
+// ProcessBuffer applies the processor to the buffer in place and measures the time.
+func (m *Metrics) ProcessBuffer(buffer []float64) {
+	start := time.Now()
+
+	if p, ok := m.processor.(bufferProcessor); ok {
+		p.ProcessBuffer(buffer)
+	} else {
+		for i := range buffer {
+			buffer[i] = m.processor.Apply(buffer[i])
+		}
+	}
+
+	elapsed := time.Since(start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, value := range buffer {
+		m.measure(value)
+	}
+
+	m.stats.Samples += int64(len(buffer))
+	m.stats.Blocks++
+	m.stats.ProcessingTime += elapsed
+	m.stats.LastBlockTime = elapsed
+
+	if elapsed > m.stats.MaxBlockTime {
+		m.stats.MaxBlockTime = elapsed
+	}
+}
+
+// measure updates the peak and the clips with the output value. m.mu must be held.
+func (m *Metrics) measure(value float64) {
+	magnitude := math.Abs(value)
+
+	if magnitude > m.stats.Peak {
+		m.stats.Peak = magnitude
+	}
+	if magnitude > 1.0 {
+		m.stats.Clips++
+	}
+}
+
+// Reset clears the state variables of the processor. The stats are kept, so call ResetStats to clear them.
+func (m *Metrics) Reset() {
+	if r, ok := m.processor.(resetter); ok {
+		r.Reset()
+	}
+}
+
+// Latency returns the latency of the processor in samples.
+func (m *Metrics) Latency() int {
+	if l, ok := m.processor.(latencyReporter); ok {
+		return l.Latency()
+	}
+
+	return 0
+}
+
+// setLatencyOwner passes the owner to the processor, e.g. the nested Chain, whose latency can change.
+func (m *Metrics) setLatencyOwner(owner *Chain) {
+	if o, ok := m.processor.(latencyOwned); ok {
+		o.setLatencyOwner(owner)
+	}
+}
+
+// FrequencyResponse returns the complex frequency response of the processor, or unity gain when it is unknown.
+func (m *Metrics) FrequencyResponse(frequency float64) complex128 {
+	if r, ok := m.processor.(responder); ok {
+		return r.FrequencyResponse(frequency)
+	}
+
+	return complex(1.0, 0.0)
+}
+
+// SetHooks sets the hooks to the processor when it accepts them.
+func (m *Metrics) SetHooks(hooks Hooks) {
+	if s, ok := m.processor.(hooksSetter); ok {
+		s.SetHooks(hooks)
+	}
+}
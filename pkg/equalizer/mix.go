@@ -0,0 +1,82 @@
+package equalizer
+
+import "math"
+
+// SetMix sets the amount of the filtered signal in percent from 0 to 100, and the rest is the unfiltered signal, e.g.
+// for the parallel equalization. The change is ramped over BypassTime. The default is 100.
+//
+// NOTE: The responses, e.g. FrequencyResponse, are of the filter regardless of the mix.
+func (f *Filter) SetMix(percent float64) {
+	f.dry.set(dryAmount(percent), int(BypassTime.Seconds()*f.sampleRate))
+}
+
+// Mix returns the amount of the filtered signal in percent.
+func (f *Filter) Mix() float64 {
+	return 100.0 * (1.0 - f.dry.target)
+}
+
+// SetMix sets the amount of the processed signal in percent from 0 to 100, and the rest is the unprocessed signal, e.g.
+// for the parallel equalization. The change is ramped over the ramp set by SetBypassRamp. The default is 100.
+//
+// NOTE: The unprocessed signal is delayed by the latency of the chain, so the mix does not comb filter with the block based processors, e.g. the convolver.
+func (c *Chain) SetMix(percent float64) {
+	c.dry.set(dryAmount(percent), c.rampLength())
+}
+
+// Mix returns the amount of the processed signal in percent.
+func (c *Chain) Mix() float64 {
+	return 100.0 * (1.0 - c.dry.target)
+}
+
+// dryAmount returns the amount of the unprocessed signal of the mix in percent.
+func dryAmount(percent float64) float64 {
+	return 1.0 - math.Max(0.0, math.Min(percent, 100.0))/100.0
+}
+
+// delayLine delays the signal by the whole samples. The zero value passes the signal through.
+type delayLine struct {
+	buffer   []float64
+	position int
+}
+
+// resize changes the delay in samples. The delayed samples are cleared when it is changed.
+func (d *delayLine) resize(delay int) {
+	if len(d.buffer) == delay {
+		return
+	}
+
+	d.buffer = make([]float64, delay)
+	d.position = 0
+}
+
+// reset clears the delayed samples.
+func (d *delayLine) reset() {
+	for i := range d.buffer {
+		d.buffer[i] = 0.0
+	}
+
+	d.position = 0
+}
+
+// feed pushes the values without reading the delayed samples.
+func (d *delayLine) feed(values []float64) {
+	if len(d.buffer) == 0 {
+		return
+	}
+	for _, value := range values {
+		d.process(value)
+	}
+}
+
+// process returns the value delayed by the delay.
+func (d *delayLine) process(input float64) float64 {
+	if len(d.buffer) == 0 {
+		return input
+	}
+
+	output := d.buffer[d.position]
+	d.buffer[d.position] = input
+	d.position = (d.position + 1) % len(d.buffer)
+
+	return output
+}
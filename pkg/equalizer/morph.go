@@ -0,0 +1,66 @@
+package equalizer
+
+import "math"
+
+// Morph returns the filter between a and b at t from 0 to 1, so the user interface can animate between two settings.
+//
+// Parameters:
+//
+//     - a ... Filter at t = 0. The result has its sample rate.
+//     - b ... Filter at t = 1.
+//     - t ... Position between a and b. It is limited between 0 and 1.
+//
+// NOTE: When a and b have the same filter name, the design parameters are interpolated, i.e. the frequency and Q on the
+// logarithmic scale and the gain in dB, and the filter is designed from them, so the morph sweeps like turning the knobs.
+// Otherwise the normalized coefficients are interpolated, which is stable because the stable biquads form the convex set,
+// and the filter between them is the custom filter.
+func Morph(a, b *Filter, t float64) *Filter {
+	f := &Filter{}
+
+	f.Morph(a, b, t)
+
+	return f
+}
+
+// Morph sets the design of f to the morph between a and b at t. The state variables of f are preserved, so it can be
+// called while processing the signal, e.g. for each block of the animation. See the function Morph for the details.
+func (f *Filter) Morph(a, b *Filter, t float64) {
+	t = math.Max(0.0, math.Min(t, 1.0))
+
+	f.name = a.name
+	f.sampleRate = a.sampleRate
+
+	if a.name == b.name {
+		frequency := math.Exp((1.0-t)*math.Log(a.frequency) + t*math.Log(b.frequency))
+		q := math.Exp((1.0-t)*math.Log(a.q) + t*math.Log(b.q))
+		gain := (1.0-t)*a.gain + t*b.gain
+
+		if g := designWith(a.method, a.name, a.sampleRate, frequency, q, gain); g != nil {
+			f.setCoefficients(g)
+
+			return
+		}
+	}
+
+	to := b
+
+	// Design b again at the sample rate of a, otherwise its coefficients mean the other frequencies.
+	if b.sampleRate != a.sampleRate {
+		if g := designWith(b.method, b.name, a.sampleRate, b.frequency, b.q, b.gain); g != nil {
+			to = g
+		}
+	}
+
+	switch {
+	case t <= 0.0:
+		f.name = a.name
+		f.setCoefficients(a)
+	case t >= 1.0:
+		f.name = to.name
+		f.setCoefficients(to)
+	default:
+		// The filter between the different kinds cannot be designed again, so it is the custom filter.
+		f.name = Custom
+		f.interpolateCoefficients(a, to, t)
+	}
+}
@@ -0,0 +1,80 @@
+package equalizer
+
+// MultiChannel applies the same biquad to every channel of the interleaved samples. The channels are processed in
+// pairs, so the kernel built with the neon tag on arm64 filters two channels with one vector instruction.
+type MultiChannel struct {
+	filter   *Filter
+	channels int
+
+	// states holds x1, x2, y1 and y2 of each pair of the channels, the values of the two channels side by side.
+	states [][8]float64
+
+	// last holds the state of the last channel when the number of the channels is odd.
+	last Filter
+}
+
+// NewMultiChannel returns the processor which applies the filter to each channel of the interleaved samples.
+//
+// Parameters:
+//
+//     - filter ... Filter whose coefficients are used. The changes, e.g. SetFrequency, apply from the next block.
+//     - channels ... Number of the channels. e.g. 2
+//
+// NOTE: The state variables of the filter itself are not used. SetBypassed and SetMix of the filter are ignored.
+func NewMultiChannel(filter *Filter, channels int) *MultiChannel {
+	if channels < 1 {
+		channels = 1
+	}
+
+	return &MultiChannel{
+		filter:   filter,
+		channels: channels,
+		states:   make([][8]float64, channels/2),
+	}
+}
+
+// Filter returns the filter whose coefficients are used.
+func (m *MultiChannel) Filter() *Filter {
+	return m.filter
+}
+
+// Channels returns the number of the channels.
+func (m *MultiChannel) Channels() int {
+	return m.channels
+}
+
+// ProcessInterleaved applies the filter to the interleaved samples in place. The incomplete frame at the end is not processed.
+func (m *MultiChannel) ProcessInterleaved(buffer []float64) {
+	frames := len(buffer) / m.channels
+
+	if frames == 0 {
+		return
+	}
+
+	f := m.filter
+
+	// Each coefficient is repeated for the two lanes of the vector.
+	coefficients := [10]float64{f.b0, f.b0, f.b1, f.b1, f.b2, f.b2, f.a1, f.a1, f.a2, f.a2}
+
+	for pair := range m.states {
+		biquadPair(&coefficients, &m.states[pair], buffer[2*pair:], frames, m.channels)
+	}
+	if m.channels%2 == 0 {
+		return
+	}
+
+	m.last.setCoefficients(f)
+
+	for i := m.channels - 1; i < frames*m.channels; i += m.channels {
+		buffer[i] = m.last.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables.
+func (m *MultiChannel) Reset() {
+	for pair := range m.states {
+		m.states[pair] = [8]float64{}
+	}
+
+	m.last.Reset()
+}
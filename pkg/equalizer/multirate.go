@@ -0,0 +1,219 @@
+package equalizer
+
+// multirateCutoff is the cut off frequency of the anti-aliasing and the anti-imaging filters relative to the lower Nyquist frequency.
+const multirateCutoff = 0.9
+
+// Decimator lowers the sample rate by the integer factor. The input is filtered by the low-pass FIR filter designed with
+// LowPassFIR, and only every factor-th output is computed.
+type Decimator struct {
+	factor       int
+	coefficients []float64
+
+	// history is the delay line of the inputs and position is where the next input is written.
+	history  []float64
+	position int
+	phase    int
+}
+
+// NewDecimator returns the decimator.
+//
+// Parameters:
+//
+//     - factor ... Decimation factor. e.g. 6 for 48000 Hz to 8000 Hz
+//     - taps ... Number of the FIR coefficients. 0 picks 32*factor+1, which gives about 74 dB of the anti-aliasing.
+//
+// NOTE: The output is delayed by (taps-1)/2 input samples.
+func NewDecimator(factor, taps int) *Decimator {
+	if factor < 1 {
+		factor = 1
+	}
+	if taps <= 0 {
+		taps = 32*factor + 1
+	}
+
+	// The sample rate is normalized to 2, so the cut off frequency is relative to the Nyquist frequency.
+	coefficients := LowPassFIR(2.0, multirateCutoff/float64(factor), taps)
+
+	return &Decimator{
+		factor:       factor,
+		coefficients: coefficients,
+		history:      make([]float64, len(coefficients)),
+	}
+}
+
+// Factor returns the decimation factor.
+func (d *Decimator) Factor() int {
+	return d.factor
+}
+
+// Latency returns the delay in the input samples.
+func (d *Decimator) Latency() int {
+	return (len(d.coefficients) - 1) / 2
+}
+
+// Process feeds the inputs and returns one output per factor inputs.
+func (d *Decimator) Process(input []float64) []float64 {
+	return d.ProcessInto(make([]float64, 0, len(input)/d.factor+1), input)
+}
+
+// ProcessInto feeds the inputs and appends the outputs to dst like append, so the caller which passes the previous
+// result sliced to zero length, e.g. output[:0], processes the stream without the allocation per block.
+func (d *Decimator) ProcessInto(dst, input []float64) []float64 {
+	for _, x := range input {
+		if y, ok := d.push(x); ok {
+			dst = append(dst, y)
+		}
+	}
+
+	return dst
+}
+
+// push feeds one input and returns the output when it is due. The outputs are aligned to the inputs 0, factor, 2*factor and so on.
+func (d *Decimator) push(x float64) (float64, bool) {
+	n := len(d.history)
+
+	d.history[d.position] = x
+	d.position = (d.position + 1) % n
+	phase := d.phase
+	d.phase = (d.phase + 1) % d.factor
+
+	if phase != 0 {
+		return 0.0, false
+	}
+
+	sum := 0.0
+
+	// The oldest input is at the position after the latest one.
+	for i, c := range d.coefficients {
+		sum += c * d.history[(d.position+i)%n]
+	}
+
+	return sum, true
+}
+
+// Reset clears the delay line.
+func (d *Decimator) Reset() {
+	for i := range d.history {
+		d.history[i] = 0.0
+	}
+
+	d.position = 0
+	d.phase = 0
+}
+
+// Interpolator raises the sample rate by the integer factor. It is the polyphase form of inserting factor-1 zeros after
+// each input and filtering with the low-pass FIR filter designed with LowPassFIR, so the zeros are never multiplied.
+type Interpolator struct {
+	factor int
+	taps   int
+
+	// phases[p] is the p-th polyphase branch of the FIR coefficients multiplied by the factor.
+	phases [][]float64
+
+	// history is the delay line of the inputs and position is where the next input is written.
+	history  []float64
+	position int
+}
+
+// NewInterpolator returns the interpolator.
+//
+// Parameters:
+//
+//     - factor ... Interpolation factor. e.g. 6 for 8000 Hz to 48000 Hz
+//     - taps ... Number of the FIR coefficients at the output rate. 0 picks 32*factor+1, which gives about 74 dB of the anti-imaging.
+//
+// NOTE: The output is delayed by (taps-1)/2 output samples.
+func NewInterpolator(factor, taps int) *Interpolator {
+	if factor < 1 {
+		factor = 1
+	}
+	if taps <= 0 {
+		taps = 32*factor + 1
+	}
+
+	coefficients := LowPassFIR(2.0, multirateCutoff/float64(factor), taps)
+	length := (len(coefficients) + factor - 1) / factor
+	phases := make([][]float64, factor)
+
+	for p := range phases {
+		phases[p] = make([]float64, length)
+
+		for k := range phases[p] {
+			if i := p + k*factor; i < len(coefficients) {
+				// The gain compensates the energy lost by the inserted zeros.
+				phases[p][k] = coefficients[i] * float64(factor)
+			}
+		}
+	}
+
+	return &Interpolator{
+		factor:  factor,
+		taps:    len(coefficients),
+		phases:  phases,
+		history: make([]float64, length),
+	}
+}
+
+// Factor returns the interpolation factor.
+func (in *Interpolator) Factor() int {
+	return in.factor
+}
+
+// Latency returns the delay in the output samples.
+func (in *Interpolator) Latency() int {
+	return (in.taps - 1) / 2
+}
+
+// Process feeds the inputs and returns factor outputs per input.
+func (in *Interpolator) Process(input []float64) []float64 {
+	return in.ProcessInto(nil, input)
+}
+
+// ProcessInto feeds the inputs and appends the outputs to dst like append. See Decimator.ProcessInto.
+func (in *Interpolator) ProcessInto(dst, input []float64) []float64 {
+	start := len(dst)
+	size := start + len(input)*in.factor
+
+	if cap(dst) < size {
+		grown := make([]float64, start, size)
+		copy(grown, dst)
+		dst = grown
+	}
+
+	dst = dst[:size]
+	output := dst[start:]
+
+	for i, x := range input {
+		in.push(x, output[i*in.factor:(i+1)*in.factor])
+	}
+
+	return dst
+}
+
+// push feeds one input and writes the factor outputs.
+func (in *Interpolator) push(x float64, output []float64) {
+	n := len(in.history)
+
+	in.position = (in.position + n - 1) % n
+	in.history[in.position] = x
+
+	// history[position+k] is the input k samples ago.
+	for p, phase := range in.phases {
+		sum := 0.0
+
+		for k, c := range phase {
+			sum += c * in.history[(in.position+k)%n]
+		}
+
+		output[p] = sum
+	}
+}
+
+// Reset clears the delay line.
+func (in *Interpolator) Reset() {
+	for i := range in.history {
+		in.history[i] = 0.0
+	}
+
+	in.position = 0
+}
@@ -0,0 +1,69 @@
+package equalizer
+
+import "math"
+
+// NormalizePeak scales the samples in place so the largest absolute sample becomes the target level and returns the gain in dB.
+//
+// Parameters:
+//
+//     - samples ... Audio signal. Pass the interleaved samples to keep the balance between the channels.
+//     - targetDB ... Target peak level in dBFS. e.g. -1.0
+//
+// NOTE: The silence is left as it is and the gain 0 is returned. The peak between the samples may exceed the target, measure it with TruePeak before encoding.
+func NormalizePeak(samples []float64, targetDB float64) float64 {
+	return normalize(samples, PeakLevel(samples), targetDB)
+}
+
+// NormalizeRMS scales the samples in place so the RMS level becomes the target level and returns the gain in dB.
+//
+// Parameters:
+//
+//     - samples ... Audio signal. Pass the interleaved samples to keep the balance between the channels.
+//     - targetDB ... Target RMS level in dBFS. e.g. -20.0
+//
+// NOTE: The silence is left as it is and the gain 0 is returned. The peak may exceed 0 dBFS after the normalization, so check PeakLevel or normalize the peak afterward.
+func NormalizeRMS(samples []float64, targetDB float64) float64 {
+	return normalize(samples, RMSLevel(samples), targetDB)
+}
+
+// PeakLevel returns the largest absolute sample in dBFS. It returns -Inf for the silence.
+func PeakLevel(samples []float64) float64 {
+	peak := 0.0
+
+	for _, sample := range samples {
+		peak = math.Max(peak, math.Abs(sample))
+	}
+
+	return 20.0 * math.Log10(peak)
+}
+
+// RMSLevel returns the root mean square of the samples in dBFS. The full scale sine wave is -3 dBFS. It returns -Inf for the silence.
+func RMSLevel(samples []float64) float64 {
+	if len(samples) == 0 {
+		return math.Inf(-1)
+	}
+
+	sum := 0.0
+
+	for _, sample := range samples {
+		sum += sample * sample
+	}
+
+	return 10.0 * math.Log10(sum/float64(len(samples)))
+}
+
+// normalize applies the gain which moves the level to the target and returns it in dB.
+func normalize(samples []float64, levelDB, targetDB float64) float64 {
+	if math.IsInf(levelDB, -1) || math.IsNaN(levelDB) {
+		return 0.0
+	}
+
+	gainDB := targetDB - levelDB
+	gain := math.Pow(10.0, gainDB/20.0)
+
+	for i := range samples {
+		samples[i] *= gain
+	}
+
+	return gainDB
+}
@@ -0,0 +1,90 @@
+package equalizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrFrequency is returned when the frequency cannot be designed at the sample rate.
+var ErrFrequency = errors.New("equalizer: invalid frequency")
+
+// CheckFrequency returns ErrFrequency when the frequency is not between 0 and the Nyquist frequency. The constructors do not
+// return the error but design the frequency not below the Nyquist frequency at 0.49 of the sample rate, so check the user input with it.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0 for the audio, 1.0/60.0 for the data sampled every minute.
+//     - frequency ... Cut off or center frequency in Hz.
+func CheckFrequency(sampleRate, frequency float64) error {
+	if !(sampleRate > 0.0) || math.IsInf(sampleRate, 0) {
+		return fmt.Errorf("%w: sample rate %g Hz must be positive", ErrFrequency, sampleRate)
+	}
+	if !(frequency > 0.0) || frequency >= sampleRate/2.0 {
+		return fmt.Errorf("%w: %g Hz is not between 0 and the Nyquist frequency of %g Hz", ErrFrequency, frequency, sampleRate/2.0)
+	}
+
+	return nil
+}
+
+// NyquistPolicy decides what New does with the frequency which is not below the Nyquist frequency.
+type NyquistPolicy int
+
+const (
+	// NyquistError returns ErrFrequency. It is the default.
+	NyquistError NyquistPolicy = iota
+
+	// NyquistClamp designs the filter above 0.49 of the sample rate at 0.49 of it instead, and passes the warning to the
+	// handler of WithWarning.
+	NyquistClamp
+
+	// NyquistMatched designs the filter which matches the magnitude of the analog prototype at any frequency. The response
+	// is not cramped toward the Nyquist frequency as the bilinear transform, e.g. the high-shelf at 16 kHz at 44.1 kHz,
+	// and the frequency above the Nyquist frequency shapes the top of the band as the analog filter would do.
+	NyquistMatched
+)
+
+// clampRatio is the frequency relative to the sample rate to which NyquistClamp clamps.
+const clampRatio = 0.49
+
+// nyquistLimit returns the frequency with which the constructors design the filter. The frequency not below the Nyquist
+// frequency is designed at clampRatio of the sample rate as NyquistClamp does, and the valid frequency is kept as it is.
+func nyquistLimit(sampleRate, frequency float64) float64 {
+	if sampleRate > 0.0 && frequency >= sampleRate/2.0 {
+		return clampRatio * sampleRate
+	}
+
+	return frequency
+}
+
+// apply returns the frequency with which the filter is designed under the policy.
+func (policy NyquistPolicy) apply(sampleRate, frequency float64, warn func(err error)) (float64, error) {
+	err := CheckFrequency(sampleRate, frequency)
+
+	// Only the finite frequency of the valid sample rate is handled by the policy.
+	if CheckFrequency(sampleRate, sampleRate/4.0) != nil || math.IsInf(frequency, 0) {
+		return frequency, err
+	}
+
+	switch policy {
+	case NyquistClamp:
+		// The frequency between the clamped one and the Nyquist frequency is clamped too, so the designed frequency never
+		// goes down as the frequency goes up.
+		limit := clampRatio * sampleRate
+
+		if !(frequency > limit) {
+			return frequency, err
+		}
+		if warn != nil {
+			warn(fmt.Errorf("%w: %g Hz is clamped to %g Hz", ErrFrequency, frequency, limit))
+		}
+
+		return limit, nil
+	case NyquistMatched:
+		if frequency >= sampleRate/2.0 {
+			return frequency, nil
+		}
+	}
+
+	return frequency, err
+}
@@ -0,0 +1,90 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestNyquistClampMonotonic(t *testing.T) {
+	sampleRate := 44100.0
+	previous := 0.0
+
+	for frequency := 20000.0; frequency < 30000.0; frequency += 10.0 {
+		f, err := New(Peaking, sampleRate, WithFrequency(frequency), WithGainDB(6), WithNyquistPolicy(NyquistClamp))
+
+		if err != nil {
+			t.Fatalf("%g Hz: %v", frequency, err)
+		}
+		if f.frequency < previous {
+			t.Fatalf("%g Hz is designed at %g Hz, below %g Hz of the lower frequency", frequency, f.frequency, previous)
+		}
+		if f.frequency > clampRatio*sampleRate {
+			t.Fatalf("%g Hz is designed at %g Hz, above the clamped frequency", frequency, f.frequency)
+		}
+
+		previous = f.frequency
+	}
+}
+
+func TestNyquistMatchedStable(t *testing.T) {
+	sampleRate := 44100.0
+
+	for _, name := range []FilterName{Peaking, LowShelf, HighShelf} {
+		for _, frequency := range []float64{1000.0, 16000.0, 21000.0, 21500.0, 22000.0, 22049.0, 22050.0, 30000.0, 60000.0} {
+			for _, q := range []float64{0.707, 2.0, 4.0} {
+				for _, gain := range []float64{-12.0, -6.0, -1.0, 0.0, 1.0, 6.0, 12.0} {
+					f, err := New(name, sampleRate, WithFrequency(frequency), WithQ(q), WithGainDB(gain), WithNyquistPolicy(NyquistMatched))
+
+					if err != nil {
+						t.Fatalf("%s at %g Hz, Q %g and %g dB: %v", filterNameString(name), frequency, q, gain, err)
+					}
+					if !f.Stable() {
+						t.Errorf("%s at %g Hz, Q %g and %g dB is unstable, poles %v", filterNameString(name), frequency, q, gain, f.Poles())
+					}
+					if gain != 0.0 {
+						continue
+					}
+					for _, w := range []float64{0.0, 1000.0, 10000.0, 22000.0} {
+						if h := f.FrequencyResponse(w); cmplx.Abs(h-1.0) > 1e-12 {
+							t.Errorf("%s at %g Hz, Q %g and 0 dB is %v at %g Hz, want 1", filterNameString(name), frequency, q, h, w)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConstructorNyquistLimit(t *testing.T) {
+	sampleRate := 44100.0
+	constructors := map[string]func(frequency float64) *Filter{
+		"low-pass":    func(frequency float64) *Filter { return NewLowPass(sampleRate, frequency, 0.707) },
+		"high-pass":   func(frequency float64) *Filter { return NewHighPass(sampleRate, frequency, 0.707) },
+		"all-pass":    func(frequency float64) *Filter { return NewAllPass(sampleRate, frequency, 0.707) },
+		"band-pass":   func(frequency float64) *Filter { return NewBandPass(sampleRate, frequency, 1.0) },
+		"band-reject": func(frequency float64) *Filter { return NewBandReject(sampleRate, frequency, 1.0) },
+		"low-shelf":   func(frequency float64) *Filter { return NewLowShelf(sampleRate, frequency, 0.707, 6.0) },
+		"high-shelf":  func(frequency float64) *Filter { return NewHighShelf(sampleRate, frequency, 0.707, 6.0) },
+		"peaking":     func(frequency float64) *Filter { return NewPeaking(sampleRate, frequency, 1.0, 6.0) },
+	}
+
+	for name, constructor := range constructors {
+		limit := constructor(clampRatio * sampleRate)
+
+		// The valid frequency between the limit and the Nyquist frequency is kept.
+		if f := constructor(22000.0); f.frequency != 22000.0 {
+			t.Errorf("%s at 22000 Hz is designed at %g Hz", name, f.frequency)
+		}
+		for _, frequency := range []float64{22050.0, 30000.0, math.Inf(1)} {
+			f := constructor(frequency)
+
+			if f.frequency != limit.frequency || f.b0 != limit.b0 || f.a1 != limit.a1 || f.a2 != limit.a2 {
+				t.Errorf("%s at %g Hz is designed at %g Hz, want %g Hz", name, frequency, f.frequency, limit.frequency)
+			}
+			if !f.Stable() {
+				t.Errorf("%s at %g Hz is unstable, poles %v", name, frequency, f.Poles())
+			}
+		}
+	}
+}
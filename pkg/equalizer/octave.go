@@ -0,0 +1,163 @@
+package equalizer
+
+import "math"
+
+// octaveRatio is the base-10 octave frequency ratio defined in IEC 61260-1.
+var octaveRatio = math.Pow(10.0, 3.0/10.0)
+
+// minOctaveFrequency is the lowest mid-band frequency of OctaveBands in Hz when the minimum frequency is not positive,
+// which is below the lowest band of the audio analyzers, e.g. 16 Hz.
+const minOctaveFrequency = 1.0
+
+// octaveFilterOrder is the order of the Butterworth low-pass prototype used for each band.
+const octaveFilterOrder = 3
+
+// OctaveBand holds the exact mid-band frequency and the band edge frequencies in Hz.
+type OctaveBand struct {
+	Center float64
+	Lower  float64
+	Upper  float64
+}
+
+// OctaveBands returns the fractional-octave bands defined in IEC 61260-1 whose mid-band frequencies are between minFrequency and maxFrequency.
+//
+// Parameters:
+//
+//     - fraction ... Bandwidth designator. 1 for octave bands, 3 for one-third-octave bands.
+//     - minFrequency ... Lowest mid-band frequency in Hz.
+//     - maxFrequency ... Highest mid-band frequency in Hz.
+//
+// NOTE: minFrequency of 0 or less is raised to minOctaveFrequency, because there are infinitely many bands above 0 Hz.
+// It returns nil when maxFrequency is not finite.
+func OctaveBands(fraction int, minFrequency, maxFrequency float64) []OctaveBand {
+	if fraction < 1 || math.IsInf(maxFrequency, 0) || math.IsNaN(maxFrequency) {
+		return nil
+	}
+	if !(minFrequency > 0.0) {
+		minFrequency = minOctaveFrequency
+	}
+
+	b := float64(fraction)
+	bands := []OctaveBand{}
+
+	// The mid-band frequencies are G^(x/b) or G^((2x+1)/(2b)) times the reference frequency 1000 Hz.
+	index := func(x int) float64 {
+		if fraction%2 == 1 {
+			return float64(x) / b
+		}
+
+		return float64(2*x+1) / (2.0 * b)
+	}
+
+	x := int(math.Floor(math.Log(minFrequency/1000.0)/math.Log(octaveRatio)*b)) - 1
+
+	for ; ; x++ {
+		center := 1000.0 * math.Pow(octaveRatio, index(x))
+
+		if center > maxFrequency*(1.0+1e-9) {
+			break
+		}
+		if center < minFrequency*(1.0-1e-9) {
+			continue
+		}
+
+		bands = append(bands, OctaveBand{
+			Center: center,
+			Lower:  center * math.Pow(octaveRatio, -1.0/(2.0*b)),
+			Upper:  center * math.Pow(octaveRatio, 1.0/(2.0*b)),
+		})
+	}
+
+	return bands
+}
+
+// OctaveBank is the fractional-octave band filter bank with the level detector for each band.
+type OctaveBank struct {
+	bands     []OctaveBand
+	filters   [][]*Filter
+	detectors []*EnvelopeFollower
+	outputs   []float64
+}
+
+// NewOctaveBank returns the fractional-octave band filter bank. Each band is the 6th order Butterworth band-pass filter.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - fraction ... Bandwidth designator. 1 for octave bands, 3 for one-third-octave bands.
+//     - minFrequency ... Lowest mid-band frequency in Hz. e.g. 31.5
+//     - maxFrequency ... Highest mid-band frequency in Hz. e.g. 16000.0
+//     - timeConstant ... Time constant of the level detector in seconds. e.g. 0.125 (fast) or 1.0 (slow)
+//
+// NOTE: The bands whose upper edge frequency is not less than the Nyquist frequency are omitted.
+func NewOctaveBank(sampleRate float64, fraction int, minFrequency, maxFrequency, timeConstant float64) *OctaveBank {
+	b := &OctaveBank{}
+
+	for _, band := range OctaveBands(fraction, minFrequency, maxFrequency) {
+		if band.Upper >= sampleRate/2.0 {
+			break
+		}
+
+		b.bands = append(b.bands, band)
+		b.filters = append(b.filters, butterworthBandPass(sampleRate, band.Lower, band.Upper, octaveFilterOrder))
+		b.detectors = append(b.detectors, NewEnvelopeFollower(sampleRate, 0.0, timeConstant, RMSEnvelope))
+	}
+
+	b.outputs = make([]float64, len(b.bands))
+
+	return b
+}
+
+// Bands returns the bands of the filter bank.
+func (b *OctaveBank) Bands() []OctaveBand {
+	return b.bands
+}
+
+// Process feeds the input to every band.
+func (b *OctaveBank) Process(input float64) {
+	for i, filters := range b.filters {
+		output := input
+
+		for _, filter := range filters {
+			output = filter.Apply(output)
+		}
+
+		b.outputs[i] = output
+		b.detectors[i].Apply(output)
+	}
+}
+
+// ProcessBuffer feeds the buffer to every band. The buffer is not modified.
+func (b *OctaveBank) ProcessBuffer(buffer []float64) {
+	for _, input := range buffer {
+		b.Process(input)
+	}
+}
+
+// Outputs returns the latest output of each band.
+func (b *OctaveBank) Outputs() []float64 {
+	return b.outputs
+}
+
+// Levels returns the RMS level of each band in dB relative to 1.0.
+func (b *OctaveBank) Levels() []float64 {
+	levels := make([]float64, len(b.detectors))
+
+	for i, detector := range b.detectors {
+		levels[i] = 20.0 * math.Log10(detector.Value())
+	}
+
+	return levels
+}
+
+// Reset clears the state of the filters and the level detectors.
+func (b *OctaveBank) Reset() {
+	for i, filters := range b.filters {
+		for _, filter := range filters {
+			filter.Reset()
+		}
+
+		b.outputs[i] = 0.0
+		b.detectors[i].Reset()
+	}
+}
@@ -0,0 +1,34 @@
+package equalizer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOctaveBands(t *testing.T) {
+	bands := OctaveBands(3, 19.0, 20000.0)
+
+	if len(bands) != 31 {
+		t.Fatalf("%d one-third-octave bands between 19 Hz and 20 kHz, want 31", len(bands))
+	}
+	if math.Abs(bands[17].Center-1000.0) > 1e-9 {
+		t.Errorf("band 17 is %v Hz, want 1000 Hz", bands[17].Center)
+	}
+	for i := 1; i < len(bands); i++ {
+		if math.Abs(bands[i].Lower-bands[i-1].Upper) > 1e-9*bands[i].Lower {
+			t.Errorf("band %d does not start at the upper edge of the previous band", i)
+		}
+	}
+
+	// The minimum frequency of 0 starts from the lowest band instead of returning no band.
+	for _, minFrequency := range []float64{0.0, -1.0, math.NaN()} {
+		bands := OctaveBands(3, minFrequency, 20000.0)
+
+		if len(bands) == 0 || bands[0].Center < minOctaveFrequency*(1.0-1e-9) {
+			t.Errorf("minimum frequency %v: %d bands", minFrequency, len(bands))
+		}
+	}
+	if bands := OctaveBands(3, 20.0, math.Inf(1)); bands != nil {
+		t.Errorf("%d bands up to the infinite frequency", len(bands))
+	}
+}
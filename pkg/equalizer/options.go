@@ -0,0 +1,144 @@
+package equalizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidOption is returned by New when the options do not describe the filter.
+var ErrInvalidOption = errors.New("equalizer: invalid option")
+
+// options are the parameters of the filter given to New.
+type options struct {
+	frequency float64
+	q         float64
+	gain      float64
+	precise   bool
+	nyquist   NyquistPolicy
+	warn      func(err error)
+}
+
+// Option sets the parameter of the filter created by New.
+type Option func(o *options) error
+
+// WithFrequency sets the cut off or center frequency in Hz. It is required.
+func WithFrequency(frequency float64) Option {
+	return func(o *options) error {
+		o.frequency = frequency
+
+		return nil
+	}
+}
+
+// WithQ sets the Q value, or the band width for the band-pass, band-reject and peaking filters. The default is 1/sqrt(2).
+func WithQ(q float64) Option {
+	return func(o *options) error {
+		if !(q > 0.0) {
+			return fmt.Errorf("%w: q %g must be positive", ErrInvalidOption, q)
+		}
+
+		o.q = q
+
+		return nil
+	}
+}
+
+// WithGainDB sets the gain in dB of the low-shelf, high-shelf and peaking filters. The default is 0.
+func WithGainDB(gain float64) Option {
+	return func(o *options) error {
+		if math.IsNaN(gain) || math.IsInf(gain, 0) {
+			return fmt.Errorf("%w: gain %g must be finite", ErrInvalidOption, gain)
+		}
+
+		o.gain = gain
+
+		return nil
+	}
+}
+
+// WithHighPrecision designs the coefficients of the built-in filters with the extended precision and rounds each of them
+// to float64 only once, instead of the float64 arithmetic whose rounding errors move the poles near z = 1. Use it for the
+// very low frequency relative to the sample rate, e.g. 0.005 Hz for the sensor sampled every minute or 5 Hz at 192 kHz.
+// The filter is designed with the extended precision again by SetFrequency, SetQ and SetGain.
+//
+// NOTE: The design takes tens of microseconds, so do not use it for the filter redesigned per sample. The registered
+// filters are designed as usual.
+func WithHighPrecision() Option {
+	return func(o *options) error {
+		o.precise = true
+
+		return nil
+	}
+}
+
+// WithNyquistPolicy sets what New does with the frequency which is not below the Nyquist frequency. The default is NyquistError.
+// NyquistMatched also changes the design of the filter below the Nyquist frequency, and SetFrequency, SetQ and SetGain
+// keep the matched design.
+func WithNyquistPolicy(policy NyquistPolicy) Option {
+	return func(o *options) error {
+		if policy < NyquistError || policy > NyquistMatched {
+			return fmt.Errorf("%w: unknown Nyquist policy %d", ErrInvalidOption, policy)
+		}
+
+		o.nyquist = policy
+
+		return nil
+	}
+}
+
+// WithWarning sets the handler which receives the problem New worked around, e.g. the frequency clamped by NyquistClamp.
+// The error wraps ErrFrequency. The default ignores the warnings.
+func WithWarning(handler func(err error)) Option {
+	return func(o *options) error {
+		o.warn = handler
+
+		return nil
+	}
+}
+
+// New returns the filter of the name designed with the options, so the meaning of each parameter is explicit at the call site.
+// The filters registered by Register are also accepted.
+//
+// Parameters:
+//
+//     - name ... Filter name, e.g. Peaking.
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - opts ... Parameters. e.g. WithFrequency(1000), WithQ(0.707), WithGainDB(-3)
+//
+// NOTE: Unlike the constructors of each filter, it validates the frequency with CheckFrequency. See WithNyquistPolicy
+// for the frequency above the Nyquist frequency.
+func New(name FilterName, sampleRate float64, opts ...Option) (*Filter, error) {
+	o := options{
+		q: 1.0 / math.Sqrt2,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	frequency, err := o.nyquist.apply(sampleRate, o.frequency, o.warn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	method := cookbookDesign
+
+	if o.precise {
+		method = preciseDesign
+	}
+	if o.nyquist == NyquistMatched {
+		method = matchedDesign
+	}
+
+	f := designWith(method, name, sampleRate, frequency, o.q, o.gain)
+
+	if f == nil {
+		return nil, fmt.Errorf("%w: filter name %d cannot be designed from the parameters", ErrInvalidOption, name)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,78 @@
+package equalizer
+
+// Oversampler runs the processor at the sample rate multiplied by the factor, so the harmonics generated by the nonlinear
+// processor, e.g. the saturator, are removed by the anti-aliasing filter instead of folding back below the Nyquist frequency.
+type Oversampler struct {
+	processor    Processor
+	interpolator *Interpolator
+	decimator    *Decimator
+	buffer       []float64
+}
+
+// NewOversampler returns the oversampler.
+//
+// Parameters:
+//
+//     - factor ... Oversampling factor. e.g. 4
+//     - processor ... Processor which runs at the oversampled rate.
+//
+// NOTE: The processor must be designed at the oversampled rate, e.g. NewPeaking(4*44100.0, ...) for the factor 4 at 44.1 kHz.
+// The output is delayed by Latency samples.
+func NewOversampler(factor int, processor Processor) *Oversampler {
+	interpolator := NewInterpolator(factor, 0)
+
+	return &Oversampler{
+		processor:    processor,
+		interpolator: interpolator,
+		decimator:    NewDecimator(interpolator.Factor(), 0),
+		buffer:       make([]float64, interpolator.Factor()),
+	}
+}
+
+// Processor returns the processor which runs at the oversampled rate.
+func (o *Oversampler) Processor() Processor {
+	return o.processor
+}
+
+// Factor returns the oversampling factor.
+func (o *Oversampler) Factor() int {
+	return o.interpolator.Factor()
+}
+
+// Latency returns the delay in samples at the original rate added by the interpolation and the decimation filters.
+// The latency of the processor itself is not included.
+func (o *Oversampler) Latency() int {
+	return (o.interpolator.Latency() + o.decimator.Latency()) / o.Factor()
+}
+
+// Apply upsamples the input, applies the processor and returns the downsampled value.
+func (o *Oversampler) Apply(input float64) float64 {
+	o.interpolator.push(input, o.buffer)
+
+	output := 0.0
+
+	for _, x := range o.buffer {
+		if y, ok := o.decimator.push(o.processor.Apply(x)); ok {
+			output = y
+		}
+	}
+
+	return output
+}
+
+// ProcessBuffer applies the oversampler to the buffer in place.
+func (o *Oversampler) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = o.Apply(buffer[i])
+	}
+}
+
+// Reset clears the filters and the state of the processor.
+func (o *Oversampler) Reset() {
+	o.interpolator.Reset()
+	o.decimator.Reset()
+
+	if r, ok := o.processor.(resetter); ok {
+		r.Reset()
+	}
+}
@@ -0,0 +1,183 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Band describes one band of the parametric equalizer.
+type Band struct {
+	Name      FilterName
+	Frequency float64
+
+	// Q is the Q value, or the band width for the band-pass, band-reject and peaking filters.
+	Q float64
+
+	// Gain is the gain in dB.
+	Gain float64
+}
+
+// ParametricEQ is the parametric equalizer which applies the bands in series.
+type ParametricEQ struct {
+	sampleRate float64
+	bands      []Band
+	filters    []*Filter
+	hooks      Hooks
+
+	// monitors are of the soloed bands, and solo is the amount of the monitors in the output.
+	monitors []monitor
+	solo     ramp
+}
+
+// NewParametricEQ returns the parametric equalizer.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - bands ... Bands applied in the given order.
+func NewParametricEQ(sampleRate float64, bands ...Band) *ParametricEQ {
+	e := &ParametricEQ{
+		sampleRate: sampleRate,
+	}
+
+	for _, band := range bands {
+		e.AddBand(band)
+	}
+
+	return e
+}
+
+// SampleRate returns the sample rate in Hz.
+func (e *ParametricEQ) SampleRate() float64 {
+	return e.sampleRate
+}
+
+// Bands returns the bands.
+func (e *ParametricEQ) Bands() []Band {
+	return e.bands
+}
+
+// Filters returns the filter of each band.
+func (e *ParametricEQ) Filters() []*Filter {
+	return e.filters
+}
+
+// AddBand appends the band.
+func (e *ParametricEQ) AddBand(band Band) {
+	e.bands = append(e.bands, band)
+	e.filters = append(e.filters, newBandFilter(e.sampleRate, band))
+	e.monitors = append(e.monitors, monitor{})
+}
+
+// SetBand replaces the i-th band. The state variables of the band are preserved when the filter name is not changed.
+func (e *ParametricEQ) SetBand(i int, band Band) {
+	old := e.bands[i]
+
+	if old.Name == band.Name {
+		e.filters[i].setCoefficients(design(band.Name, e.sampleRate, band.Frequency, band.Q, band.Gain))
+	} else {
+		muted := e.filters[i].bypass
+
+		e.filters[i] = newBandFilter(e.sampleRate, band)
+		e.filters[i].bypass = muted
+	}
+	if m := e.monitors[i].filter; m != nil {
+		m.setCoefficients(newMonitorFilter(e.sampleRate, band))
+	}
+
+	e.bands[i] = band
+
+	if e.hooks != nil && old != band {
+		e.hooks.OnParamChange(ParamChange{Processor: e, Band: i, Old: old, New: band})
+	}
+}
+
+// SetHooks sets the hooks which are called when the band is changed by SetBand. nil removes the hooks.
+func (e *ParametricEQ) SetHooks(hooks Hooks) {
+	e.hooks = hooks
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (e *ParametricEQ) FrequencyResponse(frequency float64) complex128 {
+	response := complex(1.0, 0.0)
+
+	for _, filter := range e.filters {
+		response *= filter.FrequencyResponse(frequency)
+	}
+
+	return response
+}
+
+// GroupDelay returns the group delay in seconds at the frequency in Hz.
+func (e *ParametricEQ) GroupDelay(frequency float64) float64 {
+	delay := 0.0
+
+	for _, filter := range e.filters {
+		delay += filter.GroupDelay(frequency)
+	}
+
+	return delay
+}
+
+// Response returns the magnitude response at the frequencies.
+func (e *ParametricEQ) Response(frequencies []float64) Response {
+	response := make(Response, len(frequencies))
+
+	for i, frequency := range frequencies {
+		response[i] = ResponsePoint{
+			Frequency: frequency,
+			Gain:      20.0 * math.Log10(cmplx.Abs(e.FrequencyResponse(frequency))),
+		}
+	}
+
+	return response
+}
+
+// Apply applies the bands in series and returns the value.
+func (e *ParametricEQ) Apply(input float64) float64 {
+	output := input
+
+	for _, filter := range e.filters {
+		output = filter.Apply(output)
+	}
+	if e.solo.active() {
+		return e.applySolo(input, output)
+	}
+
+	return output
+}
+
+// ProcessBuffer applies the bands to the buffer in place.
+func (e *ParametricEQ) ProcessBuffer(buffer []float64) {
+	if e.solo.active() {
+		for i := range buffer {
+			buffer[i] = e.Apply(buffer[i])
+		}
+
+		return
+	}
+	for _, filter := range e.filters {
+		filter.ProcessBuffer(buffer)
+	}
+}
+
+// Reset clears the state variables.
+func (e *ParametricEQ) Reset() {
+	for _, filter := range e.filters {
+		filter.Reset()
+	}
+	for _, m := range e.monitors {
+		if m.filter != nil {
+			m.filter.Reset()
+		}
+	}
+}
+
+// newBandFilter returns the filter for the band. The unknown filter name results in the filter which passes the signal through.
+func newBandFilter(sampleRate float64, band Band) *Filter {
+	if f := design(band.Name, sampleRate, band.Frequency, band.Q, band.Gain); f != nil {
+		return f
+	}
+
+	return NewCustom(sampleRate, 1.0, 0.0, 0.0, 1.0, 0.0, 0.0)
+}
@@ -0,0 +1,63 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// PhaseDegrees returns the phase in degrees between -180 and 180 at the frequency in Hz.
+func (f *Filter) PhaseDegrees(frequency float64) float64 {
+	return phaseDegrees(f, frequency)
+}
+
+// PhaseCurve returns the phase in degrees at each frequency in Hz. See UnwrapPhase for the unwrapping.
+func (f *Filter) PhaseCurve(frequencies []float64, unwrap bool) []float64 {
+	return phaseCurve(f, frequencies, unwrap)
+}
+
+// PhaseDegrees returns the phase of the chain in degrees between -180 and 180 at the frequency in Hz.
+//
+// NOTE: The processors which do not provide FrequencyResponse are treated as no phase shift.
+func (c *Chain) PhaseDegrees(frequency float64) float64 {
+	return phaseDegrees(c, frequency)
+}
+
+// PhaseCurve returns the phase of the chain in degrees at each frequency in Hz. See UnwrapPhase for the unwrapping.
+func (c *Chain) PhaseCurve(frequencies []float64, unwrap bool) []float64 {
+	return phaseCurve(c, frequencies, unwrap)
+}
+
+// UnwrapPhase removes the jumps of 360 degrees between the neighboring values in place and returns the phases.
+//
+// NOTE: The frequencies of the phases must be ascending and dense enough that the true phase changes less than 180 degrees between the neighbors.
+func UnwrapPhase(phases []float64) []float64 {
+	offset := 0.0
+
+	for i := 1; i < len(phases); i++ {
+		// phases[i-1] is already unwrapped, so compare with the original value.
+		previous := phases[i-1] - offset
+		jump := phases[i] - previous
+
+		offset -= 360.0 * math.Round(jump/360.0)
+		phases[i] += offset
+	}
+
+	return phases
+}
+
+func phaseDegrees(r responder, frequency float64) float64 {
+	return cmplx.Phase(r.FrequencyResponse(frequency)) * 180.0 / math.Pi
+}
+
+func phaseCurve(r responder, frequencies []float64, unwrap bool) []float64 {
+	phases := make([]float64, len(frequencies))
+
+	for i, frequency := range frequencies {
+		phases[i] = phaseDegrees(r, frequency)
+	}
+	if unwrap {
+		UnwrapPhase(phases)
+	}
+
+	return phases
+}
@@ -0,0 +1,217 @@
+package equalizer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNotBiquad is returned when the processor cannot be expressed with the biquad filters, e.g. the FIR filter or the compressor.
+var ErrNotBiquad = errors.New("equalizer: processor is not made of biquad filters")
+
+// PipeWireOptions is the options of the PipeWire filter-chain configuration.
+type PipeWireOptions struct {
+	// Name is the node name of the virtual sink. The default is "equalizer".
+	Name string
+
+	// Description is shown in the sound settings of the desktop. The default is "Equalizer Sink".
+	Description string
+
+	// Channels is the number of the channels of the virtual sink. The same filters are applied to every channel. The default is 2.
+	Channels int
+
+	// Rates are the sample rates in Hz for which the filters are designed again, so the response is kept when PipeWire runs
+	// at the other rate, e.g. 44100 and 48000. The custom filters are exported only at their own sample rates.
+	Rates []float64
+}
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o PipeWireOptions) withDefaults() PipeWireOptions {
+	if o.Name == "" {
+		o.Name = "equalizer"
+	}
+	if o.Description == "" {
+		o.Description = "Equalizer Sink"
+	}
+	if o.Channels <= 0 {
+		o.Channels = 2
+	}
+
+	return o
+}
+
+// WritePipeWireConfig writes the configuration of libpipewire-module-filter-chain which creates the virtual sink applying the processor.
+// Each biquad filter becomes the builtin bq_raw node with the coefficients designed by this package, so the response is the same as the processor.
+//
+// Parameters:
+//
+//     - w ... Destination of the configuration. e.g. the file in ~/.config/pipewire/pipewire.conf.d/
+//     - processor ... *Filter, *ParametricEQ or *Chain of them.
+//     - options ... Name, channels and sample rates of the virtual sink.
+//
+// NOTE: bq_raw needs PipeWire 0.3.80 or later. PipeWire uses the coefficients of the closest sample rate.
+func WritePipeWireConfig(w io.Writer, processor Processor, options PipeWireOptions) error {
+	filters, err := biquads(processor)
+
+	if err != nil {
+		return err
+	}
+
+	options = options.withDefaults()
+
+	// The filter-chain needs at least one node, so the flat response is the identity filter.
+	if len(filters) == 0 {
+		filters = []*Filter{NewCustom(48000.0, 1.0, 0.0, 0.0, 1.0, 0.0, 0.0)}
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "# Generated by go-equalizer.\n")
+	fmt.Fprintf(b, "context.modules = [\n")
+	fmt.Fprintf(b, "    { name = libpipewire-module-filter-chain\n")
+	fmt.Fprintf(b, "        args = {\n")
+	fmt.Fprintf(b, "            node.description = %s\n", strconv.Quote(options.Description))
+	fmt.Fprintf(b, "            media.name = %s\n", strconv.Quote(options.Description))
+	fmt.Fprintf(b, "            filter.graph = {\n")
+	fmt.Fprintf(b, "                nodes = [\n")
+
+	for i, filter := range filters {
+		fmt.Fprintf(b, "                    {\n")
+		fmt.Fprintf(b, "                        type = builtin\n")
+		fmt.Fprintf(b, "                        name = band_%d\n", i+1)
+		fmt.Fprintf(b, "                        label = bq_raw\n")
+		fmt.Fprintf(b, "                        config = {\n")
+		fmt.Fprintf(b, "                            coefficients = [\n")
+
+		for _, f := range redesign(filter, options.Rates) {
+			fmt.Fprintf(b, "                                { rate = %d, b0 = %s, b1 = %s, b2 = %s, a0 = 1.0, a1 = %s, a2 = %s }\n",
+				int(f.sampleRate), formatCoefficient(f.b0), formatCoefficient(f.b1), formatCoefficient(f.b2),
+				formatCoefficient(f.a1), formatCoefficient(f.a2))
+		}
+
+		fmt.Fprintf(b, "                            ]\n")
+		fmt.Fprintf(b, "                        }\n")
+		fmt.Fprintf(b, "                    }\n")
+	}
+
+	fmt.Fprintf(b, "                ]\n")
+	fmt.Fprintf(b, "                links = [\n")
+
+	for i := 1; i < len(filters); i++ {
+		fmt.Fprintf(b, "                    { output = \"band_%d:Out\" input = \"band_%d:In\" }\n", i, i+1)
+	}
+
+	fmt.Fprintf(b, "                ]\n")
+	fmt.Fprintf(b, "            }\n")
+	fmt.Fprintf(b, "            audio.channels = %d\n", options.Channels)
+	fmt.Fprintf(b, "            audio.position = [ %s ]\n", strings.Join(channelPositions(options.Channels), " "))
+	fmt.Fprintf(b, "            capture.props = {\n")
+	fmt.Fprintf(b, "                node.name = %s\n", strconv.Quote("effect_input."+options.Name))
+	fmt.Fprintf(b, "                media.class = Audio/Sink\n")
+	fmt.Fprintf(b, "            }\n")
+	fmt.Fprintf(b, "            playback.props = {\n")
+	fmt.Fprintf(b, "                node.name = %s\n", strconv.Quote("effect_output."+options.Name))
+	fmt.Fprintf(b, "                node.passive = true\n")
+	fmt.Fprintf(b, "            }\n")
+	fmt.Fprintf(b, "        }\n")
+	fmt.Fprintf(b, "    }\n")
+	fmt.Fprintf(b, "]\n")
+
+	return b.Flush()
+}
+
+// WritePipeWireConfigFile is the same as WritePipeWireConfig but writes the configuration to the file.
+func WritePipeWireConfigFile(path string, processor Processor, options PipeWireOptions) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+	if err := WritePipeWireConfig(file, processor, options); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// biquads returns the biquad filters of the processor in the order they are applied.
+func biquads(processor Processor) ([]*Filter, error) {
+	switch p := processor.(type) {
+	case *Filter:
+		return []*Filter{p}, nil
+	case *ParametricEQ:
+		return p.Filters(), nil
+	case *Chain:
+		var filters []*Filter
+
+		for _, processor := range p.Processors() {
+			f, err := biquads(processor)
+
+			if err != nil {
+				return nil, err
+			}
+
+			filters = append(filters, f...)
+		}
+
+		return filters, nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrNotBiquad, processor)
+}
+
+// redesign returns the filter designed for its own sample rate and each of the rates. The rates at which the filter cannot be designed are skipped.
+func redesign(filter *Filter, rates []float64) []*Filter {
+	filters := []*Filter{filter}
+
+	for _, rate := range rates {
+		if rate == filter.sampleRate || CheckFrequency(rate, filter.frequency) != nil {
+			continue
+		}
+		if f := designWith(filter.method, filter.name, rate, filter.frequency, filter.q, filter.gain); f != nil {
+			filters = append(filters, f)
+		}
+	}
+
+	return filters
+}
+
+// formatCoefficient formats the coefficient with the shortest representation which is parsed back to the same value.
+func formatCoefficient(value float64) string {
+	s := strconv.FormatFloat(value, 'g', -1, 64)
+
+	// The SPA JSON parser reads the number without the decimal point as the integer.
+	if !strings.ContainsAny(s, ".eEn") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// channelPositions returns the SPA channel positions of the common layouts.
+func channelPositions(channels int) []string {
+	switch channels {
+	case 1:
+		return []string{"MONO"}
+	case 2:
+		return []string{"FL", "FR"}
+	case 6:
+		return []string{"FL", "FR", "FC", "LFE", "RL", "RR"}
+	case 8:
+		return []string{"FL", "FR", "FC", "LFE", "RL", "RR", "SL", "SR"}
+	}
+
+	positions := make([]string, channels)
+
+	for i := range positions {
+		positions[i] = "AUX" + strconv.Itoa(i)
+	}
+
+	return positions
+}
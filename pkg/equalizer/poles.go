@@ -0,0 +1,107 @@
+package equalizer
+
+import "math/cmplx"
+
+// poleZeroer is implemented by the processors whose poles and zeros are known.
+type poleZeroer interface {
+	Poles() []complex128
+	Zeros() []complex128
+}
+
+// Poles returns the poles of the filter on the z-plane, which are the roots of z^2 + a1*z + a2.
+func (f *Filter) Poles() []complex128 {
+	return quadraticRoots(1.0, f.a1, f.a2)
+}
+
+// Zeros returns the zeros of the filter on the z-plane, which are the roots of b0*z^2 + b1*z + b2.
+func (f *Filter) Zeros() []complex128 {
+	return quadraticRoots(f.b0, f.b1, f.b2)
+}
+
+// Stable returns true when all poles are inside the unit circle.
+func (f *Filter) Stable() bool {
+	return insideUnitCircle(f.Poles())
+}
+
+// Poles returns the poles of all bands.
+func (e *ParametricEQ) Poles() []complex128 {
+	var poles []complex128
+
+	for _, filter := range e.filters {
+		poles = append(poles, filter.Poles()...)
+	}
+
+	return poles
+}
+
+// Zeros returns the zeros of all bands.
+func (e *ParametricEQ) Zeros() []complex128 {
+	var zeros []complex128
+
+	for _, filter := range e.filters {
+		zeros = append(zeros, filter.Zeros()...)
+	}
+
+	return zeros
+}
+
+// Poles returns the poles of the processors in the chain.
+//
+// NOTE: The processors which do not provide Poles and Zeros, e.g. the nonlinear processors, are skipped.
+func (c *Chain) Poles() []complex128 {
+	var poles []complex128
+
+	for _, processor := range c.processors {
+		if p, ok := processor.(poleZeroer); ok {
+			poles = append(poles, p.Poles()...)
+		}
+	}
+
+	return poles
+}
+
+// Zeros returns the zeros of the processors in the chain. See Poles for the skipped processors.
+func (c *Chain) Zeros() []complex128 {
+	var zeros []complex128
+
+	for _, processor := range c.processors {
+		if p, ok := processor.(poleZeroer); ok {
+			zeros = append(zeros, p.Zeros()...)
+		}
+	}
+
+	return zeros
+}
+
+// Stable returns true when all poles of the chain are inside the unit circle.
+func (c *Chain) Stable() bool {
+	return insideUnitCircle(c.Poles())
+}
+
+// quadraticRoots returns the roots of c2*z^2 + c1*z + c0. The degree is lowered when the leading coefficients are zero.
+func quadraticRoots(c2, c1, c0 float64) []complex128 {
+	switch {
+	case c2 != 0.0:
+		d := cmplx.Sqrt(complex(c1*c1-4.0*c2*c0, 0.0))
+
+		return []complex128{
+			(complex(-c1, 0.0) + d) / complex(2.0*c2, 0.0),
+			(complex(-c1, 0.0) - d) / complex(2.0*c2, 0.0),
+		}
+	case c1 != 0.0:
+		return []complex128{complex(-c0/c1, 0.0)}
+	default:
+		return nil
+	}
+}
+
+// insideUnitCircle returns true when the magnitudes of all roots are less than 1.
+func insideUnitCircle(roots []complex128) bool {
+	for _, root := range roots {
+		if cmplx.Abs(root) >= 1.0 {
+			return false
+		}
+	}
+
+	return true
+}
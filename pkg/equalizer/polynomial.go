@@ -0,0 +1,99 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// polynomialRoots returns the roots of the polynomial c[0]*x^n + c[1]*x^(n-1) + ... + c[n] with the Durand-Kerner method.
+func polynomialRoots(c []float64) []complex128 {
+	// Drop the leading zeros, they do not form the roots.
+	for len(c) > 0 && c[0] == 0.0 {
+		c = c[1:]
+	}
+
+	n := len(c) - 1
+
+	if n < 1 {
+		return nil
+	}
+
+	monic := make([]complex128, n+1)
+
+	for i := range c {
+		monic[i] = complex(c[i]/c[0], 0.0)
+	}
+
+	evaluate := func(x complex128) complex128 {
+		y := complex(0.0, 0.0)
+
+		for _, coefficient := range monic {
+			y = y*x + coefficient
+		}
+
+		return y
+	}
+
+	roots := make([]complex128, n)
+	seed := complex(0.4, 0.9)
+
+	for i := range roots {
+		roots[i] = cmplx.Pow(seed, complex(float64(i), 0.0))
+	}
+
+	for iteration := 0; iteration < 500; iteration++ {
+		delta := 0.0
+
+		for i := range roots {
+			denominator := complex(1.0, 0.0)
+
+			for j := range roots {
+				if i != j {
+					denominator *= roots[i] - roots[j]
+				}
+			}
+
+			step := evaluate(roots[i]) / denominator
+			roots[i] -= step
+			delta = math.Max(delta, cmplx.Abs(step))
+		}
+
+		if delta < 1e-14 {
+			break
+		}
+	}
+
+	return roots
+}
+
+// quadraticFactors groups the roots into the real quadratic factors x^2 + f[0]*x + f[1].
+// The conjugate roots are paired, then the real roots are paired.
+// The last factor is linear, f[1] is 0 and the factor is x + f[0], when the number of the real roots is odd.
+func quadraticFactors(roots []complex128) ([][2]float64, bool) {
+	const epsilon = 1e-9
+
+	reals := []float64{}
+	factors := [][2]float64{}
+
+	for _, root := range roots {
+		switch {
+		case math.Abs(imag(root)) <= epsilon*math.Max(1.0, cmplx.Abs(root)):
+			reals = append(reals, real(root))
+		case imag(root) > 0.0:
+			factors = append(factors, [2]float64{-2.0 * real(root), real(root)*real(root) + imag(root)*imag(root)})
+		}
+	}
+
+	sort.Float64s(reals)
+
+	for i := 0; i+1 < len(reals); i += 2 {
+		factors = append(factors, [2]float64{-(reals[i] + reals[i+1]), reals[i] * reals[i+1]})
+	}
+
+	if len(reals)%2 == 1 {
+		return append(factors, [2]float64{-reals[len(reals)-1], 0.0}), true
+	}
+
+	return factors, false
+}
@@ -0,0 +1,196 @@
+package equalizer
+
+import (
+	"math"
+	"math/big"
+)
+
+// precision is the number of the mantissa bits of the high precision design.
+const precision = 192
+
+// bigPi is pi with more digits than the precision.
+var bigPi, _ = new(big.Float).SetPrec(precision).SetString("3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798")
+
+// designPrecise returns the filter designed with the extended precision, or nil when the filter name is not built-in.
+// The angular frequency, its sine and cosine, and the coefficients are computed with the precision bits, and each
+// normalized coefficient is rounded to float64 only once, so the poles near z = 1 are placed as exactly as float64 can
+// represent, e.g. the high-pass at 0.005 Hz for the sensor sampled every minute or the sub-bass filter at 192 kHz.
+//
+// NOTE: The gain and the band width are converted with float64, because their rounding errors are not amplified.
+func designPrecise(name FilterName, sampleRate, frequency, q, gain float64) *Filter {
+	w0 := bigQuo(bigMul(bigMul(bigFloat(2.0), bigPi), bigFloat(frequency)), bigFloat(sampleRate))
+	sin, cos := bigSinCos(w0)
+	w, _ := w0.Float64()
+	s, _ := sin.Float64()
+	one := bigFloat(1.0)
+	two := bigFloat(2.0)
+
+	var scale, a1, a2, b0, b1, b2 *big.Float
+
+	switch name {
+	case LowPass, HighPass, AllPass:
+		alpha := bigQuo(sin, bigFloat(2.0*q))
+
+		scale, a1, a2 = bigAdd(one, alpha), bigNeg(bigMul(two, cos)), bigSub(one, alpha)
+
+		switch name {
+		case LowPass:
+			b1 = bigSub(one, cos)
+			b0, b2 = bigQuo(b1, two), bigQuo(b1, two)
+		case HighPass:
+			b1 = bigNeg(bigAdd(one, cos))
+			b0, b2 = bigQuo(bigAdd(one, cos), two), bigQuo(bigAdd(one, cos), two)
+		case AllPass:
+			b0, b1, b2 = bigSub(one, alpha), bigNeg(bigMul(two, cos)), bigAdd(one, alpha)
+		}
+	case BandPass, BandReject, Peaking:
+		alpha := bigMul(sin, bigFloat(math.Sinh(math.Log(2.0)/2.0*q*w/s)))
+
+		scale, a1, a2 = bigAdd(one, alpha), bigNeg(bigMul(two, cos)), bigSub(one, alpha)
+
+		switch name {
+		case BandPass:
+			b0, b1, b2 = alpha, bigFloat(0.0), bigNeg(alpha)
+		case BandReject:
+			b0, b1, b2 = one, bigNeg(bigMul(two, cos)), one
+		case Peaking:
+			a := bigFloat(math.Pow(10.0, gain/40.0))
+
+			scale, a2 = bigAdd(one, bigQuo(alpha, a)), bigSub(one, bigQuo(alpha, a))
+			b0, b1, b2 = bigAdd(one, bigMul(alpha, a)), bigNeg(bigMul(two, cos)), bigSub(one, bigMul(alpha, a))
+		}
+	case LowShelf, HighShelf:
+		a := bigFloat(math.Pow(10.0, gain/40.0))
+		beta := bigQuo(new(big.Float).SetPrec(precision).Sqrt(a), bigFloat(q))
+		plus, minus := bigAdd(a, one), bigSub(a, one)
+		betaSin := bigMul(beta, sin)
+
+		// The high-shelf is the low-shelf with the sign of cos(w0) flipped.
+		if name == HighShelf {
+			cos = bigNeg(cos)
+		}
+
+		scale = bigAdd(bigAdd(plus, bigMul(minus, cos)), betaSin)
+		a1 = bigMul(bigFloat(-2.0), bigAdd(minus, bigMul(plus, cos)))
+		a2 = bigSub(bigAdd(plus, bigMul(minus, cos)), betaSin)
+		b0 = bigMul(a, bigAdd(bigSub(plus, bigMul(minus, cos)), betaSin))
+		b1 = bigMul(bigMul(two, a), bigSub(minus, bigMul(plus, cos)))
+		b2 = bigMul(a, bigSub(bigSub(plus, bigMul(minus, cos)), betaSin))
+
+		if name == HighShelf {
+			a1, b1 = bigNeg(a1), bigNeg(b1)
+		}
+	default:
+		return nil
+	}
+
+	f := &Filter{
+		name:       name,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		method:     preciseDesign,
+	}
+
+	if name == LowShelf || name == HighShelf || name == Peaking {
+		f.gain = gain
+	}
+
+	f.scale, _ = scale.Float64()
+	f.a1, _ = bigQuo(a1, scale).Float64()
+	f.a2, _ = bigQuo(a2, scale).Float64()
+	f.b0, _ = bigQuo(b0, scale).Float64()
+	f.b1, _ = bigQuo(b1, scale).Float64()
+	f.b2, _ = bigQuo(b2, scale).Float64()
+
+	return f
+}
+
+// designMethod is how the coefficients of the built-in filters are designed.
+type designMethod int
+
+const (
+	// cookbookDesign is the bilinear transform of the cookbook computed with float64.
+	cookbookDesign designMethod = iota
+
+	// preciseDesign is the bilinear transform of the cookbook computed with the extended precision.
+	preciseDesign
+
+	// matchedDesign matches the magnitude of the analog prototype, see designMatched.
+	matchedDesign
+)
+
+// designWith returns the filter designed with the method. The registered filters are designed by design as usual.
+func designWith(method designMethod, name FilterName, sampleRate, frequency, q, gain float64) *Filter {
+	var f *Filter
+
+	switch method {
+	case preciseDesign:
+		f = designPrecise(name, sampleRate, frequency, q, gain)
+	case matchedDesign:
+		f = designMatched(name, sampleRate, frequency, q, gain)
+	}
+	if f != nil {
+		return f
+	}
+
+	return design(name, sampleRate, frequency, q, gain)
+}
+
+// bigSinCos returns the sine and the cosine of x by the Taylor series. x is reduced to between -pi and pi beforehand.
+func bigSinCos(x *big.Float) (*big.Float, *big.Float) {
+	twoPi := bigMul(bigFloat(2.0), bigPi)
+	turns, _ := bigQuo(x, twoPi).Float64()
+	x = bigSub(x, bigMul(bigFloat(math.Round(turns)), twoPi))
+
+	sin, cos := bigFloat(0.0), bigFloat(0.0)
+	term := bigFloat(1.0)
+	limit := new(big.Float).SetMantExp(bigFloat(1.0), -precision-8)
+
+	// term is x^n / n!, which is added to the cosine for the even n and to the sine for the odd n.
+	for n := 0; n < 1000; n++ {
+		switch n % 4 {
+		case 0:
+			cos = bigAdd(cos, term)
+		case 1:
+			sin = bigAdd(sin, term)
+		case 2:
+			cos = bigSub(cos, term)
+		case 3:
+			sin = bigSub(sin, term)
+		}
+
+		term = bigQuo(bigMul(term, x), bigFloat(float64(n+1)))
+
+		if n > 1 && new(big.Float).Abs(term).Cmp(limit) < 0 {
+			break
+		}
+	}
+
+	return sin, cos
+}
+
+// bigFloat returns x with the precision bits.
+func bigFloat(x float64) *big.Float {
+	return new(big.Float).SetPrec(precision).SetFloat64(x)
+}
+
+func bigAdd(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(precision).Add(x, y)
+}
+
+func bigSub(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(precision).Sub(x, y)
+}
+
+func bigMul(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(precision).Mul(x, y)
+}
+
+func bigQuo(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(precision).Quo(x, y)
+}
+
+func bigNeg(x *big.Float) *big.Float {
+	return new(big.Float).SetPrec(precision).Neg(x)
+}
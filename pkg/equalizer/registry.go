@@ -0,0 +1,118 @@
+package equalizer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Designer designs the biquad filter from the band parameters, e.g. with NewCustom. It is called again whenever the
+// parameters are changed, so it must be fast and must not keep the returned filter.
+type Designer func(sampleRate, frequency, q, gain float64) *Filter
+
+// registry holds the designers registered by Register.
+var registry = struct {
+	sync.RWMutex
+	designers map[FilterName]Designer
+	names     map[string]FilterName
+}{
+	designers: map[FilterName]Designer{},
+	names:     map[string]FilterName{},
+}
+
+// Register registers the designer by the name and returns the filter name which is used in the Band, so the third-party
+// filter type works in the parametric equalizer, the config files, the presets and the command line flags.
+// Call it in the init function of the package which provides the filter, as same as database/sql.Register.
+//
+// Parameters:
+//
+//     - name ... Name used in the config files and as the flag of the commands, e.g. "myshelf". It must not be the built-in filter flag names, e.g. "peak".
+//     - designer ... Function which designs the filter.
+//
+// NOTE: It panics when the name is empty or already registered, or the designer is nil.
+func Register(name string, designer Designer) FilterName {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if name == "" || designer == nil {
+		panic("equalizer: Register called with the empty name or the nil designer")
+	}
+	if _, ok := registry.names[name]; ok {
+		panic(fmt.Sprintf("equalizer: Register called twice for %q", name))
+	}
+
+	filterName := Custom + 1 + FilterName(len(registry.names))
+
+	registry.designers[filterName] = designer
+	registry.names[name] = filterName
+
+	return filterName
+}
+
+// Lookup returns the filter name registered by the name.
+func Lookup(name string) (FilterName, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	filterName, ok := registry.names[name]
+
+	return filterName, ok
+}
+
+// RegisteredName returns the name by which the filter name is registered, or the empty string for the built-in filters.
+func RegisteredName(filterName FilterName) string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for name, f := range registry.names {
+		if f == filterName {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Registered returns the sorted names of the registered designers.
+func Registered() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.names))
+
+	for name := range registry.names {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// designRegistered designs the filter by the registered designer, or returns nil when the name is not registered.
+func designRegistered(name FilterName, sampleRate, frequency, q, gain float64) *Filter {
+	registry.RLock()
+	designer, ok := registry.designers[name]
+	registry.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	f := designer(sampleRate, frequency, q, gain)
+
+	if f == nil {
+		return nil
+	}
+
+	// The design parameters are kept, so the setters and SetBand redesign the filter by the designer.
+	g := *f
+
+	g.name = name
+	g.sampleRate = sampleRate
+	g.frequency = frequency
+	g.q = q
+	g.gain = gain
+
+	return &g
+}
@@ -0,0 +1,168 @@
+package equalizer
+
+import "math"
+
+const (
+	// resamplerZeros is the number of the zero crossings of the sinc on each side of the kernel.
+	resamplerZeros = 64
+
+	// resamplerResolution is the number of the table entries between the zero crossings.
+	resamplerResolution = 512
+
+	// resamplerRolloff is the cut off frequency relative to the lower Nyquist frequency. The margin is the transition band.
+	resamplerRolloff = 0.9
+)
+
+// Resampler converts the sample rate by the band-limited interpolation with the Blackman windowed sinc.
+// When the rate is lowered, the kernel is stretched, so it is also the anti-aliasing filter.
+// The ratio of the rates is arbitrary, e.g. 48000 Hz to 44100 Hz or 1 Hz to 1/60 Hz.
+type Resampler struct {
+	fromRate float64
+	toRate   float64
+
+	// step is the number of the input samples per output sample.
+	step float64
+
+	// scale is the cut off frequency relative to the input Nyquist frequency and half is the half width of the kernel in the input samples.
+	scale float64
+	half  float64
+	table []float64
+
+	// history holds the inputs not consumed yet. offset is the input index of history[0].
+	history  []float64
+	offset   int64
+	inputs   int64
+	produced int64
+}
+
+// NewResampler returns the resampler.
+//
+// Parameters:
+//
+//     - fromRate ... Input sample rate in Hz. e.g. 48000.0
+//     - toRate ... Output sample rate in Hz. e.g. 44100.0
+func NewResampler(fromRate, toRate float64) *Resampler {
+	scale := resamplerRolloff * math.Min(1.0, toRate/fromRate)
+	table := make([]float64, resamplerZeros*resamplerResolution+2)
+
+	for i := range table {
+		u := float64(i) / resamplerResolution
+		table[i] = sinc(u) * blackman(u, resamplerZeros)
+	}
+
+	r := &Resampler{
+		fromRate: fromRate,
+		toRate:   toRate,
+		step:     fromRate / toRate,
+		scale:    scale,
+		half:     resamplerZeros / scale,
+		table:    table,
+	}
+
+	r.Reset()
+
+	return r
+}
+
+// Latency returns the number of the output samples which are held back until the following inputs arrive. Call Flush to get them.
+func (r *Resampler) Latency() int {
+	return int(math.Ceil(r.half / r.step))
+}
+
+// Process feeds the inputs and returns the output samples which can be computed so far.
+func (r *Resampler) Process(input []float64) []float64 {
+	return r.ProcessInto(nil, input)
+}
+
+// ProcessInto feeds the inputs and appends the output samples which can be computed so far to dst like append.
+// See Decimator.ProcessInto.
+func (r *Resampler) ProcessInto(dst, input []float64) []float64 {
+	r.history = append(r.history, input...)
+	r.inputs += int64(len(input))
+
+	return r.produce(dst, math.MaxInt64)
+}
+
+// Flush returns the remaining output samples and resets the resampler. The total number of the outputs is the number
+// of the inputs converted to the output rate and rounded up.
+func (r *Resampler) Flush() []float64 {
+	total := int64(math.Ceil(float64(r.inputs) / r.step))
+
+	r.history = append(r.history, make([]float64, int(math.Ceil(r.half))+1)...)
+	output := r.produce(nil, total)
+
+	r.Reset()
+
+	return output
+}
+
+// Reset clears the inputs. The next input is treated as the first one.
+func (r *Resampler) Reset() {
+	// The zeros before the first input let the kernel look back from the first output.
+	padding := int(math.Ceil(r.half)) + 1
+
+	r.history = make([]float64, padding)
+	r.offset = -int64(padding)
+	r.inputs = 0
+	r.produced = 0
+}
+
+// produce appends the outputs to the output until the kernel needs the inputs not arrived yet or the number of the outputs reaches the total.
+func (r *Resampler) produce(output []float64, total int64) []float64 {
+	for r.produced < total {
+		t := float64(r.produced) * r.step
+
+		if int64(math.Floor(t+r.half)) >= r.offset+int64(len(r.history)) {
+			break
+		}
+
+		output = append(output, r.at(t))
+		r.produced++
+	}
+
+	// Drop the inputs which the kernel never reaches again.
+	t := float64(r.produced) * r.step
+
+	if drop := int64(math.Floor(t-r.half)) - r.offset; drop > 0 {
+		if drop > int64(len(r.history)) {
+			drop = int64(len(r.history))
+		}
+
+		r.history = append(r.history[:0], r.history[drop:]...)
+		r.offset += drop
+	}
+
+	return output
+}
+
+// at returns the value at the input time t in samples.
+func (r *Resampler) at(t float64) float64 {
+	first := int64(math.Ceil(t - r.half))
+	last := int64(math.Floor(t + r.half))
+	sum := 0.0
+
+	for k := first; k <= last; k++ {
+		sum += r.history[k-r.offset] * r.kernel(math.Abs(t-float64(k))*r.scale)
+	}
+
+	return sum * r.scale
+}
+
+// kernel returns the windowed sinc at u zero crossings from the center, interpolated from the table.
+func (r *Resampler) kernel(u float64) float64 {
+	x := u * resamplerResolution
+	i := int(x)
+
+	if i >= len(r.table)-1 {
+		return 0.0
+	}
+
+	return r.table[i] + (r.table[i+1]-r.table[i])*(x-float64(i))
+}
+
+// Resample converts the sample rate of the whole signal. See NewResampler for the parameters.
+func Resample(samples []float64, fromRate, toRate float64) []float64 {
+	r := NewResampler(fromRate, toRate)
+
+	return append(r.Process(samples), r.Flush()...)
+}
@@ -0,0 +1,63 @@
+package equalizer
+
+import (
+	"math"
+	"sort"
+)
+
+// ResponsePoint is the magnitude in dB at the frequency in Hz.
+type ResponsePoint struct {
+	Frequency float64
+	Gain      float64
+}
+
+// Response is the magnitude response, e.g. the measured frequency response of the speaker.
+type Response []ResponsePoint
+
+// Sort sorts the points by the frequency.
+func (r Response) Sort() {
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Frequency < r[j].Frequency
+	})
+}
+
+// At returns the gain in dB at the frequency. The gain is interpolated linearly on the logarithmic frequency axis and held outside the points.
+//
+// NOTE: The points must be sorted by the frequency.
+func (r Response) At(frequency float64) float64 {
+	if len(r) == 0 {
+		return 0.0
+	}
+	if frequency <= r[0].Frequency {
+		return r[0].Gain
+	}
+	if frequency >= r[len(r)-1].Frequency {
+		return r[len(r)-1].Gain
+	}
+
+	i := sort.Search(len(r), func(i int) bool {
+		return r[i].Frequency >= frequency
+	})
+
+	a, b := r[i-1], r[i]
+	t := math.Log(frequency/a.Frequency) / math.Log(b.Frequency/a.Frequency)
+
+	return a.Gain + (b.Gain-a.Gain)*t
+}
+
+// logFrequencies returns the frequencies spaced equally on the logarithmic axis.
+func logFrequencies(minFrequency, maxFrequency float64, count int) []float64 {
+	frequencies := make([]float64, count)
+
+	for i := range frequencies {
+		t := 0.0
+
+		if count > 1 {
+			t = float64(i) / float64(count-1)
+		}
+
+		frequencies[i] = minFrequency * math.Pow(maxFrequency/minFrequency, t)
+	}
+
+	return frequencies
+}
@@ -0,0 +1,416 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// MovingAverage is the boxcar smoother which outputs the mean of the last size inputs.
+type MovingAverage struct {
+	sampleRate float64
+
+	// window holds the last inputs and sum is their total.
+	window   []float64
+	position int
+	sum      float64
+}
+
+// NewMovingAverage returns the moving average.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - size ... Number of the averaged samples. The first zero at the frequency sampleRate/size is the strongest cut.
+//
+// NOTE: size less than 1 is treated as 1. The output is delayed by (size-1)/2 samples.
+func NewMovingAverage(sampleRate float64, size int) *MovingAverage {
+	if size < 1 {
+		size = 1
+	}
+
+	return &MovingAverage{
+		sampleRate: sampleRate,
+		window:     make([]float64, size),
+	}
+}
+
+// Size returns the number of the averaged samples.
+func (m *MovingAverage) Size() int {
+	return len(m.window)
+}
+
+// Apply applies the moving average and returns the value. The missing inputs before the first one are treated as 0.
+func (m *MovingAverage) Apply(input float64) float64 {
+	m.sum += input - m.window[m.position]
+	m.window[m.position] = input
+	m.position = (m.position + 1) % len(m.window)
+
+	// The running sum accumulates the rounding errors, so it is recomputed once per round of the window.
+	if m.position == 0 {
+		m.sum = 0.0
+
+		for _, value := range m.window {
+			m.sum += value
+		}
+	}
+
+	return m.sum / float64(len(m.window))
+}
+
+// ProcessBuffer applies the moving average to the buffer in place.
+func (m *MovingAverage) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = m.Apply(buffer[i])
+	}
+}
+
+// Reset clears the window.
+func (m *MovingAverage) Reset() {
+	for i := range m.window {
+		m.window[i] = 0.0
+	}
+
+	m.position = 0
+	m.sum = 0.0
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (m *MovingAverage) FrequencyResponse(frequency float64) complex128 {
+	w := 2.0 * p * frequency / m.sampleRate
+	n := float64(len(m.window))
+
+	// The sum of the geometric series, sin(n*w/2) / (n*sin(w/2)) with the linear phase.
+	if math.Abs(math.Sin(w/2.0)) < 1e-12 {
+		return 1.0
+	}
+
+	return complex(math.Sin(n*w/2.0)/(n*math.Sin(w/2.0)), 0.0) * cmplx.Exp(complex(0.0, -w*(n-1.0)/2.0))
+}
+
+// GroupDelay returns the group delay in seconds, which is (size-1)/2 samples at any frequency.
+func (m *MovingAverage) GroupDelay(frequency float64) float64 {
+	return float64(len(m.window)-1) / 2.0 / m.sampleRate
+}
+
+// SavitzkyGolay is the smoother which fits the polynomial to the window by the least squares and outputs its value at
+// the center. It keeps the height and the width of the peaks better than the moving average of the same size.
+type SavitzkyGolay struct {
+	sampleRate   float64
+	coefficients []float64
+
+	// window holds the last inputs.
+	window   []float64
+	position int
+}
+
+// NewSavitzkyGolay returns the Savitzky-Golay smoother.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - size ... Number of the samples in the window. e.g. 11
+//     - order ... Order of the polynomial. e.g. 2 or 4. Order 0 and 1 are the same as the moving average.
+//
+// NOTE: The even size is rounded up to the odd number and the order is limited below the size. The output is delayed by (size-1)/2 samples.
+func NewSavitzkyGolay(sampleRate float64, size, order int) *SavitzkyGolay {
+	coefficients := SavitzkyGolayCoefficients(size, order)
+
+	return &SavitzkyGolay{
+		sampleRate:   sampleRate,
+		coefficients: coefficients,
+		window:       make([]float64, len(coefficients)),
+	}
+}
+
+// SavitzkyGolayCoefficients returns the FIR coefficients which smooth the center of the window. See NewSavitzkyGolay for the parameters.
+func SavitzkyGolayCoefficients(size, order int) []float64 {
+	if size < 1 {
+		size = 1
+	}
+	if size%2 == 0 {
+		size++
+	}
+	if order >= size {
+		order = size - 1
+	}
+	if order < 0 {
+		order = 0
+	}
+
+	half := size / 2
+	n := order + 1
+
+	// normal is the matrix A^T*A of the least squares, where A[i][j] = (i-half)^j.
+	normal := make([][]float64, n)
+
+	for j := range normal {
+		normal[j] = make([]float64, n)
+
+		for k := range normal[j] {
+			for i := -half; i <= half; i++ {
+				normal[j][k] += math.Pow(float64(i), float64(j+k))
+			}
+		}
+	}
+
+	// The smoothed value is the constant term of the fitted polynomial, so only the first row of the inverse is needed.
+	row := make([]float64, n)
+	row[0] = 1.0
+	row = solveLinear(normal, row)
+
+	coefficients := make([]float64, size)
+
+	for i := range coefficients {
+		x := float64(i - half)
+
+		for j := n - 1; j >= 0; j-- {
+			coefficients[i] = coefficients[i]*x + row[j]
+		}
+	}
+
+	return coefficients
+}
+
+// Size returns the number of the samples in the window.
+func (s *SavitzkyGolay) Size() int {
+	return len(s.window)
+}
+
+// Coefficients returns the FIR coefficients. The first one is applied to the oldest sample in the window.
+func (s *SavitzkyGolay) Coefficients() []float64 {
+	return s.coefficients
+}
+
+// Apply applies the smoother and returns the value. The missing inputs before the first one are treated as 0.
+func (s *SavitzkyGolay) Apply(input float64) float64 {
+	n := len(s.window)
+
+	s.window[s.position] = input
+	s.position = (s.position + 1) % n
+
+	output := 0.0
+
+	// The oldest sample is at the position after the latest one.
+	for i, c := range s.coefficients {
+		output += c * s.window[(s.position+i)%n]
+	}
+
+	return output
+}
+
+// ProcessBuffer applies the smoother to the buffer in place.
+func (s *SavitzkyGolay) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = s.Apply(buffer[i])
+	}
+}
+
+// Reset clears the window.
+func (s *SavitzkyGolay) Reset() {
+	for i := range s.window {
+		s.window[i] = 0.0
+	}
+
+	s.position = 0
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (s *SavitzkyGolay) FrequencyResponse(frequency float64) complex128 {
+	w := 2.0 * p * frequency / s.sampleRate
+	n := len(s.coefficients)
+	response := complex(0.0, 0.0)
+
+	// The newest sample is multiplied by the last coefficient.
+	for i, c := range s.coefficients {
+		response += complex(c, 0.0) * cmplx.Exp(complex(0.0, -w*float64(n-1-i)))
+	}
+
+	return response
+}
+
+// GroupDelay returns the group delay in seconds, which is (size-1)/2 samples at any frequency because the coefficients are symmetric.
+func (s *SavitzkyGolay) GroupDelay(frequency float64) float64 {
+	return float64(len(s.window)-1) / 2.0 / s.sampleRate
+}
+
+// solveLinear solves a*x = b with the Gaussian elimination and the partial pivoting. The a and the b are modified.
+func solveLinear(a [][]float64, b []float64) []float64 {
+	n := len(b)
+
+	for col := 0; col < n; col++ {
+		pivot := col
+
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+
+			b[row] -= factor * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+
+		x[row] = sum / a[row][row]
+	}
+
+	return x
+}
+
+// ExponentialSmoothing is the exponential moving average, the one-pole low-pass filter which is also used as the level detector.
+type ExponentialSmoothing struct {
+	sampleRate  float64
+	coefficient float64
+
+	// state variables
+	level       float64
+	initialized bool
+}
+
+// NewExponentialSmoothing returns the exponential moving average.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - timeConstant ... Time in seconds to reach 1-1/e of the step. e.g. 0.1
+//
+// NOTE: The first input initializes the level, so the output does not ramp up from 0. timeConstant less than or equal to 0 passes the input through.
+func NewExponentialSmoothing(sampleRate, timeConstant float64) *ExponentialSmoothing {
+	return &ExponentialSmoothing{
+		sampleRate:  sampleRate,
+		coefficient: smoothingCoefficient(sampleRate, timeConstant),
+	}
+}
+
+// Apply feeds the input and returns the smoothed level.
+func (e *ExponentialSmoothing) Apply(input float64) float64 {
+	if !e.initialized {
+		e.level = input
+		e.initialized = true
+	}
+
+	e.level = e.coefficient*e.level + (1.0-e.coefficient)*input
+
+	return e.level
+}
+
+// ProcessBuffer applies the smoothing to the buffer in place.
+func (e *ExponentialSmoothing) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = e.Apply(buffer[i])
+	}
+}
+
+// Value returns the current level without feeding the input.
+func (e *ExponentialSmoothing) Value() float64 {
+	return e.level
+}
+
+// Reset clears the level, so the next input initializes it again.
+func (e *ExponentialSmoothing) Reset() {
+	e.level = 0.0
+	e.initialized = false
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (e *ExponentialSmoothing) FrequencyResponse(frequency float64) complex128 {
+	w := 2.0 * p * frequency / e.sampleRate
+	z1 := cmplx.Exp(complex(0.0, -w))
+
+	return complex(1.0-e.coefficient, 0.0) / (1.0 - complex(e.coefficient, 0.0)*z1)
+}
+
+// DoubleExponentialSmoothing is Holt's linear smoothing which tracks the level and the trend, so the output does not
+// lag behind the ramp as the exponential moving average does.
+type DoubleExponentialSmoothing struct {
+	sampleRate float64
+
+	// smoothing coefficients
+	levelCoefficient float64
+	trendCoefficient float64
+
+	// state variables
+	level   float64
+	trend   float64
+	samples int
+}
+
+// NewDoubleExponentialSmoothing returns the double exponential smoothing.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 100.0
+//     - levelTime ... Time constant of the level in seconds. e.g. 1.0
+//     - trendTime ... Time constant of the trend in seconds. e.g. 10.0
+//
+// NOTE: The first two inputs initialize the level and the trend.
+func NewDoubleExponentialSmoothing(sampleRate, levelTime, trendTime float64) *DoubleExponentialSmoothing {
+	return &DoubleExponentialSmoothing{
+		sampleRate:       sampleRate,
+		levelCoefficient: smoothingCoefficient(sampleRate, levelTime),
+		trendCoefficient: smoothingCoefficient(sampleRate, trendTime),
+	}
+}
+
+// Apply feeds the input and returns the smoothed level.
+func (d *DoubleExponentialSmoothing) Apply(input float64) float64 {
+	switch d.samples {
+	case 0:
+		d.level = input
+		d.samples++
+
+		return d.level
+	case 1:
+		d.trend = input - d.level
+		d.samples++
+	}
+
+	previous := d.level
+
+	d.level = d.levelCoefficient*(d.level+d.trend) + (1.0-d.levelCoefficient)*input
+	d.trend = d.trendCoefficient*d.trend + (1.0-d.trendCoefficient)*(d.level-previous)
+
+	return d.level
+}
+
+// ProcessBuffer applies the smoothing to the buffer in place.
+func (d *DoubleExponentialSmoothing) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = d.Apply(buffer[i])
+	}
+}
+
+// Value returns the current level without feeding the input.
+func (d *DoubleExponentialSmoothing) Value() float64 {
+	return d.level
+}
+
+// Trend returns the current slope of the level per second.
+func (d *DoubleExponentialSmoothing) Trend() float64 {
+	return d.trend * d.sampleRate
+}
+
+// Reset clears the level and the trend, so the next inputs initialize them again.
+func (d *DoubleExponentialSmoothing) Reset() {
+	d.level = 0.0
+	d.trend = 0.0
+	d.samples = 0
+}
@@ -0,0 +1,82 @@
+package equalizer
+
+import "math"
+
+// monitor is the band-pass filter which lets the user hear the range of the soloed band.
+type monitor struct {
+	filter *Filter
+
+	// gain is 1 while the band is soloed and ramps to 0 when the solo is released.
+	gain ramp
+}
+
+// SetMuted ramps the effect of the i-th band out, or in when muted is false, so the band can be compared with and without it.
+//
+// NOTE: The responses, e.g. FrequencyResponse, are of all the bands regardless of the mute and the solo.
+func (e *ParametricEQ) SetMuted(i int, muted bool) {
+	e.filters[i].SetBypassed(muted)
+}
+
+// Muted returns true when the i-th band is muted.
+func (e *ParametricEQ) Muted(i int) bool {
+	return e.filters[i].Bypassed()
+}
+
+// SetSolo isolates the i-th band. While any band is soloed, the output is the input band-passed around the soloed bands,
+// so the user hears exactly the range which the band changes. The change is ramped over BypassTime.
+func (e *ParametricEQ) SetSolo(i int, solo bool) {
+	length := int(BypassTime.Seconds() * e.sampleRate)
+
+	// The monitor which was released starts with the clean state.
+	if solo && (e.monitors[i].filter == nil || !e.monitors[i].gain.active()) {
+		e.monitors[i].filter = newMonitorFilter(e.sampleRate, e.bands[i])
+	}
+
+	e.monitors[i].gain.set(bypassAmount(solo), length)
+
+	soloed := false
+
+	for _, m := range e.monitors {
+		soloed = soloed || m.gain.target == 1.0
+	}
+
+	e.solo.set(bypassAmount(soloed), length)
+}
+
+// Soloed returns true when the i-th band is soloed.
+func (e *ParametricEQ) Soloed(i int) bool {
+	return e.monitors[i].gain.target == 1.0
+}
+
+// applySolo returns the output crossfaded to the sum of the monitors of the soloed bands.
+func (e *ParametricEQ) applySolo(input, output float64) float64 {
+	sum := 0.0
+
+	for i := range e.monitors {
+		m := &e.monitors[i]
+
+		if m.filter == nil || !m.gain.active() {
+			continue
+		}
+
+		sum += m.gain.advance() * m.filter.Apply(input)
+	}
+
+	amount := e.solo.advance()
+
+	return output + amount*(sum-output)
+}
+
+// newMonitorFilter returns the band-pass filter around the band. The Q value is converted to the band width in octaves
+// for the filters whose Q is not the band width.
+func newMonitorFilter(sampleRate float64, band Band) *Filter {
+	width := band.Q
+
+	switch band.Name {
+	case BandPass, BandReject, Peaking:
+	default:
+		width = 2.0 / math.Ln2 * math.Asinh(1.0/(2.0*band.Q))
+	}
+
+	return NewBandPass(sampleRate, band.Frequency, width)
+}
@@ -0,0 +1,54 @@
+package equalizer
+
+import "fmt"
+
+// FilterState is the state variables of the filter, which are the last two inputs and outputs.
+// Saving and restoring the state lets the processing continue later without the transient at the restart.
+type FilterState struct {
+	In1  float64
+	In2  float64
+	Out1 float64
+	Out2 float64
+}
+
+// State returns the state variables.
+func (f *Filter) State() FilterState {
+	return FilterState{
+		In1:  f.in1,
+		In2:  f.in2,
+		Out1: f.out1,
+		Out2: f.out2,
+	}
+}
+
+// SetState restores the state variables returned by State.
+func (f *Filter) SetState(state FilterState) {
+	f.in1 = state.In1
+	f.in2 = state.In2
+	f.out1 = state.Out1
+	f.out2 = state.Out2
+}
+
+// State returns the state variables of each band.
+func (e *ParametricEQ) State() []FilterState {
+	states := make([]FilterState, len(e.filters))
+
+	for i, filter := range e.filters {
+		states[i] = filter.State()
+	}
+
+	return states
+}
+
+// SetState restores the state variables returned by State. The number of the states must match the number of the bands.
+func (e *ParametricEQ) SetState(states []FilterState) error {
+	if len(states) != len(e.filters) {
+		return fmt.Errorf("equalizer: %d states for %d bands", len(states), len(e.filters))
+	}
+
+	for i, filter := range e.filters {
+		filter.SetState(states[i])
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package equalizer
+
+// Stereo applies the same filter to the left and the right channels. Each channel has its own state variables, so one
+// filter does not have to be created for each channel.
+type Stereo struct {
+	left  *Filter
+	right *Filter
+}
+
+// NewStereo returns the stereo filter which has the design of the filter, e.g. NewStereo(NewLowPass(44100, 1000, 0.707)).
+//
+// Parameters:
+//
+//     - filter ... Filter whose design and coefficients are copied to both channels. It is not changed.
+//
+// NOTE: The state variables start from zero. Use NewMultiChannel for more channels or the NEON kernel on arm64.
+func NewStereo(filter *Filter) *Stereo {
+	left := *filter
+	right := *filter
+
+	left.Reset()
+	right.Reset()
+
+	return &Stereo{
+		left:  &left,
+		right: &right,
+	}
+}
+
+// Left returns the filter of the left channel, e.g. to save its state with State.
+func (s *Stereo) Left() *Filter {
+	return s.left
+}
+
+// Right returns the filter of the right channel.
+func (s *Stereo) Right() *Filter {
+	return s.right
+}
+
+// SetFrequency redesigns both channels with the new frequency. The state variables are preserved.
+func (s *Stereo) SetFrequency(frequency float64) {
+	s.left.SetFrequency(frequency)
+	s.right.SetFrequency(frequency)
+}
+
+// SetQ redesigns both channels with the new Q value, or the new band width. The state variables are preserved.
+func (s *Stereo) SetQ(q float64) {
+	s.left.SetQ(q)
+	s.right.SetQ(q)
+}
+
+// SetGain redesigns both channels with the new gain in dB. The state variables are preserved.
+func (s *Stereo) SetGain(gain float64) {
+	s.left.SetGain(gain)
+	s.right.SetGain(gain)
+}
+
+// Apply applies the filter to the sample of each channel and returns the values.
+func (s *Stereo) Apply(left, right float64) (float64, float64) {
+	return s.left.Apply(left), s.right.Apply(right)
+}
+
+// ProcessInterleaved applies the filter to the interleaved samples, L R L R ..., in place. The incomplete frame at the end is not processed.
+func (s *Stereo) ProcessInterleaved(buffer []float64) {
+	for i := 0; i+1 < len(buffer); i += 2 {
+		buffer[i] = s.left.Apply(buffer[i])
+		buffer[i+1] = s.right.Apply(buffer[i+1])
+	}
+}
+
+// ProcessPlanar applies the filter to the buffer of each channel in place. The buffers may have the different lengths.
+func (s *Stereo) ProcessPlanar(left, right []float64) {
+	s.left.ProcessBuffer(left)
+	s.right.ProcessBuffer(right)
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz, which is the same for both channels.
+func (s *Stereo) FrequencyResponse(frequency float64) complex128 {
+	return s.left.FrequencyResponse(frequency)
+}
+
+// Reset clears the state variables of both channels.
+func (s *Stereo) Reset() {
+	s.left.Reset()
+	s.right.Reset()
+}
@@ -0,0 +1,52 @@
+package equalizer
+
+// NewThiran returns the Thiran all-pass fractional delay filter as the cascade of the second order sections.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - delay ... Delay in samples. e.g. 2.3
+//     - order ... Order of the all-pass filter. e.g. 2
+//
+// NOTE: delay should be between order-0.5 and order+0.5 for the flat group delay. The filter is unstable when delay is less than order-1.
+// For the long delay, combine the integer delay line and the low order Thiran filter.
+func NewThiran(sampleRate, delay float64, order int) []*Filter {
+	if order < 1 {
+		return nil
+	}
+
+	// a[k] = (-1)^k * C(N, k) * prod_{n=0}^{N} (D - N + n) / (D - N + k + n)
+	a := make([]float64, order+1)
+	binomial := 1.0
+
+	for k := 0; k <= order; k++ {
+		if k > 0 {
+			binomial = binomial * float64(order-k+1) / float64(k)
+		}
+
+		a[k] = binomial
+
+		if k%2 == 1 {
+			a[k] = -a[k]
+		}
+		for n := 0; n <= order; n++ {
+			a[k] *= (delay - float64(order) + float64(n)) / (delay - float64(order) + float64(k) + float64(n))
+		}
+	}
+
+	factors, linear := quadraticFactors(polynomialRoots(a))
+	filters := make([]*Filter, len(factors))
+
+	// Each factor forms the all-pass section whose numerator is the reversed denominator.
+	for i, f := range factors {
+		if linear && i == len(factors)-1 {
+			filters[i] = NewCustom(sampleRate, f[0], 1.0, 0.0, 1.0, f[0], 0.0)
+
+			continue
+		}
+
+		filters[i] = NewCustom(sampleRate, f[1], f[0], 1.0, 1.0, f[0], f[1])
+	}
+
+	return filters
+}
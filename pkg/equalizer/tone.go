@@ -0,0 +1,83 @@
+package equalizer
+
+import "math"
+
+// ToneControl corner frequencies in Hz.
+const (
+	ToneBassFrequency   = 100.0
+	ToneMidFrequency    = 1000.0
+	ToneTrebleFrequency = 10000.0
+)
+
+// ToneControl is the three-band bass, mid and treble tone control with the fixed corner frequencies.
+type ToneControl struct {
+	bass   *Filter
+	mid    *Filter
+	treble *Filter
+}
+
+// NewToneControl returns the tone control with flat response. Bass is the low-shelf at 100 Hz, mid is the peaking at 1 kHz and treble is the high-shelf at 10 kHz.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//
+// NOTE: The treble frequency is lowered when it is too close to the Nyquist frequency.
+func NewToneControl(sampleRate float64) *ToneControl {
+	treble := math.Min(ToneTrebleFrequency, 0.4*sampleRate)
+
+	return &ToneControl{
+		bass:   NewLowShelf(sampleRate, ToneBassFrequency, 0.707, 0.0),
+		mid:    NewPeaking(sampleRate, ToneMidFrequency, 2.0, 0.0),
+		treble: NewHighShelf(sampleRate, treble, 0.707, 0.0),
+	}
+}
+
+// Bass returns the bass gain in dB.
+func (t *ToneControl) Bass() float64 {
+	return t.bass.Gain()
+}
+
+// SetBass sets the bass gain in dB.
+func (t *ToneControl) SetBass(gain float64) {
+	t.bass.SetGain(gain)
+}
+
+// Mid returns the mid gain in dB.
+func (t *ToneControl) Mid() float64 {
+	return t.mid.Gain()
+}
+
+// SetMid sets the mid gain in dB.
+func (t *ToneControl) SetMid(gain float64) {
+	t.mid.SetGain(gain)
+}
+
+// Treble returns the treble gain in dB.
+func (t *ToneControl) Treble() float64 {
+	return t.treble.Gain()
+}
+
+// SetTreble sets the treble gain in dB.
+func (t *ToneControl) SetTreble(gain float64) {
+	t.treble.SetGain(gain)
+}
+
+// Apply applies the tone control and returns the value.
+func (t *ToneControl) Apply(input float64) float64 {
+	return t.treble.Apply(t.mid.Apply(t.bass.Apply(input)))
+}
+
+// ProcessBuffer applies the tone control to the buffer in place.
+func (t *ToneControl) ProcessBuffer(buffer []float64) {
+	t.bass.ProcessBuffer(buffer)
+	t.mid.ProcessBuffer(buffer)
+	t.treble.ProcessBuffer(buffer)
+}
+
+// Reset clears the state variables.
+func (t *ToneControl) Reset() {
+	t.bass.Reset()
+	t.mid.Reset()
+	t.treble.Reset()
+}
@@ -0,0 +1,85 @@
+package equalizer
+
+import "math"
+
+// TruePeakMeter measures the true peak of ITU-R BS.1770, the peak of the oversampled signal which includes the peaks
+// between the samples. The boost of the equalizer often creates such peaks which clip after the conversion to analog or the lossy encoding.
+type TruePeakMeter struct {
+	interpolator *Interpolator
+	buffer       []float64
+	peak         float64
+}
+
+// NewTruePeakMeter returns the true-peak meter. The signal is oversampled 4 times up to 48 kHz, twice up to 96 kHz and not above.
+func NewTruePeakMeter(sampleRate float64) *TruePeakMeter {
+	factor := 4
+
+	switch {
+	case sampleRate > 96000.0:
+		factor = 1
+	case sampleRate > 48000.0:
+		factor = 2
+	}
+
+	interpolator := NewInterpolator(factor, 12*factor+1)
+
+	return &TruePeakMeter{
+		interpolator: interpolator,
+		buffer:       make([]float64, factor),
+	}
+}
+
+// Apply measures the input and returns it as it is, so the meter can be placed in the Chain.
+func (t *TruePeakMeter) Apply(input float64) float64 {
+	t.peak = math.Max(t.peak, math.Abs(input))
+	t.interpolator.push(input, t.buffer)
+
+	for _, value := range t.buffer {
+		t.peak = math.Max(t.peak, math.Abs(value))
+	}
+
+	return input
+}
+
+// ProcessBuffer measures the buffer without modifying it.
+func (t *TruePeakMeter) ProcessBuffer(buffer []float64) {
+	for _, sample := range buffer {
+		t.Apply(sample)
+	}
+}
+
+// Peak returns the true peak in dBTP since the start or Reset. It returns -Inf for the silence.
+//
+// NOTE: The peak between the last few samples is measured after the following samples arrive. Call Flush at the end of the signal.
+func (t *TruePeakMeter) Peak() float64 {
+	return 20.0 * math.Log10(t.peak)
+}
+
+// Flush feeds the zeros which push the last samples through the oversampling filter and returns the true peak in dBTP.
+func (t *TruePeakMeter) Flush() float64 {
+	factor := t.interpolator.Factor()
+
+	for i := 0; i < (t.interpolator.Latency()+factor-1)/factor; i++ {
+		t.interpolator.push(0.0, t.buffer)
+
+		for _, value := range t.buffer {
+			t.peak = math.Max(t.peak, math.Abs(value))
+		}
+	}
+
+	return t.Peak()
+}
+
+// Reset clears the peak and the oversampling filter.
+func (t *TruePeakMeter) Reset() {
+	t.interpolator.Reset()
+	t.peak = 0.0
+}
+
+// TruePeak returns the true peak of the samples in dBTP. See TruePeakMeter.
+func TruePeak(samples []float64, sampleRate float64) float64 {
+	meter := NewTruePeakMeter(sampleRate)
+	meter.ProcessBuffer(samples)
+
+	return meter.Flush()
+}
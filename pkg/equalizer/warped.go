@@ -0,0 +1,208 @@
+package equalizer
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// WarpedFilter is the biquad filter whose delays are replaced with the first-order allpass filters,
+// (z^-1 - lambda) / (1 - lambda z^-1). The positive lambda stretches the low frequencies over the wider range of the
+// prototype, so the sub-bass filter at 96 or 192 kHz is designed with the poles far from z = 1 and processed without
+// the loss of the precision which the plain biquad suffers there.
+type WarpedFilter struct {
+	name       FilterName
+	sampleRate float64
+	frequency  float64
+	q          float64
+	gain       float64
+	lambda     float64
+
+	// prototype is designed at the warped frequencies.
+	prototype *Filter
+
+	// states of the allpass filters
+	s1 float64
+	s2 float64
+}
+
+// NewWarped returns the warped filter which has the response of the filter of the name at the frequency.
+//
+// Parameters:
+//
+//     - name ... Filter name, e.g. LowShelf.
+//     - sampleRate ... sample rate in Hz. e.g. 192000.0
+//     - frequency ... Cut off or center frequency in Hz.
+//     - q ... Q value, or the band width in octaves for the band-pass, band-reject and peaking filters.
+//     - gain ... Gain in dB.
+//     - lambda ... Warping factor between -1 and 1. e.g. WarpFor(sampleRate, frequency)
+//
+// NOTE: The band width in octaves is kept by warping the band edges. The Q value is not changed, so the shape around the
+// cut off is close but not the same as the plain biquad. The unknown filter name passes the signal through.
+func NewWarped(name FilterName, sampleRate, frequency, q, gain, lambda float64) *WarpedFilter {
+	w := &WarpedFilter{
+		name:       name,
+		sampleRate: sampleRate,
+		lambda:     lambda,
+	}
+
+	w.design(frequency, q, gain)
+
+	return w
+}
+
+// WarpFor returns the warping factor which maps the frequency to the eighth of the sample rate, where the biquad is
+// designed with the good precision, e.g. about 0.98 for 200 Hz at 192 kHz. It is limited to 0.99 for the lower frequencies.
+func WarpFor(sampleRate, frequency float64) float64 {
+	target := 2.0 * math.Pi / 8.0
+	w := 2.0 * math.Pi * frequency / sampleRate
+	lower, upper := -0.99, 0.99
+
+	// The warped frequency increases with lambda, so it is found by the bisection.
+	for i := 0; i < 60; i++ {
+		lambda := (lower + upper) / 2.0
+
+		if warpFrequency(w, lambda) < target {
+			lower = lambda
+		} else {
+			upper = lambda
+		}
+	}
+
+	return (lower + upper) / 2.0
+}
+
+// warpFrequency returns the angular frequency of the prototype which appears at w.
+func warpFrequency(w, lambda float64) float64 {
+	return w + 2.0*math.Atan(lambda*math.Sin(w)/(1.0-lambda*math.Cos(w)))
+}
+
+// design designs the prototype at the warped frequency and band width.
+func (w *WarpedFilter) design(frequency, q, gain float64) {
+	w.frequency = frequency
+	w.q = q
+	w.gain = gain
+
+	warp := func(f float64) float64 {
+		return warpFrequency(2.0*math.Pi*f/w.sampleRate, w.lambda) * w.sampleRate / (2.0 * math.Pi)
+	}
+
+	warpedFrequency := warp(frequency)
+	warpedQ := q
+
+	switch w.name {
+	case BandPass, BandReject, Peaking:
+		lower := warp(frequency * math.Pow(2.0, -q/2.0))
+		upper := warp(math.Min(frequency*math.Pow(2.0, q/2.0), 0.499*w.sampleRate))
+
+		warpedFrequency = math.Sqrt(lower * upper)
+		warpedQ = math.Log2(upper / lower)
+	}
+
+	prototype := design(w.name, w.sampleRate, math.Min(warpedFrequency, 0.499*w.sampleRate), warpedQ, gain)
+
+	if prototype == nil {
+		prototype = NewCustom(w.sampleRate, 1.0, 0.0, 0.0, 1.0, 0.0, 0.0)
+	}
+
+	w.prototype = prototype
+}
+
+// Name returns the filter name.
+func (w *WarpedFilter) Name() FilterName {
+	return w.name
+}
+
+// Lambda returns the warping factor.
+func (w *WarpedFilter) Lambda() float64 {
+	return w.lambda
+}
+
+// Frequency returns the cut off or center frequency in Hz.
+func (w *WarpedFilter) Frequency() float64 {
+	return w.frequency
+}
+
+// SetFrequency redesigns the filter with the new frequency. The state variables are preserved.
+func (w *WarpedFilter) SetFrequency(frequency float64) {
+	w.design(frequency, w.q, w.gain)
+}
+
+// Q returns the Q value or the band width.
+func (w *WarpedFilter) Q() float64 {
+	return w.q
+}
+
+// SetQ redesigns the filter with the new Q value or band width. The state variables are preserved.
+func (w *WarpedFilter) SetQ(q float64) {
+	w.design(w.frequency, q, w.gain)
+}
+
+// Gain returns the gain in dB.
+func (w *WarpedFilter) Gain() float64 {
+	return w.gain
+}
+
+// SetGain redesigns the filter with the new gain in dB. The state variables are preserved.
+func (w *WarpedFilter) SetGain(gain float64) {
+	w.design(w.frequency, w.q, gain)
+}
+
+// Apply applies the filter and returns the value.
+func (w *WarpedFilter) Apply(input float64) float64 {
+	p := w.prototype
+	lambda := w.lambda
+
+	// The allpass filters pass -lambda times the input without the delay, so the delay-free loop is solved for the
+	// internal value v of the direct form II.
+	v := (input - p.a1*w.s1 + p.a2*lambda*w.s1 - p.a2*w.s2) / (1.0 - p.a1*lambda + p.a2*lambda*lambda)
+	u1 := -lambda*v + w.s1
+	u2 := -lambda*u1 + w.s2
+
+	w.s1 = v + lambda*u1
+	w.s2 = u1 + lambda*u2
+
+	return p.b0*v + p.b1*u1 + p.b2*u2
+}
+
+// ProcessBuffer applies the filter to the buffer in place.
+func (w *WarpedFilter) ProcessBuffer(buffer []float64) {
+	for i := range buffer {
+		buffer[i] = w.Apply(buffer[i])
+	}
+}
+
+// Reset clears the state variables.
+func (w *WarpedFilter) Reset() {
+	w.s1 = 0.0
+	w.s2 = 0.0
+}
+
+// FrequencyResponse returns the complex frequency response at the frequency in Hz.
+func (w *WarpedFilter) FrequencyResponse(frequency float64) complex128 {
+	p := w.prototype
+	z1 := cmplx.Exp(complex(0.0, -2.0*math.Pi*frequency/w.sampleRate))
+	lambda := complex(w.lambda, 0.0)
+	d := (z1 - lambda) / (1.0 - lambda*z1)
+
+	numerator := complex(p.b0, 0.0) + complex(p.b1, 0.0)*d + complex(p.b2, 0.0)*d*d
+	denominator := 1.0 + complex(p.a1, 0.0)*d + complex(p.a2, 0.0)*d*d
+
+	return numerator / denominator
+}
+
+// Biquad returns the plain biquad filter which has the same response, e.g. to export the coefficients. It is processed
+// with the precision of the plain biquad.
+func (w *WarpedFilter) Biquad() *Filter {
+	p := w.prototype
+	l := w.lambda
+
+	// Substituting the allpass into b0 + b1 D + b2 D^2 and multiplying by (1 - lambda z^-1)^2 gives the biquad again.
+	expand := func(c0, c1, c2 float64) (float64, float64, float64) {
+		return c0 - l*c1 + l*l*c2, -2.0*l*c0 + (1.0+l*l)*c1 - 2.0*l*c2, l*l*c0 - l*c1 + c2
+	}
+
+	b0, b1, b2 := expand(p.b0, p.b1, p.b2)
+	a0, a1, a2 := expand(1.0, p.a1, p.a2)
+
+	return NewCustom(w.sampleRate, b0, b1, b2, a0, a1, a2)
+}
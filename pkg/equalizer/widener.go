@@ -0,0 +1,97 @@
+package equalizer
+
+import "math"
+
+// MidSide encodes the left and right samples to the mid and side samples.
+func MidSide(left, right float64) (mid, side float64) {
+	return (left + right) / 2.0, (left - right) / 2.0
+}
+
+// LeftRight decodes the mid and side samples to the left and right samples.
+func LeftRight(mid, side float64) (left, right float64) {
+	return mid + side, mid - side
+}
+
+// StereoWidener widens the stereo image by processing the side channel only.
+// The side channel below the mono frequency is removed, so the bass stays mono.
+type StereoWidener struct {
+	width     float64
+	shelf     *Filter
+	monoPass  [2]*Filter
+	monoBelow bool
+}
+
+// NewStereoWidener returns the stereo widener of the width 1.0 and the flat shelf. When monoFrequency is greater than 0,
+// the side channel below it is removed from the start, so the bass is mono even before the width or the shelf gain is set.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - monoFrequency ... The side channel below this frequency in Hz is removed. 0 disables the mono protection. e.g. 120.0
+//     - shelfFrequency ... Corner frequency of the high-shelf applied to the side channel in Hz. e.g. 4000.0
+func NewStereoWidener(sampleRate, monoFrequency, shelfFrequency float64) *StereoWidener {
+	const q = 1.0 / math.Sqrt2
+
+	w := &StereoWidener{
+		width:     1.0,
+		shelf:     NewHighShelf(sampleRate, shelfFrequency, q, 0.0),
+		monoBelow: monoFrequency > 0.0,
+	}
+
+	if w.monoBelow {
+		w.monoPass[0] = NewHighPass(sampleRate, monoFrequency, q)
+		w.monoPass[1] = NewHighPass(sampleRate, monoFrequency, q)
+	}
+
+	return w
+}
+
+// Width returns the broadband gain of the side channel in dB.
+func (w *StereoWidener) Width() float64 {
+	return 20.0 * math.Log10(w.width)
+}
+
+// SetWidth sets the broadband gain of the side channel in dB. Positive value widens and negative value narrows the stereo image.
+func (w *StereoWidener) SetWidth(gain float64) {
+	w.width = math.Pow(10.0, gain/20.0)
+}
+
+// ShelfGain returns the gain of the high-shelf applied to the side channel in dB.
+func (w *StereoWidener) ShelfGain() float64 {
+	return w.shelf.Gain()
+}
+
+// SetShelfGain sets the gain of the high-shelf applied to the side channel in dB.
+func (w *StereoWidener) SetShelfGain(gain float64) {
+	w.shelf.SetGain(gain)
+}
+
+// Apply applies the stereo widener to the left and right samples.
+func (w *StereoWidener) Apply(left, right float64) (float64, float64) {
+	mid, side := MidSide(left, right)
+
+	side = w.shelf.Apply(side) * w.width
+
+	if w.monoBelow {
+		side = w.monoPass[1].Apply(w.monoPass[0].Apply(side))
+	}
+
+	return LeftRight(mid, side)
+}
+
+// ProcessBuffer applies the stereo widener to the left and right buffers in place. The buffers must have the same length.
+func (w *StereoWidener) ProcessBuffer(left, right []float64) {
+	for i := range left {
+		left[i], right[i] = w.Apply(left[i], right[i])
+	}
+}
+
+// Reset clears the state variables.
+func (w *StereoWidener) Reset() {
+	w.shelf.Reset()
+
+	if w.monoBelow {
+		w.monoPass[0].Reset()
+		w.monoPass[1].Reset()
+	}
+}
@@ -0,0 +1,20 @@
+// Package generator provides the test signal generators, e.g. for measuring the systems and validating the filters.
+package generator
+
+import "math"
+
+// fade applies the raised cosine fade-in and fade-out in place. The lengths are given in samples.
+func fade(samples []float64, fadeIn, fadeOut int) {
+	if fadeIn > len(samples)/2 {
+		fadeIn = len(samples) / 2
+	}
+	if fadeOut > len(samples)/2 {
+		fadeOut = len(samples) / 2
+	}
+	for i := 0; i < fadeIn; i++ {
+		samples[i] *= 0.5 - 0.5*math.Cos(math.Pi*float64(i)/float64(fadeIn))
+	}
+	for i := 0; i < fadeOut; i++ {
+		samples[len(samples)-1-i] *= 0.5 - 0.5*math.Cos(math.Pi*float64(i)/float64(fadeOut))
+	}
+}
@@ -0,0 +1,88 @@
+package generator
+
+import "math/rand"
+
+// NoiseColor represents the spectral slope of the noise.
+type NoiseColor int
+
+// NoiseColor constants are noise colors.
+const (
+	// WhiteNoise has the flat spectrum.
+	WhiteNoise NoiseColor = iota
+
+	// PinkNoise falls by 3 dB per octave.
+	PinkNoise
+
+	// BrownNoise falls by 6 dB per octave.
+	BrownNoise
+)
+
+// Noise generates the noise. The same seed generates the same samples.
+type Noise struct {
+	color  NoiseColor
+	random *rand.Rand
+
+	// pink noise filter state (Paul Kellet's refined method)
+	pink [7]float64
+
+	// brown noise integrator state
+	brown float64
+}
+
+// NewNoise returns the noise generator.
+//
+// Parameters:
+//
+//     - color ... WhiteNoise, PinkNoise or BrownNoise.
+//     - seed ... Seed of the random number generator.
+//
+// NOTE: The samples are approximately between -1.0 and 1.0.
+func NewNoise(color NoiseColor, seed int64) *Noise {
+	return &Noise{
+		color:  color,
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// GenerateNoise returns the noise of the length in samples.
+func GenerateNoise(color NoiseColor, length int, seed int64) []float64 {
+	samples := make([]float64, length)
+
+	NewNoise(color, seed).Fill(samples)
+
+	return samples
+}
+
+// Next returns the next sample.
+func (n *Noise) Next() float64 {
+	white := n.random.Float64()*2.0 - 1.0
+
+	switch n.color {
+	case PinkNoise:
+		p := &n.pink
+		p[0] = 0.99886*p[0] + white*0.0555179
+		p[1] = 0.99332*p[1] + white*0.0750759
+		p[2] = 0.96900*p[2] + white*0.1538520
+		p[3] = 0.86650*p[3] + white*0.3104856
+		p[4] = 0.55000*p[4] + white*0.5329522
+		p[5] = -0.7616*p[5] - white*0.0168980
+		output := p[0] + p[1] + p[2] + p[3] + p[4] + p[5] + p[6] + white*0.5362
+		p[6] = white * 0.115926
+
+		return output * 0.11
+	case BrownNoise:
+		// The leak keeps the integrator from drifting away.
+		n.brown = 0.998*n.brown + 0.025*white
+
+		return n.brown
+	}
+
+	return white
+}
+
+// Fill fills the samples with the noise.
+func (n *Noise) Fill(samples []float64) {
+	for i := range samples {
+		samples[i] = n.Next()
+	}
+}
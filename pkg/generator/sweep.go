@@ -0,0 +1,94 @@
+package generator
+
+import "math"
+
+// SweepMode represents how the frequency of the sweep changes.
+type SweepMode int
+
+// SweepMode constants are sweep modes.
+const (
+	ExponentialSweep SweepMode = iota
+	LinearSweep
+)
+
+// defaultFade is the fade-in and fade-out time in seconds used by GenerateSweep.
+const defaultFade = 0.02
+
+// Sweep describes the sine sweep (chirp).
+type Sweep struct {
+	SampleRate     float64
+	StartFrequency float64
+	EndFrequency   float64
+
+	// Duration is the length of the sweep in seconds.
+	Duration float64
+
+	Mode SweepMode
+
+	// FadeIn and FadeOut are the length of the raised cosine fades in seconds.
+	FadeIn  float64
+	FadeOut float64
+
+	// Amplitude is the peak amplitude. 0 means 1.0.
+	Amplitude float64
+}
+
+// GenerateSweep returns the exponential sweep with 20 ms fades.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - f0 ... Start frequency in Hz. e.g. 20.0
+//     - f1 ... End frequency in Hz. e.g. 20000.0
+//     - duration ... Length in seconds. e.g. 10.0
+func GenerateSweep(sampleRate, f0, f1, duration float64) []float64 {
+	return Sweep{
+		SampleRate:     sampleRate,
+		StartFrequency: f0,
+		EndFrequency:   f1,
+		Duration:       duration,
+		Mode:           ExponentialSweep,
+		FadeIn:         defaultFade,
+		FadeOut:        defaultFade,
+	}.Generate()
+}
+
+// Generate returns the samples of the sweep.
+func (s Sweep) Generate() []float64 {
+	n := int(s.Duration * s.SampleRate)
+	samples := make([]float64, n)
+	amplitude := s.Amplitude
+
+	if amplitude == 0.0 {
+		amplitude = 1.0
+	}
+	for i := range samples {
+		samples[i] = amplitude * math.Sin(s.phase(float64(i)/s.SampleRate))
+	}
+
+	fade(samples, int(s.FadeIn*s.SampleRate), int(s.FadeOut*s.SampleRate))
+
+	return samples
+}
+
+// Frequency returns the instantaneous frequency in Hz at the time in seconds.
+func (s Sweep) Frequency(t float64) float64 {
+	if s.Mode == LinearSweep {
+		return s.StartFrequency + (s.EndFrequency-s.StartFrequency)*t/s.Duration
+	}
+
+	return s.StartFrequency * math.Exp(t/s.Duration*math.Log(s.EndFrequency/s.StartFrequency))
+}
+
+// phase returns the instantaneous phase in radians at the time in seconds.
+func (s Sweep) phase(t float64) float64 {
+	f0, f1, d := s.StartFrequency, s.EndFrequency, s.Duration
+
+	if s.Mode == LinearSweep {
+		return 2.0 * math.Pi * (f0*t + (f1-f0)*t*t/(2.0*d))
+	}
+
+	rate := math.Log(f1 / f0)
+
+	return 2.0 * math.Pi * f0 * d / rate * (math.Exp(t/d*rate) - 1.0)
+}
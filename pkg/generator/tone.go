@@ -0,0 +1,125 @@
+package generator
+
+import "math"
+
+// Waveform represents the waveform of the tone.
+type Waveform int
+
+// Waveform constants are waveforms.
+const (
+	Sine Waveform = iota
+	Square
+	Sawtooth
+)
+
+// Tone describes the periodic signal.
+type Tone struct {
+	Waveform  Waveform
+	Frequency float64
+
+	// Amplitude is the peak amplitude.
+	Amplitude float64
+
+	// Phase is the initial phase in radians.
+	Phase float64
+}
+
+// Oscillator generates the tone sample by sample. The square and sawtooth waves are band-limited with PolyBLEP, so they do not alias audibly.
+type Oscillator struct {
+	tone  Tone
+	step  float64
+	phase float64
+}
+
+// NewOscillator returns the oscillator.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 48000.0
+//     - tone ... Tone to generate.
+func NewOscillator(sampleRate float64, tone Tone) *Oscillator {
+	phase := math.Mod(tone.Phase/(2.0*math.Pi), 1.0)
+
+	if phase < 0.0 {
+		phase += 1.0
+	}
+
+	return &Oscillator{
+		tone:  tone,
+		step:  tone.Frequency / sampleRate,
+		phase: phase,
+	}
+}
+
+// Next returns the next sample.
+func (o *Oscillator) Next() float64 {
+	phase := o.phase
+	value := 0.0
+
+	switch o.tone.Waveform {
+	case Square:
+		value = 1.0
+
+		if phase >= 0.5 {
+			value = -1.0
+		}
+
+		value += polyBLEP(phase, o.step) - polyBLEP(math.Mod(phase+0.5, 1.0), o.step)
+	case Sawtooth:
+		value = 2.0*phase - 1.0 - polyBLEP(phase, o.step)
+	default:
+		value = math.Sin(2.0 * math.Pi * phase)
+	}
+
+	o.phase += o.step
+	o.phase -= math.Floor(o.phase)
+
+	return o.tone.Amplitude * value
+}
+
+// Fill fills the samples with the tone.
+func (o *Oscillator) Fill(samples []float64) {
+	for i := range samples {
+		samples[i] = o.Next()
+	}
+}
+
+// polyBLEP returns the correction of the discontinuity at the phase 0.
+func polyBLEP(phase, step float64) float64 {
+	switch {
+	case phase < step:
+		t := phase / step
+
+		return t + t - t*t - 1.0
+	case phase > 1.0-step:
+		t := (phase - 1.0) / step
+
+		return t*t + t + t + 1.0
+	}
+
+	return 0.0
+}
+
+// GenerateTone returns the tone of the length in samples.
+func GenerateTone(sampleRate float64, tone Tone, length int) []float64 {
+	samples := make([]float64, length)
+
+	NewOscillator(sampleRate, tone).Fill(samples)
+
+	return samples
+}
+
+// GenerateMultitone returns the sum of the tones of the length in samples, e.g. the multitone test signal or the intermodulation test signal.
+func GenerateMultitone(sampleRate float64, tones []Tone, length int) []float64 {
+	samples := make([]float64, length)
+
+	for _, tone := range tones {
+		o := NewOscillator(sampleRate, tone)
+
+		for i := range samples {
+			samples[i] += o.Next()
+		}
+	}
+
+	return samples
+}
@@ -0,0 +1,265 @@
+// Package midi maps the MIDI control change messages to the parameters of the parametric equalizers, so the hardware
+// controller can drive the live equalizer. The messages are read by any MIDI library, e.g. gitlab.com/gomidi/midi, and
+// passed to Mapper as the raw bytes.
+package midi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// ErrInvalidMapping is returned when the mapping does not fit the equalizers.
+var ErrInvalidMapping = errors.New("midi: invalid mapping")
+
+// Curve is the curve from the controller value to the parameter.
+type Curve int
+
+// Curve constants.
+const (
+	// Linear is suitable for the gain.
+	Linear Curve = iota
+
+	// Logarithmic is suitable for the frequency and Q, so each step of the knob changes the same ratio.
+	Logarithmic
+)
+
+// Mapping binds one control change to one parameter of the band.
+type Mapping struct {
+	// Channel is the MIDI channel from 1 to 16. 0 accepts every channel.
+	Channel int
+
+	// Controller is the control change number from 0 to 127.
+	Controller int
+
+	// Band is the index of the band in the equalizers.
+	Band int
+
+	// Parameter is the frequency, Q or gain of the band.
+	Parameter equalizer.Parameter
+
+	// Min and Max are the values of the parameter at the controller values 0 and 127. Max can be less than Min to invert the knob.
+	Min float64
+	Max float64
+
+	Curve Curve
+
+	// Smoothing is the time constant in which the parameter follows the controller, so the steps of 7 bit do not zipper. 0 follows immediately.
+	Smoothing time.Duration
+}
+
+// value returns the parameter value of the controller value.
+func (m Mapping) value(controller int) float64 {
+	v := float64(controller) / 127.0
+
+	if m.Curve == Logarithmic {
+		return m.Min * math.Pow(m.Max/m.Min, v)
+	}
+
+	return m.Min + v*(m.Max-m.Min)
+}
+
+// target is the smoothed state of one mapping.
+type target struct {
+	current float64
+	target  float64
+	active  bool
+}
+
+// Mapper applies the control changes to the equalizers. The control changes are received on any goroutine,
+// and the parameters are changed by Advance on the audio goroutine.
+type Mapper struct {
+	sampleRate float64
+	mappings   []Mapping
+	equalizers []*equalizer.ParametricEQ
+
+	mu      sync.Mutex
+	targets []target
+}
+
+// NewMapper returns the mapper.
+//
+// Parameters:
+//
+//     - sampleRate ... sample rate in Hz. e.g. 44100.0
+//     - mappings ... Bindings of the control changes.
+//     - equalizers ... Equalizers of the channels which have the same bands, e.g. the left and the right.
+//
+// NOTE: The parameters start at the current values of the first equalizer and move when the controller is touched.
+func NewMapper(sampleRate float64, mappings []Mapping, equalizers ...*equalizer.ParametricEQ) (*Mapper, error) {
+	if len(equalizers) == 0 {
+		return nil, fmt.Errorf("%w: no equalizers", ErrInvalidMapping)
+	}
+
+	bands := equalizers[0].Bands()
+	m := &Mapper{
+		sampleRate: sampleRate,
+		mappings:   append([]Mapping(nil), mappings...),
+		equalizers: equalizers,
+		targets:    make([]target, len(mappings)),
+	}
+
+	for i, mapping := range mappings {
+		if err := validate(sampleRate, mapping, len(bands)); err != nil {
+			return nil, fmt.Errorf("mappings[%d]: %w", i, err)
+		}
+
+		value := parameter(bands[mapping.Band], mapping.Parameter)
+
+		m.targets[i] = target{current: value, target: value}
+	}
+
+	return m, nil
+}
+
+// validate returns the error when the mapping does not fit the bands.
+func validate(sampleRate float64, mapping Mapping, bands int) error {
+	if mapping.Channel < 0 || mapping.Channel > 16 {
+		return fmt.Errorf("%w: channel %d", ErrInvalidMapping, mapping.Channel)
+	}
+	if mapping.Controller < 0 || mapping.Controller > 127 {
+		return fmt.Errorf("%w: controller %d", ErrInvalidMapping, mapping.Controller)
+	}
+	if mapping.Band < 0 || mapping.Band >= bands {
+		return fmt.Errorf("%w: band %d does not exist", ErrInvalidMapping, mapping.Band)
+	}
+	if mapping.Curve == Logarithmic && (mapping.Min <= 0.0 || mapping.Max <= 0.0) {
+		return fmt.Errorf("%w: logarithmic range must be positive", ErrInvalidMapping)
+	}
+
+	switch mapping.Parameter {
+	case equalizer.FrequencyParameter:
+		for _, frequency := range []float64{mapping.Min, mapping.Max} {
+			if err := equalizer.CheckFrequency(sampleRate, frequency); err != nil {
+				return err
+			}
+		}
+	case equalizer.QParameter:
+		if mapping.Min <= 0.0 || mapping.Max <= 0.0 {
+			return fmt.Errorf("%w: q must be positive", ErrInvalidMapping)
+		}
+	case equalizer.GainParameter:
+	default:
+		return fmt.Errorf("%w: parameter %d", ErrInvalidMapping, mapping.Parameter)
+	}
+
+	return nil
+}
+
+// Handle handles the raw MIDI message and returns true when it is the control change bound to any parameter.
+// The other messages are ignored, so all messages from the device can be passed.
+func (m *Mapper) Handle(message []byte) bool {
+	if len(message) < 3 || message[0]&0xF0 != 0xB0 {
+		return false
+	}
+
+	return m.ControlChange(int(message[0]&0x0F)+1, int(message[1]&0x7F), int(message[2]&0x7F))
+}
+
+// ControlChange sets the target of the parameters bound to the control change and returns true when any is bound.
+//
+// Parameters:
+//
+//     - channel ... MIDI channel from 1 to 16.
+//     - controller ... Control change number from 0 to 127.
+//     - value ... Controller value from 0 to 127.
+func (m *Mapper) ControlChange(channel, controller, value int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	handled := false
+
+	for i, mapping := range m.mappings {
+		if mapping.Controller != controller || (mapping.Channel != 0 && mapping.Channel != channel) {
+			continue
+		}
+
+		m.targets[i].target = mapping.value(value)
+		m.targets[i].active = true
+		handled = true
+	}
+
+	return handled
+}
+
+// Value returns the current value of the parameter of the i-th mapping.
+func (m *Mapper) Value(i int) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.targets[i].current
+}
+
+// Advance moves the parameters toward the targets by the frames and applies them to the equalizers.
+// Call it from the goroutine which processes the equalizers, before processing each block of the frames.
+//
+// NOTE: The parameters are changed once per call, so keep the blocks short, e.g. a few milliseconds, for the smooth change.
+func (m *Mapper) Advance(frames int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, mapping := range m.mappings {
+		t := &m.targets[i]
+
+		if !t.active {
+			continue
+		}
+
+		t.current = m.step(mapping, t.current, t.target, frames)
+
+		// Snap to the target when the rest of the change is inaudible, so the filter is not designed again forever.
+		if math.Abs(t.target-t.current) <= 1e-4*math.Max(1.0, math.Abs(t.target)) {
+			t.current = t.target
+			t.active = false
+		}
+
+		for _, eq := range m.equalizers {
+			band := eq.Bands()[mapping.Band]
+
+			setParameter(&band, mapping.Parameter, t.current)
+			eq.SetBand(mapping.Band, band)
+		}
+	}
+}
+
+// step returns the value which follows the target by the one-pole smoothing over the frames.
+func (m *Mapper) step(mapping Mapping, current, target float64, frames int) float64 {
+	if mapping.Smoothing <= 0 {
+		return target
+	}
+
+	k := 1.0 - math.Exp(-float64(frames)/(mapping.Smoothing.Seconds()*m.sampleRate))
+
+	// The logarithmic parameters are smoothed in the log domain, so the frequency sweeps evenly across the octaves.
+	if mapping.Curve == Logarithmic && current > 0.0 {
+		return current * math.Pow(target/current, k)
+	}
+
+	return current + k*(target-current)
+}
+
+func parameter(band equalizer.Band, p equalizer.Parameter) float64 {
+	switch p {
+	case equalizer.FrequencyParameter:
+		return band.Frequency
+	case equalizer.QParameter:
+		return band.Q
+	default:
+		return band.Gain
+	}
+}
+
+func setParameter(band *equalizer.Band, p equalizer.Parameter, value float64) {
+	switch p {
+	case equalizer.FrequencyParameter:
+		band.Frequency = value
+	case equalizer.QParameter:
+		band.Q = value
+	default:
+		band.Gain = value
+	}
+}
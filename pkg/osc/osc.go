@@ -0,0 +1,217 @@
+// Package osc controls the parametric equalizers with Open Sound Control over UDP, e.g. from TouchOSC or the show-control systems.
+//
+// The following addresses are served. The values are the actual parameters, so set the ranges of the faders in the client,
+// e.g. -12 to 12 for the gain.
+//
+//	/eq/band/{i}/frequency ... Frequency in Hz of the i-th band, counted from 0.
+//	/eq/band/{i}/q         ... Q value of the i-th band.
+//	/eq/band/{i}/gain      ... Gain in dB of the i-th band.
+//	/eq/bypass             ... Bypass, true when the argument is T or not 0.
+//
+// The arguments of the type i, h, f and d are accepted as the numbers. The bundles are unpacked and applied immediately regardless of the time tag.
+package osc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrInvalidPacket is returned when the packet is not the valid OSC message or bundle.
+	ErrInvalidPacket = errors.New("osc: invalid packet")
+
+	// ErrUnknownAddress is returned when the address is not served.
+	ErrUnknownAddress = errors.New("osc: unknown address")
+)
+
+// Message is the OSC message. The arguments are int32, int64, float32, float64, string, []byte or bool.
+type Message struct {
+	Address   string
+	Arguments []interface{}
+}
+
+// Float returns the i-th argument as the number.
+func (m Message) Float(i int) (float64, error) {
+	if i >= len(m.Arguments) {
+		return 0.0, fmt.Errorf("%w: %s has no argument %d", ErrInvalidPacket, m.Address, i)
+	}
+
+	switch v := m.Arguments[i].(type) {
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1.0, nil
+		}
+
+		return 0.0, nil
+	default:
+		return 0.0, fmt.Errorf("%w: argument %d of %s is not the number", ErrInvalidPacket, i, m.Address)
+	}
+}
+
+// ParsePacket parses the message or the bundle and returns the messages in the order. The bundles are flattened.
+func ParsePacket(packet []byte) ([]Message, error) {
+	if bytes.HasPrefix(packet, []byte("#bundle\x00")) {
+		return parseBundle(packet)
+	}
+
+	message, err := parseMessage(packet)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []Message{message}, nil
+}
+
+// parseBundle parses "#bundle", the time tag and the elements prefixed with their sizes.
+func parseBundle(packet []byte) ([]Message, error) {
+	if len(packet) < 16 {
+		return nil, ErrInvalidPacket
+	}
+
+	var messages []Message
+
+	rest := packet[16:]
+
+	for len(rest) > 0 {
+		if len(rest) < 4 {
+			return nil, ErrInvalidPacket
+		}
+
+		size := int(binary.BigEndian.Uint32(rest))
+		rest = rest[4:]
+
+		if size < 0 || size > len(rest) || size%4 != 0 {
+			return nil, ErrInvalidPacket
+		}
+
+		elements, err := ParsePacket(rest[:size])
+
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, elements...)
+		rest = rest[size:]
+	}
+
+	return messages, nil
+}
+
+// parseMessage parses the address, the type tags and the arguments.
+func parseMessage(packet []byte) (Message, error) {
+	address, rest, err := readString(packet)
+
+	if err != nil || len(address) == 0 || address[0] != '/' {
+		return Message{}, ErrInvalidPacket
+	}
+
+	message := Message{Address: address}
+
+	// The old implementations omit the type tags when there are no arguments.
+	if len(rest) == 0 {
+		return message, nil
+	}
+
+	tags, rest, err := readString(rest)
+
+	if err != nil || len(tags) == 0 || tags[0] != ',' {
+		return Message{}, ErrInvalidPacket
+	}
+
+	for _, tag := range tags[1:] {
+		var value interface{}
+
+		switch tag {
+		case 'i', 'f':
+			if len(rest) < 4 {
+				return Message{}, ErrInvalidPacket
+			}
+
+			bits := binary.BigEndian.Uint32(rest)
+			rest = rest[4:]
+
+			if tag == 'i' {
+				value = int32(bits)
+			} else {
+				value = math.Float32frombits(bits)
+			}
+		case 'h', 'd', 't':
+			if len(rest) < 8 {
+				return Message{}, ErrInvalidPacket
+			}
+
+			bits := binary.BigEndian.Uint64(rest)
+			rest = rest[8:]
+
+			if tag == 'd' {
+				value = math.Float64frombits(bits)
+			} else {
+				value = int64(bits)
+			}
+		case 's', 'S':
+			var s string
+
+			if s, rest, err = readString(rest); err != nil {
+				return Message{}, err
+			}
+
+			value = s
+		case 'b':
+			if len(rest) < 4 {
+				return Message{}, ErrInvalidPacket
+			}
+
+			size := int(binary.BigEndian.Uint32(rest))
+			padded := (size + 3) &^ 3
+
+			if size < 0 || 4+padded > len(rest) {
+				return Message{}, ErrInvalidPacket
+			}
+
+			value = append([]byte(nil), rest[4:4+size]...)
+			rest = rest[4+padded:]
+		case 'T':
+			value = true
+		case 'F':
+			value = false
+		case 'N', 'I':
+			// Nil and Impulse have no data and are skipped.
+			continue
+		default:
+			return Message{}, fmt.Errorf("%w: unsupported type tag %q", ErrInvalidPacket, tag)
+		}
+
+		message.Arguments = append(message.Arguments, value)
+	}
+
+	return message, nil
+}
+
+// readString reads the null terminated string padded to the multiple of 4 bytes.
+func readString(b []byte) (string, []byte, error) {
+	end := bytes.IndexByte(b, 0)
+
+	if end < 0 {
+		return "", nil, ErrInvalidPacket
+	}
+
+	padded := (end + 4) &^ 3
+
+	if padded > len(b) {
+		return "", nil, ErrInvalidPacket
+	}
+
+	return string(b[:end]), b[padded:], nil
+}
@@ -0,0 +1,174 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// maxPacketSize is the largest UDP payload.
+const maxPacketSize = 65535
+
+// Server receives the OSC packets and changes the bands of the equalizers.
+type Server struct {
+	conn net.PacketConn
+
+	// mu guards the equalizers, so the audio goroutine processes them in Update while the packets are applied.
+	mu         sync.Mutex
+	equalizers []*equalizer.ParametricEQ
+	bypass     bool
+}
+
+// NewServer listens on the UDP address. Call Serve to receive the packets.
+//
+// Parameters:
+//
+//     - address ... UDP address to listen on. e.g. ":8000" which is the default of TouchOSC
+//     - equalizers ... Equalizers of the channels which have the same bands, e.g. the left and the right.
+func NewServer(address string, equalizers ...*equalizer.ParametricEQ) (*Server, error) {
+	conn, err := net.ListenPacket("udp", address)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		conn:       conn,
+		equalizers: equalizers,
+	}, nil
+}
+
+// Addr returns the address on which the server listens.
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Serve receives the packets until Close is called. The invalid packets and the unknown addresses are ignored,
+// because the clients send the controls of the other targets to the same port.
+func (s *Server) Serve() error {
+	buffer := make([]byte, maxPacketSize)
+
+	for {
+		n, _, err := s.conn.ReadFrom(buffer)
+
+		if err != nil {
+			return err
+		}
+
+		s.Handle(buffer[:n])
+	}
+}
+
+// Close stops Serve.
+func (s *Server) Close() error {
+	return s.conn.Close()
+}
+
+// Handle applies the packet. It returns the first error, but the valid messages in the bundle are applied anyway.
+// It is useful for the other transports, e.g. the OSC over TCP or WebSocket.
+func (s *Server) Handle(packet []byte) error {
+	messages, err := ParsePacket(packet)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var first error
+
+	for _, message := range messages {
+		if err := s.apply(message); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Update calls f while no packet is applied, so f can process the equalizers safely.
+func (s *Server) Update(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f()
+}
+
+// Bypass returns true when the client turned the bypass on. Check it in Update and skip the equalizers.
+func (s *Server) Bypass() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bypass
+}
+
+// apply applies the message to the equalizers.
+func (s *Server) apply(message Message) error {
+	parts := strings.Split(strings.TrimPrefix(message.Address, "/"), "/")
+
+	if len(parts) == 2 && parts[0] == "eq" && parts[1] == "bypass" {
+		value, err := message.Float(0)
+
+		if err != nil {
+			return err
+		}
+
+		// The filters are cleared when they are enabled again, because the state is stale.
+		if s.bypass && value == 0.0 {
+			for _, eq := range s.equalizers {
+				eq.Reset()
+			}
+		}
+
+		s.bypass = value != 0.0
+
+		return nil
+	}
+	if len(parts) != 4 || parts[0] != "eq" || parts[1] != "band" || len(s.equalizers) == 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownAddress, message.Address)
+	}
+
+	i, err := strconv.Atoi(parts[2])
+
+	if err != nil || i < 0 || i >= len(s.equalizers[0].Bands()) {
+		return fmt.Errorf("%w: %s", ErrUnknownAddress, message.Address)
+	}
+
+	value, err := message.Float(0)
+
+	if err != nil {
+		return err
+	}
+
+	band := s.equalizers[0].Bands()[i]
+
+	switch parts[3] {
+	case "frequency":
+		if err := equalizer.CheckFrequency(s.equalizers[0].SampleRate(), value); err != nil {
+			return err
+		}
+
+		band.Frequency = value
+	case "q":
+		if value <= 0.0 {
+			return fmt.Errorf("%w: q must be positive", ErrInvalidPacket)
+		}
+
+		band.Q = value
+	case "gain":
+		band.Gain = value
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAddress, message.Address)
+	}
+
+	for _, eq := range s.equalizers {
+		eq.SetBand(i, band)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package pcm
+
+// Int16ToFloat64 converts the int16 samples to float64 and returns the number of the samples.
+//
+// NOTE: dst must be at least as long as src. The same applies to the other converters.
+func Int16ToFloat64(dst []float64, src []int16) int {
+	for i, v := range src {
+		dst[i] = float64(v) / 32768.0
+	}
+
+	return len(src)
+}
+
+// Int24ToFloat64 converts the 24 bit samples stored in the lower bits of int32 to float64 and returns the number of the samples.
+func Int24ToFloat64(dst []float64, src []int32) int {
+	for i, v := range src {
+		dst[i] = float64(v) / 8388608.0
+	}
+
+	return len(src)
+}
+
+// Int32ToFloat64 converts the int32 samples to float64 and returns the number of the samples.
+func Int32ToFloat64(dst []float64, src []int32) int {
+	for i, v := range src {
+		dst[i] = float64(v) / 2147483648.0
+	}
+
+	return len(src)
+}
+
+// Float32ToFloat64 converts the float32 samples to float64 and returns the number of the samples.
+func Float32ToFloat64(dst []float64, src []float32) int {
+	for i, v := range src {
+		dst[i] = float64(v)
+	}
+
+	return len(src)
+}
+
+// Float64ToInt16 converts the samples to int16 and returns the number of the samples converted before the error.
+func Float64ToInt16(dst []int16, src []float64, policy ClipPolicy) (int, error) {
+	for i, value := range src {
+		v, err := quantize(value, 32768.0, policy)
+
+		if err != nil {
+			return i, err
+		}
+
+		dst[i] = int16(v)
+	}
+
+	return len(src), nil
+}
+
+// Float64ToInt24 converts the samples to the 24 bit integers stored in int32 and returns the number of the samples converted before the error.
+func Float64ToInt24(dst []int32, src []float64, policy ClipPolicy) (int, error) {
+	for i, value := range src {
+		v, err := quantize(value, 8388608.0, policy)
+
+		if err != nil {
+			return i, err
+		}
+
+		dst[i] = int32(v)
+	}
+
+	return len(src), nil
+}
+
+// Float64ToInt32 converts the samples to int32 and returns the number of the samples converted before the error.
+func Float64ToInt32(dst []int32, src []float64, policy ClipPolicy) (int, error) {
+	for i, value := range src {
+		v, err := quantize(value, 2147483648.0, policy)
+
+		if err != nil {
+			return i, err
+		}
+
+		dst[i] = int32(v)
+	}
+
+	return len(src), nil
+}
+
+// Float64ToFloat32 converts the samples to float32 and returns the number of the samples. The values are never clipped.
+func Float64ToFloat32(dst []float32, src []float64) int {
+	for i, value := range src {
+		dst[i] = float32(value)
+	}
+
+	return len(src)
+}
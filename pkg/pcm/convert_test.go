@@ -0,0 +1,127 @@
+package pcm
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIntegerRoundTrip(t *testing.T) {
+	samples := []float64{0.0, 0.5, -0.5, -1.0, 0.123456789, -0.987654321}
+
+	int16s := make([]int16, len(samples))
+	int24s := make([]int32, len(samples))
+	int32s := make([]int32, len(samples))
+	decoded := make([]float64, len(samples))
+
+	tests := []struct {
+		name      string
+		tolerance float64
+		convert   func() error
+	}{
+		{"int16", 1.0 / 65536.0, func() error {
+			if _, err := Float64ToInt16(int16s, samples, Fail); err != nil {
+				return err
+			}
+
+			Int16ToFloat64(decoded, int16s)
+
+			return nil
+		}},
+		{"int24", 1.0 / 16777216.0, func() error {
+			if _, err := Float64ToInt24(int24s, samples, Fail); err != nil {
+				return err
+			}
+
+			Int24ToFloat64(decoded, int24s)
+
+			return nil
+		}},
+		{"int32", 1.0 / 4294967296.0, func() error {
+			if _, err := Float64ToInt32(int32s, samples, Fail); err != nil {
+				return err
+			}
+
+			Int32ToFloat64(decoded, int32s)
+
+			return nil
+		}},
+	}
+
+	for _, test := range tests {
+		if err := test.convert(); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for i, sample := range samples {
+			if math.Abs(decoded[i]-sample) > test.tolerance {
+				t.Errorf("%s: sample %d is %v, want %v", test.name, i, decoded[i], sample)
+			}
+		}
+	}
+}
+
+func TestIntegerClipping(t *testing.T) {
+	samples := []float64{1.0, -1.0, 1.5, -1.5, math.NaN()}
+
+	int16s := make([]int16, len(samples))
+	int24s := make([]int32, len(samples))
+	int32s := make([]int32, len(samples))
+
+	if _, err := Float64ToInt16(int16s, samples, Clip); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Float64ToInt24(int24s, samples, Clip); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Float64ToInt32(int32s, samples, Clip); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int16{32767, -32768, 32767, -32768, 0} {
+		if int16s[i] != want {
+			t.Errorf("int16 of %v is %d, want %d", samples[i], int16s[i], want)
+		}
+	}
+	for i, want := range []int32{8388607, -8388608, 8388607, -8388608, 0} {
+		if int24s[i] != want {
+			t.Errorf("int24 of %v is %d, want %d", samples[i], int24s[i], want)
+		}
+	}
+	for i, want := range []int32{2147483647, -2147483648, 2147483647, -2147483648, 0} {
+		if int32s[i] != want {
+			t.Errorf("int32 of %v is %d, want %d", samples[i], int32s[i], want)
+		}
+	}
+
+	// Fail stops at +1.0, which is one step above the largest integer.
+	if n, err := Float64ToInt16(int16s, samples, Fail); n != 0 || !errors.Is(err, ErrClipped) {
+		t.Errorf("int16 with Fail converts %d samples and returns %v", n, err)
+	}
+	if n, err := Float64ToInt24(int24s, samples[1:], Fail); n != 1 || !errors.Is(err, ErrClipped) {
+		t.Errorf("int24 with Fail converts %d samples and returns %v", n, err)
+	}
+	if n, err := Float64ToInt32(int32s, []float64{-1.0, math.NaN()}, Fail); n != 2 || err != nil {
+		t.Errorf("int32 with Fail converts %d samples and returns %v", n, err)
+	}
+}
+
+func TestFloat32RoundTrip(t *testing.T) {
+	samples := []float64{0.0, 0.5, -1.0, 1.5, math.Inf(1)}
+	float32s := make([]float32, len(samples))
+	decoded := make([]float64, len(samples))
+
+	Float64ToFloat32(float32s, samples)
+	Float32ToFloat64(decoded, float32s)
+
+	for i, sample := range samples {
+		if decoded[i] != sample {
+			t.Errorf("sample %d is %v, want %v", i, decoded[i], sample)
+		}
+	}
+
+	// NaN is kept as it is, because the float formats are never clipped.
+	Float64ToFloat32(float32s, []float64{math.NaN()})
+
+	if !math.IsNaN(float64(float32s[0])) {
+		t.Errorf("NaN is converted to %v", float32s[0])
+	}
+}
@@ -0,0 +1,77 @@
+package pcm
+
+// Interleave writes the samples of the channels into dst frame by frame, e.g. L R L R, and returns the number of the frames.
+// The number of the frames is limited by len(dst)/len(src) and the shortest channel. It does not allocate.
+func Interleave(dst []float64, src [][]float64) int {
+	channels := len(src)
+
+	if channels == 0 {
+		return 0
+	}
+
+	frames := len(dst) / channels
+
+	for _, channel := range src {
+		if len(channel) < frames {
+			frames = len(channel)
+		}
+	}
+
+	switch channels {
+	case 1:
+		copy(dst, src[0][:frames])
+	case 2:
+		left, right := src[0][:frames], src[1][:frames]
+
+		for i := range left {
+			dst[2*i] = left[i]
+			dst[2*i+1] = right[i]
+		}
+	default:
+		for c, channel := range src {
+			for i, value := range channel[:frames] {
+				dst[i*channels+c] = value
+			}
+		}
+	}
+
+	return frames
+}
+
+// Deinterleave splits the interleaved samples into the channels and returns the number of the frames. The number of the
+// frames is limited by len(src)/len(dst) and the shortest channel. It does not allocate.
+func Deinterleave(dst [][]float64, src []float64) int {
+	channels := len(dst)
+
+	if channels == 0 {
+		return 0
+	}
+
+	frames := len(src) / channels
+
+	for _, channel := range dst {
+		if len(channel) < frames {
+			frames = len(channel)
+		}
+	}
+
+	switch channels {
+	case 1:
+		copy(dst[0], src[:frames])
+	case 2:
+		left, right := dst[0][:frames], dst[1][:frames]
+
+		for i := range left {
+			left[i] = src[2*i]
+			right[i] = src[2*i+1]
+		}
+	default:
+		for c, channel := range dst {
+			for i := range channel[:frames] {
+				channel[i] = src[i*channels+c]
+			}
+		}
+	}
+
+	return frames
+}
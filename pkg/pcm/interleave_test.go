@@ -0,0 +1,81 @@
+package pcm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    [][]float64
+		size   int
+		frames int
+		want   []float64
+	}{
+		{"mono", [][]float64{{1, 2, 3}}, 3, 3, []float64{1, 2, 3}},
+		{"stereo", [][]float64{{1, 2}, {-1, -2}}, 4, 2, []float64{1, -1, 2, -2}},
+		{"stereo of unequal length", [][]float64{{1, 2, 3}, {-1, -2}}, 6, 2, []float64{1, -1, 2, -2, 0, 0}},
+		{"three channels of unequal length", [][]float64{{1, 2}, {-1, -2, -3}, {10}}, 9, 1, []float64{1, -1, 10, 0, 0, 0, 0, 0, 0}},
+		{"short dst", [][]float64{{1, 2, 3}, {-1, -2, -3}}, 5, 2, []float64{1, -1, 2, -2, 0}},
+		{"no channel", nil, 4, 0, []float64{0, 0, 0, 0}},
+	}
+
+	for _, test := range tests {
+		dst := make([]float64, test.size)
+
+		if frames := Interleave(dst, test.src); frames != test.frames {
+			t.Errorf("%s: %d frames, want %d", test.name, frames, test.frames)
+		}
+		if !reflect.DeepEqual(dst, test.want) {
+			t.Errorf("%s: %v, want %v", test.name, dst, test.want)
+		}
+	}
+}
+
+func TestDeinterleave(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    []float64
+		sizes  []int
+		frames int
+		want   [][]float64
+	}{
+		{"mono", []float64{1, 2, 3}, []int{3}, 3, [][]float64{{1, 2, 3}}},
+		{"stereo", []float64{1, -1, 2, -2}, []int{2, 2}, 2, [][]float64{{1, 2}, {-1, -2}}},
+		{"stereo of unequal length", []float64{1, -1, 2, -2, 3, -3}, []int{3, 1}, 1, [][]float64{{1, 0, 0}, {-1}}},
+		{"three channels of unequal length", []float64{1, -1, 10, 2, -2, 20}, []int{2, 3, 2}, 2, [][]float64{{1, 2}, {-1, -2, 0}, {10, 20}}},
+		{"incomplete frame", []float64{1, -1, 2}, []int{2, 2}, 1, [][]float64{{1, 0}, {-1, 0}}},
+	}
+
+	for _, test := range tests {
+		dst := make([][]float64, len(test.sizes))
+
+		for i, size := range test.sizes {
+			dst[i] = make([]float64, size)
+		}
+		if frames := Deinterleave(dst, test.src); frames != test.frames {
+			t.Errorf("%s: %d frames, want %d", test.name, frames, test.frames)
+		}
+		if !reflect.DeepEqual(dst, test.want) {
+			t.Errorf("%s: %v, want %v", test.name, dst, test.want)
+		}
+	}
+
+	if frames := Deinterleave(nil, []float64{1, 2}); frames != 0 {
+		t.Errorf("no channel has %d frames", frames)
+	}
+}
+
+func TestInterleaveRoundTrip(t *testing.T) {
+	channels := [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}}
+	interleaved := make([]float64, 12)
+	Interleave(interleaved, channels)
+
+	result := [][]float64{make([]float64, 4), make([]float64, 4), make([]float64, 4)}
+	Deinterleave(result, interleaved)
+
+	if !reflect.DeepEqual(result, channels) {
+		t.Errorf("round trip is %v, want %v", result, channels)
+	}
+}
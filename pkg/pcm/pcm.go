@@ -0,0 +1,197 @@
+// Package pcm converts the PCM samples between the bytes, the integer types and float64 between -1.0 and 1.0.
+//
+// The integer samples are scaled by the power of 2, e.g. the int16 sample -32768 is -1.0 and 16384 is 0.5.
+// The 8 bit samples are unsigned as same as the WAV file.
+package pcm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrClipped is returned by the Fail policy when the sample is out of the range of the integer format.
+var ErrClipped = errors.New("pcm: sample is clipped")
+
+// ClipPolicy represents how the samples out of the range are encoded to the integers.
+type ClipPolicy int
+
+// ClipPolicy constants are the ways to treat the samples out of the range.
+const (
+	// Clip saturates the sample at the maximum or the minimum integer.
+	Clip ClipPolicy = iota
+
+	// Fail returns ErrClipped at the first sample out of the range. The samples before it are encoded.
+	Fail
+)
+
+// Format describes the encoding of one sample.
+type Format struct {
+	// BitsPerSample is 8, 16, 24 or 32 for the integers, and 32 or 64 for the floats.
+	BitsPerSample int
+	Float         bool
+
+	// Order is binary.LittleEndian or binary.BigEndian. nil means little endian.
+	Order binary.ByteOrder
+}
+
+// Common formats.
+var (
+	S16LE = Format{BitsPerSample: 16}
+	S24LE = Format{BitsPerSample: 24}
+	S32LE = Format{BitsPerSample: 32}
+	F32LE = Format{BitsPerSample: 32, Float: true}
+	S16BE = Format{BitsPerSample: 16, Order: binary.BigEndian}
+	S24BE = Format{BitsPerSample: 24, Order: binary.BigEndian}
+	S32BE = Format{BitsPerSample: 32, Order: binary.BigEndian}
+	F32BE = Format{BitsPerSample: 32, Float: true, Order: binary.BigEndian}
+)
+
+// Size returns the number of the bytes of one sample.
+func (f Format) Size() int {
+	return f.BitsPerSample / 8
+}
+
+// Validate returns the error when the format is not supported.
+func (f Format) Validate() error {
+	if f.Float && f.BitsPerSample != 32 && f.BitsPerSample != 64 {
+		return fmt.Errorf("pcm: %d bit float is not supported", f.BitsPerSample)
+	}
+	if !f.Float && f.BitsPerSample != 8 && f.BitsPerSample != 16 && f.BitsPerSample != 24 && f.BitsPerSample != 32 {
+		return fmt.Errorf("pcm: %d bit integer is not supported", f.BitsPerSample)
+	}
+
+	return nil
+}
+
+func (f Format) order() binary.ByteOrder {
+	if f.Order == nil {
+		return binary.LittleEndian
+	}
+
+	return f.Order
+}
+
+// Decode converts the bytes to the samples and returns the number of the samples. The incomplete sample at the end of src is ignored.
+//
+// NOTE: dst must be long enough to hold len(src)/Size() samples.
+func (f Format) Decode(dst []float64, src []byte) int {
+	size := f.Size()
+	n := len(src) / size
+
+	for i := 0; i < n; i++ {
+		dst[i] = f.DecodeSample(src[i*size : (i+1)*size])
+	}
+
+	return n
+}
+
+// DecodeSample converts the bytes of one sample to the value.
+func (f Format) DecodeSample(b []byte) float64 {
+	order := f.order()
+
+	switch {
+	case f.Float && f.BitsPerSample == 32:
+		return float64(math.Float32frombits(order.Uint32(b)))
+	case f.Float:
+		return math.Float64frombits(order.Uint64(b))
+	case f.BitsPerSample == 8:
+		return (float64(b[0]) - 128.0) / 128.0
+	case f.BitsPerSample == 16:
+		return float64(int16(order.Uint16(b))) / 32768.0
+	case f.BitsPerSample == 24:
+		return float64(int24(b, order)) / 8388608.0
+	}
+
+	return float64(int32(order.Uint32(b))) / 2147483648.0
+}
+
+// Encode converts the samples to the bytes and returns the number of the bytes written. The float formats are never clipped.
+//
+// NOTE: dst must be long enough to hold len(src)*Size() bytes.
+func (f Format) Encode(dst []byte, src []float64, policy ClipPolicy) (int, error) {
+	size := f.Size()
+
+	for i, value := range src {
+		if err := f.EncodeSample(dst[i*size:(i+1)*size], value, policy); err != nil {
+			return i * size, err
+		}
+	}
+
+	return len(src) * size, nil
+}
+
+// EncodeSample converts the value to the bytes of one sample.
+func (f Format) EncodeSample(b []byte, value float64, policy ClipPolicy) error {
+	order := f.order()
+
+	switch {
+	case f.Float && f.BitsPerSample == 32:
+		order.PutUint32(b, math.Float32bits(float32(value)))
+
+		return nil
+	case f.Float:
+		order.PutUint64(b, math.Float64bits(value))
+
+		return nil
+	}
+
+	scale := math.Ldexp(1.0, f.BitsPerSample-1)
+	v, err := quantize(value, scale, policy)
+
+	if err != nil {
+		return err
+	}
+
+	switch f.BitsPerSample {
+	case 8:
+		b[0] = byte(v + 128)
+	case 16:
+		order.PutUint16(b, uint16(v))
+	case 24:
+		putInt24(b, int32(v), order)
+	default:
+		order.PutUint32(b, uint32(v))
+	}
+
+	return nil
+}
+
+// quantize rounds the value multiplied by the scale to the integer between -scale and scale-1.
+func quantize(value, scale float64, policy ClipPolicy) (int64, error) {
+	v := math.Round(value * scale)
+
+	if math.IsNaN(v) {
+		v = 0.0
+	}
+	if v < -scale || v > scale-1.0 {
+		if policy == Fail {
+			return 0, fmt.Errorf("%w: %g", ErrClipped, value)
+		}
+
+		v = math.Max(-scale, math.Min(scale-1.0, v))
+	}
+
+	return int64(v), nil
+}
+
+// int24 returns the signed 24 bit integer.
+func int24(b []byte, order binary.ByteOrder) int32 {
+	if order == binary.BigEndian {
+		return int32(uint32(b[2])<<8|uint32(b[1])<<16|uint32(b[0])<<24) >> 8
+	}
+
+	return int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+}
+
+// putInt24 writes the signed 24 bit integer.
+func putInt24(b []byte, v int32, order binary.ByteOrder) {
+	if order == binary.BigEndian {
+		b[0], b[1], b[2] = byte(v>>16), byte(v>>8), byte(v)
+
+		return
+	}
+
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
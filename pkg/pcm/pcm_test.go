@@ -0,0 +1,188 @@
+package pcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		value  float64
+		bytes  []byte
+	}{
+		{"s16le", S16LE, 0.5, []byte{0x00, 0x40}},
+		{"s16be", S16BE, 0.5, []byte{0x40, 0x00}},
+		{"s16le negative", S16LE, -1.0, []byte{0x00, 0x80}},
+		{"s24le", S24LE, 0.5, []byte{0x00, 0x00, 0x40}},
+		{"s24be", S24BE, 0.5, []byte{0x40, 0x00, 0x00}},
+		{"s24le negative", S24LE, -1.0 / 8388608.0, []byte{0xff, 0xff, 0xff}},
+		{"s24be negative", S24BE, -0.5, []byte{0xc0, 0x00, 0x00}},
+		{"s32le", S32LE, -0.5, []byte{0x00, 0x00, 0x00, 0xc0}},
+		{"s32be", S32BE, -0.5, []byte{0xc0, 0x00, 0x00, 0x00}},
+		{"f32le", F32LE, 1.0, []byte{0x00, 0x00, 0x80, 0x3f}},
+		{"f32be", F32BE, 1.0, []byte{0x3f, 0x80, 0x00, 0x00}},
+		{"u8", Format{BitsPerSample: 8}, -1.0, []byte{0x00}},
+	}
+
+	for _, test := range tests {
+		b := make([]byte, test.format.Size())
+
+		if err := test.format.EncodeSample(b, test.value, Fail); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !bytes.Equal(b, test.bytes) {
+			t.Errorf("%s: %v is encoded to % x, want % x", test.name, test.value, b, test.bytes)
+		}
+		if value := test.format.DecodeSample(test.bytes); value != test.value {
+			t.Errorf("%s: % x is decoded to %v, want %v", test.name, test.bytes, value, test.value)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	formats := []Format{
+		{BitsPerSample: 8},
+		S16LE, S16BE, S24LE, S24BE, S32LE, S32BE, F32LE, F32BE,
+		{BitsPerSample: 64, Float: true},
+		{BitsPerSample: 64, Float: true, Order: binary.BigEndian},
+	}
+	samples := []float64{0.0, 0.25, -0.25, 0.99, -0.99, -1.0, 1e-3, -1e-3}
+
+	for _, format := range formats {
+		if err := format.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The rounding error is at most the half of the step of the integer format.
+		tolerance := math.Ldexp(1.0, -format.BitsPerSample)
+
+		if format.Float {
+			tolerance = 1e-7
+		}
+
+		encoded := make([]byte, (len(samples)+1)*format.Size()-1)
+		n, err := format.Encode(encoded, samples, Fail)
+
+		if err != nil {
+			t.Fatalf("%+v: %v", format, err)
+		}
+		if n != len(samples)*format.Size() {
+			t.Fatalf("%+v: %d bytes are written, want %d", format, n, len(samples)*format.Size())
+		}
+
+		// The incomplete sample at the end is ignored.
+		decoded := make([]float64, len(samples))
+
+		if n := format.Decode(decoded, encoded); n != len(samples) {
+			t.Fatalf("%+v: %d samples are decoded, want %d", format, n, len(samples))
+		}
+		for i, sample := range samples {
+			if math.Abs(decoded[i]-sample) > tolerance {
+				t.Errorf("%+v: sample %d is %v, want %v", format, i, decoded[i], sample)
+			}
+		}
+	}
+}
+
+func TestFormatClipping(t *testing.T) {
+	formats := []Format{{BitsPerSample: 8}, S16LE, S16BE, S24LE, S24BE, S32LE, S32BE}
+
+	for _, format := range formats {
+		// The largest integer is one step below 1.0.
+		largest := 1.0 - math.Ldexp(1.0, 1-format.BitsPerSample)
+		b := make([]byte, format.Size())
+
+		for _, test := range []struct {
+			value float64
+			want  float64
+		}{
+			{1.0, largest},
+			{2.0, largest},
+			{math.Inf(1), largest},
+			{-1.0, -1.0},
+			{-2.0, -1.0},
+			{math.Inf(-1), -1.0},
+			{math.NaN(), 0.0},
+		} {
+			if err := format.EncodeSample(b, test.value, Clip); err != nil {
+				t.Errorf("%+v: %v is not clipped: %v", format, test.value, err)
+			}
+			if value := format.DecodeSample(b); value != test.want {
+				t.Errorf("%+v: %v is clipped to %v, want %v", format, test.value, value, test.want)
+			}
+
+			// Fail accepts only the values in the range, and NaN is encoded as the silence.
+			err := format.EncodeSample(b, test.value, Fail)
+
+			if inRange := test.value == -1.0 || math.IsNaN(test.value); inRange != (err == nil) {
+				t.Errorf("%+v: Fail returns %v for %v", format, err, test.value)
+			}
+			if err != nil && !errors.Is(err, ErrClipped) {
+				t.Errorf("%+v: error is %v, want ErrClipped", format, err)
+			}
+		}
+	}
+
+	// The float formats are never clipped.
+	b := make([]byte, 4)
+
+	if err := F32LE.EncodeSample(b, 2.0, Fail); err != nil || F32LE.DecodeSample(b) != 2.0 {
+		t.Errorf("2.0 in f32le is %v, %v", F32LE.DecodeSample(b), err)
+	}
+}
+
+func TestFormatEncodeFail(t *testing.T) {
+	samples := []float64{0.5, -0.5, 1.5, 0.25}
+	b := make([]byte, len(samples)*S16LE.Size())
+	n, err := S16LE.Encode(b, samples, Fail)
+
+	if !errors.Is(err, ErrClipped) {
+		t.Fatalf("error is %v, want ErrClipped", err)
+	}
+	if n != 2*S16LE.Size() {
+		t.Errorf("%d bytes are written before the error, want %d", n, 2*S16LE.Size())
+	}
+}
+
+func TestInt24SignExtension(t *testing.T) {
+	for _, test := range []struct {
+		bytes []byte
+		want  int32
+	}{
+		{[]byte{0x00, 0x00, 0x00}, 0},
+		{[]byte{0xff, 0xff, 0x7f}, 8388607},
+		{[]byte{0x00, 0x00, 0x80}, -8388608},
+		{[]byte{0xff, 0xff, 0xff}, -1},
+		{[]byte{0x01, 0x00, 0x80}, -8388607},
+	} {
+		if v := int24(test.bytes, binary.LittleEndian); v != test.want {
+			t.Errorf("% x is %d, want %d", test.bytes, v, test.want)
+		}
+
+		reversed := []byte{test.bytes[2], test.bytes[1], test.bytes[0]}
+
+		if v := int24(reversed, binary.BigEndian); v != test.want {
+			t.Errorf("big endian % x is %d, want %d", reversed, v, test.want)
+		}
+
+		b := make([]byte, 3)
+		putInt24(b, test.want, binary.LittleEndian)
+
+		if !bytes.Equal(b, test.bytes) {
+			t.Errorf("%d is written as % x, want % x", test.want, b, test.bytes)
+		}
+	}
+}
+
+func TestFormatValidate(t *testing.T) {
+	for _, format := range []Format{{BitsPerSample: 12}, {BitsPerSample: 16, Float: true}, {}} {
+		if err := format.Validate(); err == nil {
+			t.Errorf("%+v is valid", format)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+// Package pipeline builds the whole processing graph, i.e. the input format, the channels, the chain of the stages and
+// the output format, from the YAML or JSON document, so the headless equalizer is configured without writing Go code.
+//
+// The document looks like the following. JSON is also accepted because it is the subset of YAML.
+//
+//	input:
+//	  type: raw        # raw or wav
+//	  format: s16le    # u8, s16le, s24le, s32le, f32le or f64le. Ignored for wav.
+//	  sampleRate: 48000
+//	  channels: 2
+//	stages:
+//	  - type: highpass # The filter flag names of the equalizer command are the stages of one filter.
+//	    frequency: 80
+//	  - type: parametric
+//	    filters:       # Same as the filters of the config file.
+//	      - type: peak
+//	        frequency: 2500
+//	        q: 1.4
+//	        gain: -3
+//	  - type: convolver
+//	    ir: room.wav   # Mono or one channel per channel.
+//	    blockSize: 512
+//	  - type: gain     # The output trim in dB.
+//	    gain: -1.5
+//	  - type: clip
+//	    mode: soft     # detect, soft or hard
+//	    knee: 0.9
+//	output:
+//	  type: wav
+//	  format: s16le    # The format of the input when omitted.
+//
+// Each channel is processed by its own copy of the stages.
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/wav"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxChannels is the largest number of the channels.
+const MaxChannels = 64
+
+// ErrInvalidConfig is returned when the document does not describe the valid pipeline.
+var ErrInvalidConfig = errors.New("pipeline: invalid config")
+
+// Config is the pipeline document.
+type Config struct {
+	Input  Input   `yaml:"input" json:"input"`
+	Stages []Stage `yaml:"stages" json:"stages"`
+	Output Output  `yaml:"output" json:"output"`
+}
+
+// Input describes the input stream.
+type Input struct {
+	// Type is "raw" for the headerless PCM or "wav". The default is raw.
+	Type string `yaml:"type" json:"type"`
+
+	// Format is the sample format of the raw input, e.g. "s16le".
+	Format string `yaml:"format" json:"format"`
+
+	// SampleRate and Channels are required for the wav input too, because the stages are designed before the input is
+	// opened. Run fails when the WAV header does not match.
+	SampleRate int `yaml:"sampleRate" json:"sampleRate"`
+	Channels   int `yaml:"channels" json:"channels"`
+}
+
+// Output describes the output stream. It has the sample rate and the channels of the input.
+type Output struct {
+	// Type is "raw" for the headerless PCM or "wav". The default is raw.
+	Type string `yaml:"type" json:"type"`
+
+	// Format is the sample format, e.g. "s16le". The default is the format of the input.
+	Format string `yaml:"format" json:"format"`
+}
+
+// Stage is one stage of the chain. The fields used depend on the type.
+type Stage struct {
+	// Type is "parametric", "convolver", "gain", "clip" or one of the filter flag names, e.g. "peak".
+	Type string `yaml:"type" json:"type"`
+
+	// Frequency, Q and Gain are of the filter stages. The omitted Q value is config.DefaultQ. Gain is also of the gain stage.
+	Frequency float64  `yaml:"frequency" json:"frequency,omitempty"`
+	Q         *float64 `yaml:"q" json:"q,omitempty"`
+	Gain      float64  `yaml:"gain" json:"gain,omitempty"`
+
+	// Filters are the bands of the parametric stage.
+	Filters []Filter `yaml:"filters" json:"filters,omitempty"`
+
+	// IR and BlockSize are of the convolver stage. The relative path of the WAV is resolved from the directory of the
+	// config file, or the current directory for FromConfig. The default block size is 512.
+	IR        string `yaml:"ir" json:"ir,omitempty"`
+	BlockSize int    `yaml:"blockSize" json:"blockSize,omitempty"`
+
+	// Mode and Knee are of the clip stage. The default mode is detect and the default knee is 0.9.
+	Mode string   `yaml:"mode" json:"mode,omitempty"`
+	Knee *float64 `yaml:"knee" json:"knee,omitempty"`
+}
+
+// Filter is one band of the parametric stage, which is the same as the filter of the config file of the equalizer
+// command. The type is one of the filter flag names, e.g. "peak", or the name registered by equalizer.Register.
+type Filter = config.Filter
+
+// Bands converts the filters to the bands, e.g. for the other front end which accepts the same filters. The omitted
+// Q value is 1/sqrt(2).
+func Bands(filters []Filter) ([]equalizer.Band, error) {
+	return config.Bands(filters)
+}
+
+// FromConfig reads the document and builds the pipeline. The errors tell the line of the invalid value, e.g.
+// "pipeline: invalid config: line 12: stages[1]: unknown type "peek"".
+func FromConfig(r io.Reader) (*Pipeline, error) {
+	data, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(data, "")
+}
+
+// Load reads the config file and builds the pipeline.
+func Load(path string) (*Pipeline, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := parse(data, filepath.Dir(path))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// parse builds the pipeline. dir is the directory from which the relative paths are resolved.
+func parse(data []byte, dir string) (*Pipeline, error) {
+	var (
+		c    Config
+		root yaml.Node
+	)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	// Report the misspelled keys instead of ignoring them.
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return build(c, dir, newLines(&root))
+}
+
+// build validates the config and builds the pipeline.
+func build(c Config, dir string, lines lines) (*Pipeline, error) {
+	input := c.Input
+
+	if input.Type == "" {
+		input.Type = "raw"
+	}
+	if input.Type != "raw" && input.Type != "wav" {
+		return nil, lines.errorf("input", "type", "unknown type %q", input.Type)
+	}
+	if _, ok := config.RawFormats[input.Format]; !ok && input.Type == "raw" {
+		return nil, lines.errorf("input", "format", "unknown format %q", input.Format)
+	}
+	if input.SampleRate <= 0 {
+		return nil, lines.errorf("input", "sampleRate", "sample rate must be positive")
+	}
+	if input.Channels <= 0 || input.Channels > MaxChannels {
+		return nil, lines.errorf("input", "channels", "channels must be between 1 and %d", MaxChannels)
+	}
+
+	output := c.Output
+
+	if output.Type == "" {
+		output.Type = "raw"
+	}
+	if output.Type != "raw" && output.Type != "wav" {
+		return nil, lines.errorf("output", "type", "unknown type %q", output.Type)
+	}
+	if _, ok := config.RawFormats[output.Format]; !ok && output.Format != "" {
+		return nil, lines.errorf("output", "format", "unknown format %q", output.Format)
+	}
+
+	p := &Pipeline{
+		Input:  input,
+		Output: output,
+		chains: make([]*equalizer.Chain, input.Channels),
+		config: c,
+		dir:    dir,
+	}
+
+	for i := range p.chains {
+		p.chains[i] = equalizer.NewChain()
+	}
+	for i, stage := range c.Stages {
+		processors, err := newStage(stage, float64(input.SampleRate), input.Channels, dir)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConfig, lines.at(i, fmt.Sprintf("stages[%d]: %v", i, err)))
+		}
+
+		for c, processor := range processors {
+			p.chains[c].Add(processor)
+		}
+	}
+
+	return p, nil
+}
+
+// newStage returns the processor of the stage for each channel.
+func newStage(stage Stage, sampleRate float64, channels int, dir string) ([]equalizer.Processor, error) {
+	processors := make([]equalizer.Processor, channels)
+
+	switch stage.Type {
+	case "parametric":
+		bands, err := config.Bands(stage.Filters)
+
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBands(sampleRate, bands); err != nil {
+			return nil, err
+		}
+
+		for c := range processors {
+			processors[c] = equalizer.NewParametricEQ(sampleRate, bands...)
+		}
+	case "convolver":
+		if stage.IR == "" {
+			return nil, fmt.Errorf("ir is required")
+		}
+
+		path := stage.IR
+
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+
+		blockSize := stage.BlockSize
+
+		if blockSize == 0 {
+			blockSize = 512
+		}
+		if blockSize < 0 {
+			return nil, fmt.Errorf("block size must be positive")
+		}
+
+		data, err := ioutil.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// The mono impulse response is loaded for each channel, because the convolver has the state.
+		for c := range processors {
+			convolvers, err := wav.NewConvolvers(bytes.NewReader(data), sampleRate, blockSize)
+
+			if err != nil {
+				return nil, err
+			}
+			if len(convolvers) != 1 && len(convolvers) != channels {
+				return nil, fmt.Errorf("impulse response has %d channels, but the input has %d", len(convolvers), channels)
+			}
+			if len(convolvers) == channels {
+				copy(processors, toProcessors(convolvers))
+
+				break
+			}
+
+			processors[c] = convolvers[0]
+		}
+	case "gain":
+		if math.IsNaN(stage.Gain) || math.IsInf(stage.Gain, 0) {
+			return nil, fmt.Errorf("gain must be finite")
+		}
+
+		for c := range processors {
+			processors[c] = equalizer.NewGain(sampleRate, stage.Gain)
+		}
+	case "clip":
+		modes := map[string]equalizer.ClipMode{
+			"":       equalizer.DetectClip,
+			"detect": equalizer.DetectClip,
+			"soft":   equalizer.SoftClip,
+			"hard":   equalizer.HardClip,
+		}
+
+		mode, ok := modes[stage.Mode]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown mode %q", stage.Mode)
+		}
+
+		knee := 0.9
+
+		if stage.Knee != nil {
+			knee = *stage.Knee
+		}
+		if knee < 0.0 || knee >= 1.0 {
+			return nil, fmt.Errorf("knee must be between 0 and 1")
+		}
+
+		for c := range processors {
+			processors[c] = equalizer.NewClipGuard(mode, knee)
+		}
+	default:
+		bands, err := config.Bands([]config.Filter{{Type: stage.Type, Frequency: stage.Frequency, Q: stage.Q, Gain: stage.Gain}})
+
+		if err != nil {
+			return nil, fmt.Errorf("unknown type %q", stage.Type)
+		}
+		if err := checkBands(sampleRate, bands); err != nil {
+			return nil, err
+		}
+
+		for c := range processors {
+			processors[c] = equalizer.NewParametricEQ(sampleRate, bands...)
+		}
+	}
+
+	return processors, nil
+}
+
+func toProcessors(convolvers []*equalizer.Convolver) []equalizer.Processor {
+	processors := make([]equalizer.Processor, len(convolvers))
+
+	for i, convolver := range convolvers {
+		processors[i] = convolver
+	}
+
+	return processors
+}
+
+// checkBands returns the error when the frequency of any band is not below the Nyquist frequency.
+func checkBands(sampleRate float64, bands []equalizer.Band) error {
+	for i, band := range bands {
+		if err := equalizer.CheckFrequency(sampleRate, band.Frequency); err != nil {
+			return fmt.Errorf("filters[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// lines finds the lines of the values in the document for the errors.
+type lines struct {
+	root *yaml.Node
+}
+
+func newLines(root *yaml.Node) lines {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	return lines{root: root}
+}
+
+// value returns the value of the key in the mapping node, or nil.
+func value(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// errorf returns the error of the field in the section with the line of the field, or of the section when the field is omitted.
+func (l lines) errorf(section, field, format string, args ...interface{}) error {
+	message := fmt.Sprintf("%s.%s: %s", section, field, fmt.Sprintf(format, args...))
+	node := value(l.root, section)
+
+	if v := value(node, field); v != nil {
+		node = v
+	}
+	if node == nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, message)
+	}
+
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidConfig, node.Line, message)
+}
+
+// at prefixes the message with the line of the i-th stage.
+func (l lines) at(i int, message string) string {
+	stages := value(l.root, "stages")
+
+	if stages == nil || stages.Kind != yaml.SequenceNode || i >= len(stages.Content) {
+		return message
+	}
+
+	return fmt.Sprintf("line %d: %s", stages.Content[i].Line, message)
+}
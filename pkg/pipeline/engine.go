@@ -0,0 +1,199 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Job is the file processed by the engine.
+type Job struct {
+	Input  string
+	Output string
+
+	// Pipeline is cloned for the job, so the jobs can share it.
+	Pipeline *Pipeline
+}
+
+// Result is the outcome of the job.
+type Result struct {
+	Job     Job
+	Elapsed time.Duration
+	Err     error
+}
+
+// Engine runs the independent work, i.e. the files or the channels, on the fixed number of the goroutines. Each worker
+// holds one block at a time, so the memory is bounded by the number of the workers regardless of the number of the jobs.
+type Engine struct {
+	// OnResult is called with the result of each job in the order of the completion, e.g. to report the progress.
+	// It is called on the worker goroutines, so it must be safe for the concurrent use.
+	OnResult func(result Result)
+
+	tasks chan func()
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewEngine starts the workers. Call Close to stop them.
+//
+// Parameters:
+//
+//     - workers ... Number of the goroutines. 0 uses the number of the CPUs.
+func NewEngine(workers int) *Engine {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	e := &Engine{
+		tasks: make(chan func()),
+	}
+
+	e.wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer e.wg.Done()
+
+			for task := range e.tasks {
+				task()
+			}
+		}()
+	}
+
+	return e
+}
+
+// Close stops the workers after the running work. The engine must not be used after Close.
+func (e *Engine) Close() {
+	e.once.Do(func() {
+		close(e.tasks)
+	})
+
+	e.wg.Wait()
+}
+
+// ProcessFiles processes the jobs on the workers and returns the results in the order of the jobs. The output of the
+// failed job is removed. When the ctx is canceled, the running jobs stop at the next block and the jobs not started fail
+// with the error of the ctx.
+func (e *Engine) ProcessFiles(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+
+	var wg sync.WaitGroup
+
+	for i := range jobs {
+		i := i
+
+		wg.Add(1)
+
+		task := func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := ctx.Err()
+
+			if err == nil {
+				err = processFile(ctx, jobs[i])
+			}
+
+			results[i] = Result{
+				Job:     jobs[i],
+				Elapsed: time.Since(start),
+				Err:     err,
+			}
+
+			if e.OnResult != nil {
+				e.OnResult(results[i])
+			}
+		}
+
+		select {
+		case e.tasks <- task:
+		case <-ctx.Done():
+			// The task was not taken, so it is run here to record the error.
+			task()
+		}
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// Process applies the chains of the pipeline to the interleaved samples in place like Pipeline.Process, but the
+// channels are processed on the workers at the same time.
+//
+// NOTE: Do not call it from the job of the same engine, because the job waits for the workers which it occupies.
+func (e *Engine) Process(p *Pipeline, samples []float64) {
+	p.deinterleave(samples)
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(p.chains))
+
+	for c := range p.chains {
+		c := c
+
+		e.tasks <- func() {
+			defer wg.Done()
+
+			p.chains[c].ProcessBuffer(p.channels[c])
+		}
+	}
+
+	wg.Wait()
+	p.interleave(samples)
+}
+
+// processFile runs the clone of the pipeline of the job from the input file to the output file.
+func processFile(ctx context.Context, job Job) error {
+	p, err := job.Pipeline.Clone()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", job.Input, err)
+	}
+
+	in, err := os.Open(job.Input)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(job.Output)
+
+	if err != nil {
+		return err
+	}
+	if err := p.Run(contextReader{ctx: ctx, r: in}, out); err != nil {
+		out.Close()
+		os.Remove(job.Output)
+
+		return fmt.Errorf("%s: %w", job.Input, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(job.Output)
+
+		return err
+	}
+
+	return nil
+}
+
+// contextReader fails the read when the ctx is canceled, so the pipeline stops at the next block.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(b []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(b)
+}
@@ -0,0 +1,208 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pcm"
+	"github.com/moutend/go-equalizer/pkg/pool"
+	"github.com/moutend/go-equalizer/pkg/wav"
+)
+
+// blockFrames is the number of the frames read at once by Run.
+const blockFrames = 1024
+
+// Pipeline is the processing graph built from the config.
+type Pipeline struct {
+	Input  Input
+	Output Output
+
+	chains   []*equalizer.Chain
+	channels [][]float64
+
+	// config and dir build the copy of the pipeline.
+	config Config
+	dir    string
+}
+
+// SampleRate returns the sample rate in Hz.
+func (p *Pipeline) SampleRate() float64 {
+	return float64(p.Input.SampleRate)
+}
+
+// Channels returns the number of the channels.
+func (p *Pipeline) Channels() int {
+	return len(p.chains)
+}
+
+// Chains returns the chain of each channel, e.g. to set the hooks.
+func (p *Pipeline) Chains() []*equalizer.Chain {
+	return p.chains
+}
+
+// Process applies the chains to the interleaved samples in place. The incomplete frame at the end is not processed.
+func (p *Pipeline) Process(samples []float64) {
+	p.deinterleave(samples)
+
+	for c, chain := range p.chains {
+		chain.ProcessBuffer(p.channels[c])
+	}
+
+	p.interleave(samples)
+}
+
+// deinterleave copies the complete frames of the samples to the buffer of each channel.
+func (p *Pipeline) deinterleave(samples []float64) {
+	frames := len(samples) / len(p.chains)
+
+	if len(p.channels) != len(p.chains) {
+		p.channels = make([][]float64, len(p.chains))
+	}
+	for c := range p.channels {
+		if cap(p.channels[c]) < frames {
+			p.channels[c] = make([]float64, frames)
+		}
+
+		p.channels[c] = p.channels[c][:frames]
+	}
+
+	pcm.Deinterleave(p.channels, samples)
+}
+
+// interleave copies the buffers of the channels back to the samples.
+func (p *Pipeline) interleave(samples []float64) {
+	pcm.Interleave(samples, p.channels)
+}
+
+// Clone builds the new pipeline from the same config, e.g. to process the other stream at the same time. The state
+// variables and the hooks are not copied.
+func (p *Pipeline) Clone() (*Pipeline, error) {
+	return build(p.config, p.dir, lines{})
+}
+
+// Reset clears the state variables of the chains.
+func (p *Pipeline) Reset() {
+	for _, chain := range p.chains {
+		chain.Reset()
+	}
+}
+
+// Latency returns the latency of the chains in samples.
+func (p *Pipeline) Latency() int {
+	return p.chains[0].Latency()
+}
+
+// Run reads the input stream from r, processes it and writes the output stream to w until the input ends.
+// The WAV output is written with the unknown sizes unless w is seekable, e.g. the file.
+func (p *Pipeline) Run(r io.Reader, w io.Writer) error {
+	return p.run(r, w, p.Process)
+}
+
+// run reads the input stream with the config of p and processes it by the process.
+func (p *Pipeline) run(r io.Reader, w io.Writer, process func(samples []float64)) error {
+	reader, err := p.openInput(r)
+
+	if err != nil {
+		return err
+	}
+
+	write, done, err := p.openOutput(w, reader.Format)
+
+	if err != nil {
+		return err
+	}
+
+	buffer := pool.GetFloat64s(blockFrames * p.Channels())
+
+	defer pool.PutFloat64s(buffer)
+
+	for {
+		n, err := reader.Read(buffer)
+
+		if n > 0 {
+			process(buffer[:n])
+
+			if err := write(buffer[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return done()
+}
+
+// openInput returns the reader of the input stream.
+func (p *Pipeline) openInput(r io.Reader) (*wav.Reader, error) {
+	if p.Input.Type == "raw" {
+		format := config.RawFormats[p.Input.Format]
+
+		format.SampleRate = p.Input.SampleRate
+		format.Channels = p.Input.Channels
+
+		return wav.NewRawReader(r, format)
+	}
+
+	reader, err := wav.NewReader(r)
+
+	if err != nil {
+		return nil, err
+	}
+	if reader.Format.SampleRate != p.Input.SampleRate || reader.Format.Channels != p.Input.Channels {
+		return nil, fmt.Errorf("pipeline: input is %d Hz %d channels, but the config is %d Hz %d channels", reader.Format.SampleRate, reader.Format.Channels, p.Input.SampleRate, p.Input.Channels)
+	}
+
+	return reader, nil
+}
+
+// openOutput returns the function which writes the samples and the function which completes the output.
+func (p *Pipeline) openOutput(w io.Writer, input wav.Format) (func([]float64) error, func() error, error) {
+	format := input
+
+	if p.Output.Format != "" {
+		format = config.RawFormats[p.Output.Format]
+		format.SampleRate = input.SampleRate
+		format.Channels = input.Channels
+	}
+	// The standard output is io.WriteSeeker even when it is the pipe, so check whether it really seeks.
+	if ws, ok := w.(io.WriteSeeker); ok && p.Output.Type == "wav" && seekable(ws) {
+		writer, err := wav.NewWriter(ws, format)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return writer.Write, writer.Close, nil
+	}
+	if p.Output.Type == "wav" {
+		header, err := wav.StreamHeader(format)
+
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := w.Write(header); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	writer, err := wav.NewRawWriter(w, format)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return writer.Write, func() error { return nil }, nil
+}
+
+func seekable(ws io.WriteSeeker) bool {
+	_, err := ws.Seek(0, io.SeekCurrent)
+
+	return err == nil
+}
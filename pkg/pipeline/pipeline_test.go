@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+input: {type: raw, format: f64le, sampleRate: 48000, channels: 2}
+stages:
+  - {type: highpass, frequency: 80}
+  - {type: peak, frequency: 1000, q: 1, gain: 3}
+  - {type: gain, gain: -1.5}
+`
+
+func newTestPipeline(tb testing.TB) *Pipeline {
+	p, err := FromConfig(strings.NewReader(testConfig))
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return p
+}
+
+// testSamples returns the interleaved stereo sine wave.
+func testSamples(frames int) []float64 {
+	samples := make([]float64, 2*frames)
+
+	for i := 0; i < frames; i++ {
+		samples[2*i] = 0.5 * math.Sin(2.0*math.Pi*440.0*float64(i)/48000.0)
+		samples[2*i+1] = samples[2*i]
+	}
+
+	return samples
+}
+
+func BenchmarkPipelineProcess(b *testing.B) {
+	p := newTestPipeline(b)
+	signal := testSamples(1024)
+	samples := make([]float64, len(signal))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(8 * len(samples)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		copy(samples, signal)
+		p.Process(samples)
+	}
+}
+
+func TestPipelineProcessAllocs(t *testing.T) {
+	p := newTestPipeline(t)
+	signal := testSamples(1024)
+	samples := make([]float64, len(signal))
+
+	// The first block allocates the buffers of the channels.
+	p.Process(samples)
+
+	n := testing.AllocsPerRun(100, func() {
+		copy(samples, signal)
+		p.Process(samples)
+	})
+
+	if n != 0 {
+		t.Errorf("Pipeline.Process allocates %v times", n)
+	}
+}
+
+func TestWatcherInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+
+	if err := ioutil.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWatcher(path, -time.Second, 0); err == nil {
+		t.Error("negative interval is accepted")
+	}
+
+	// The watcher of the interval 0 is only reloaded by Reload, so Watch returns instead of panicking.
+	w, err := NewWatcher(path, 0, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Watch(context.Background()); err == nil {
+		t.Error("Watch with the interval 0 returns nil")
+	}
+}
@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watcher reloads the config file when it is changed and swaps the running pipeline, so the headless equalizer is tuned
+// live by editing the file. The old and the new pipelines are crossfaded, so the swap does not click.
+type Watcher struct {
+	path      string
+	interval  time.Duration
+	crossfade int
+
+	// OnReload is called with the result of each reload, e.g. to log the invalid config. The old pipeline keeps running
+	// when the error is not nil. It is called on the goroutine of Watch or Reload.
+	OnReload func(err error)
+
+	mu       sync.Mutex
+	modTime  time.Time
+	size     int64
+	current  *Pipeline
+	pending  *Pipeline
+	previous *Pipeline
+	position int
+	buffer   []float64
+}
+
+// NewWatcher loads the config file and returns the watcher. Call Watch to reload the file on change.
+//
+// Parameters:
+//
+//     - path ... Path to the config file.
+//     - interval ... Interval to check the modification time of the file. e.g. time.Second, or 0 for the watcher which is only reloaded by Reload
+//     - crossfade ... Duration of the crossfade from the old pipeline to the new one. e.g. 50 * time.Millisecond
+//
+// NOTE: The reloaded config must have the same input and output as the first one, because the streams are already open.
+func NewWatcher(path string, interval, crossfade time.Duration) (*Watcher, error) {
+	if interval < 0 {
+		return nil, fmt.Errorf("pipeline: watch interval %v must not be negative", interval)
+	}
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := Load(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		path:      path,
+		interval:  interval,
+		crossfade: int(crossfade.Seconds() * p.SampleRate()),
+		modTime:   info.ModTime(),
+		size:      info.Size(),
+		current:   p,
+	}, nil
+}
+
+// Pipeline returns the running pipeline. The pipeline being reloaded is not returned until Process starts it.
+func (w *Watcher) Pipeline() *Pipeline {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.current
+}
+
+// Watch checks the file every interval and reloads it on change until the context is done. It returns the error
+// immediately when the interval is 0.
+func (w *Watcher) Watch(ctx context.Context) error {
+	if w.interval <= 0 {
+		return fmt.Errorf("pipeline: watch interval %v must be positive", w.interval)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(w.path)
+
+		// The editors replace the file by renaming, so the file can be missing for a moment.
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+			continue
+		}
+
+		w.modTime = info.ModTime()
+		w.size = info.Size()
+
+		w.Reload()
+	}
+}
+
+// Reload loads the file and makes Process swap the pipeline at the next block, e.g. on SIGHUP.
+func (w *Watcher) Reload() error {
+	p, err := Load(w.path)
+
+	if err == nil {
+		err = w.Swap(p)
+	}
+	if w.OnReload != nil {
+		w.OnReload(err)
+	}
+
+	return err
+}
+
+// Swap makes Process swap the pipeline at the next block. It fails when the input or the output is changed.
+func (w *Watcher) Swap(p *Pipeline) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if p.Input != w.current.Input {
+		return fmt.Errorf("%w: input cannot be changed while running", ErrInvalidConfig)
+	}
+	if p.Output != w.current.Output {
+		return fmt.Errorf("%w: output cannot be changed while running", ErrInvalidConfig)
+	}
+
+	w.pending = p
+
+	return nil
+}
+
+// Run reads the input stream from r, processes it by Process and writes the output stream to w until the input ends.
+// Run Watch on the other goroutine to reload the file.
+func (w *Watcher) Run(r io.Reader, wr io.Writer) error {
+	return w.Pipeline().run(r, wr, w.Process)
+}
+
+// Process applies the running pipeline to the interleaved samples in place. While the crossfade, the samples are
+// processed by both pipelines and the output moves linearly from the old one to the new one.
+func (w *Watcher) Process(samples []float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pending != nil {
+		// The swap during the crossfade drops the oldest pipeline and fades from the current one.
+		w.previous, w.current, w.pending = w.current, w.pending, nil
+		w.position = 0
+
+		if w.crossfade <= 0 {
+			w.previous = nil
+		}
+	}
+	if w.previous == nil {
+		w.current.Process(samples)
+
+		return
+	}
+	if cap(w.buffer) < len(samples) {
+		w.buffer = make([]float64, len(samples))
+	}
+
+	old := w.buffer[:len(samples)]
+
+	copy(old, samples)
+	w.previous.Process(old)
+	w.current.Process(samples)
+
+	channels := w.current.Channels()
+
+	for i := range samples {
+		frame := w.position + i/channels
+
+		if frame >= w.crossfade {
+			break
+		}
+
+		// The linear crossfade keeps the level, because both pipelines process the same input.
+		t := float64(frame) / float64(w.crossfade)
+		samples[i] = (1.0-t)*old[i] + t*samples[i]
+	}
+
+	w.position += len(samples) / channels
+
+	if w.position >= w.crossfade {
+		w.previous = nil
+	}
+}
@@ -0,0 +1,258 @@
+package plot
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+// anchor represents the horizontal alignment of the text.
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// stroke is the style of the line.
+type stroke struct {
+	color  color.RGBA
+	width  float64
+	dashed bool
+}
+
+// dashLength is the length of the dashes and the gaps of the dashed line in pixels.
+const dashLength = 6.0
+
+// canvas is the drawing surface shared by the PNG and SVG output.
+type canvas interface {
+	// rect fills the rectangle.
+	rect(x, y, width, height float64, c color.RGBA)
+
+	// polyline draws the connected line segments.
+	polyline(points [][2]float64, s stroke)
+
+	// text draws the text whose vertical center is y.
+	text(x, y float64, s string, c color.RGBA, a anchor)
+
+	// encode writes the drawing.
+	encode(w io.Writer) error
+}
+
+// rasterCanvas draws on the image and encodes it as PNG.
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+func newRasterCanvas(width, height int) *rasterCanvas {
+	return &rasterCanvas{
+		img: image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+}
+
+func (r *rasterCanvas) rect(x, y, width, height float64, c color.RGBA) {
+	x0, y0 := int(math.Round(x)), int(math.Round(y))
+	x1, y1 := int(math.Round(x+width)), int(math.Round(y+height))
+
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			r.blend(px, py, c, 1.0)
+		}
+	}
+}
+
+func (r *rasterCanvas) polyline(points [][2]float64, s stroke) {
+	// The coverage of the whole polyline is accumulated first, so the joints are not blended twice.
+	coverages := map[image.Point]float64{}
+	radius := s.width / 2.0
+
+	// distance along the polyline, which keeps the dash pattern continuous across the segments
+	distance := 0.0
+
+	for i := 1; i < len(points); i++ {
+		x0, y0 := points[i-1][0], points[i-1][1]
+		x1, y1 := points[i][0], points[i][1]
+		dx, dy := x1-x0, y1-y0
+		length := math.Hypot(dx, dy)
+		left := int(math.Floor(math.Min(x0, x1) - radius - 1.0))
+		right := int(math.Ceil(math.Max(x0, x1) + radius + 1.0))
+		top := int(math.Floor(math.Min(y0, y1) - radius - 1.0))
+		bottom := int(math.Ceil(math.Max(y0, y1) + radius + 1.0))
+
+		for py := top; py <= bottom; py++ {
+			for px := left; px <= right; px++ {
+				cx, cy := float64(px)+0.5, float64(py)+0.5
+
+				// Project the pixel center onto the segment.
+				t := 0.0
+
+				if length > 0.0 {
+					t = math.Max(0.0, math.Min(1.0, ((cx-x0)*dx+(cy-y0)*dy)/(length*length)))
+				}
+				if s.dashed && math.Mod(distance+t*length, 2.0*dashLength) >= dashLength {
+					continue
+				}
+
+				d := math.Hypot(cx-(x0+dx*t), cy-(y0+dy*t))
+				coverage := math.Min(1.0, radius+0.5-d)
+				point := image.Point{px, py}
+
+				if coverage > coverages[point] {
+					coverages[point] = coverage
+				}
+			}
+		}
+
+		distance += length
+	}
+
+	for point, coverage := range coverages {
+		r.blend(point.X, point.Y, s.color, coverage)
+	}
+}
+
+// blend mixes the color into the pixel by the coverage between 0 and 1.
+func (r *rasterCanvas) blend(x, y int, c color.RGBA, coverage float64) {
+	if !(image.Point{x, y}).In(r.img.Rect) {
+		return
+	}
+
+	alpha := coverage * float64(c.A) / 255.0
+	i := r.img.PixOffset(x, y)
+	pixel := r.img.Pix[i : i+4]
+	mix := func(dst, src uint8) uint8 {
+		return uint8(math.Round(float64(dst)*(1.0-alpha) + float64(src)*alpha))
+	}
+
+	pixel[0] = mix(pixel[0], c.R)
+	pixel[1] = mix(pixel[1], c.G)
+	pixel[2] = mix(pixel[2], c.B)
+	pixel[3] = 255
+}
+
+func (r *rasterCanvas) text(x, y float64, s string, c color.RGBA, a anchor) {
+	width := textWidth(s)
+
+	switch a {
+	case anchorMiddle:
+		x -= width / 2.0
+	case anchorEnd:
+		x -= width
+	}
+
+	left := int(math.Round(x))
+	top := int(math.Round(y - glyphHeight/2.0))
+
+	for _, character := range s {
+		bitmap := glyph(character)
+
+		for column, bits := range bitmap {
+			for row := 0; row < glyphHeight; row++ {
+				if bits&(1<<uint(row)) != 0 {
+					r.blend(left+column, top+row, c, 1.0)
+				}
+			}
+		}
+
+		left += glyphAdvance
+	}
+}
+
+func (r *rasterCanvas) encode(w io.Writer) error {
+	return png.Encode(w, r.img)
+}
+
+// vectorCanvas collects the SVG elements.
+type vectorCanvas struct {
+	width    int
+	height   int
+	elements []string
+}
+
+func newVectorCanvas(width, height int) *vectorCanvas {
+	return &vectorCanvas{
+		width:  width,
+		height: height,
+	}
+}
+
+func (v *vectorCanvas) rect(x, y, width, height float64, c color.RGBA) {
+	v.elements = append(v.elements, fmt.Sprintf(
+		`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`,
+		x, y, width, height, svgColor(c),
+	))
+}
+
+func (v *vectorCanvas) polyline(points [][2]float64, s stroke) {
+	var b strings.Builder
+
+	for i, point := range points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		fmt.Fprintf(&b, "%.2f,%.2f", point[0], point[1])
+	}
+
+	dash := ""
+
+	if s.dashed {
+		dash = fmt.Sprintf(` stroke-dasharray="%g %g"`, dashLength, dashLength)
+	}
+
+	v.elements = append(v.elements, fmt.Sprintf(
+		`<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"%s/>`,
+		b.String(), svgColor(s.color), s.width, dash,
+	))
+}
+
+func (v *vectorCanvas) text(x, y float64, s string, c color.RGBA, a anchor) {
+	textAnchor := "start"
+
+	switch a {
+	case anchorMiddle:
+		textAnchor = "middle"
+	case anchorEnd:
+		textAnchor = "end"
+	}
+
+	v.elements = append(v.elements, fmt.Sprintf(
+		`<text x="%.1f" y="%.1f" fill="%s" text-anchor="%s" dominant-baseline="central">%s</text>`,
+		x, y, svgColor(c), textAnchor, svgEscape(s),
+	))
+}
+
+func (v *vectorCanvas) encode(w io.Writer) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10">`+"\n", v.width, v.height, v.width, v.height)
+
+	for _, element := range v.elements {
+		b.WriteString(element)
+		b.WriteByte('\n')
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.Flush()
+}
+
+// svgColor returns the color in the SVG notation.
+func svgColor(c color.RGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", c.R, c.G, c.B, float64(c.A)/255.0)
+}
+
+// svgEscape escapes the characters which have the special meaning in XML.
+func svgEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
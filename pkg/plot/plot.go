@@ -0,0 +1,444 @@
+// Package plot renders the frequency response of the filters as PNG, SVG or text.
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"math/cmplx"
+)
+
+// Responder is implemented by the filters, the parametric equalizer and the chain.
+type Responder interface {
+	FrequencyResponse(frequency float64) complex128
+}
+
+// Format represents the output image format.
+type Format int
+
+// Format constants are output formats.
+const (
+	PNG Format = iota
+	SVG
+
+	// Text draws the magnitude with the ASCII characters for the terminal.
+	Text
+
+	// ANSI is the same as Text except the traces are colored with the ANSI escape sequences.
+	ANSI
+)
+
+// Trace is the frequency response drawn as one curve.
+type Trace struct {
+	// Name is shown in the legend. The legend is omitted when no trace has the name.
+	Name string
+
+	Responder Responder
+
+	// Color is the color of the curve. The zero value picks the color from the palette.
+	Color color.RGBA
+}
+
+// Options controls the plot.
+type Options struct {
+	Format Format
+
+	// Width and Height are the size of the image in pixels. The defaults are 800 and 450.
+	// For Text and ANSI, they are the number of the columns and the rows including the labels. The defaults are 80 and 24.
+	Width  int
+	Height int
+
+	// MinFrequency and MaxFrequency are the range of the logarithmic frequency axis in Hz. The defaults are 20 Hz and 20 kHz.
+	MinFrequency float64
+	MaxFrequency float64
+
+	// MinGain and MaxGain are the range of the gain axis in dB. When both are zero, the range is chosen from the curves.
+	MinGain float64
+	MaxGain float64
+
+	// Phase draws the phase in degrees as the dashed curve on the right axis. It is ignored by Text and ANSI.
+	Phase bool
+
+	Title string
+}
+
+// withDefaults returns the options whose zero fields are replaced with the defaults.
+func (o Options) withDefaults() Options {
+	text := o.Format == Text || o.Format == ANSI
+
+	if o.Width <= 0 {
+		o.Width = 800
+
+		if text {
+			o.Width = 80
+		}
+	}
+	if o.Height <= 0 {
+		o.Height = 450
+
+		if text {
+			o.Height = 24
+		}
+	}
+	if o.MinFrequency <= 0.0 {
+		o.MinFrequency = 20.0
+	}
+	if o.MaxFrequency <= o.MinFrequency {
+		o.MaxFrequency = math.Max(20000.0, 10.0*o.MinFrequency)
+	}
+
+	return o
+}
+
+// Colors of the plot.
+var (
+	backgroundColor = color.RGBA{255, 255, 255, 255}
+	majorGridColor  = color.RGBA{200, 200, 200, 255}
+	minorGridColor  = color.RGBA{235, 235, 235, 255}
+	textColor       = color.RGBA{51, 51, 51, 255}
+
+	// palette is the colors of the traces without the color.
+	palette = []color.RGBA{
+		{31, 119, 180, 255},
+		{255, 127, 14, 255},
+		{44, 160, 44, 255},
+		{214, 39, 40, 255},
+		{148, 103, 189, 255},
+		{140, 86, 75, 255},
+		{227, 119, 194, 255},
+		{127, 127, 127, 255},
+	}
+)
+
+// Response renders the frequency response of the responder and writes the image.
+//
+// Parameters:
+//
+//     - w ... Destination of the image.
+//     - responder ... Filter, chain or anything else which provides the frequency response.
+//     - options ... Plot options. The zero value writes PNG with the defaults.
+func Response(w io.Writer, responder Responder, options Options) error {
+	return Responses(w, []Trace{{Responder: responder}}, options)
+}
+
+// Responses renders the frequency responses of the traces in the same plot and writes the image. See Response for the options.
+func Responses(w io.Writer, traces []Trace, options Options) error {
+	options = options.withDefaults()
+
+	var c canvas
+
+	switch options.Format {
+	case PNG:
+		c = newRasterCanvas(options.Width, options.Height)
+	case SVG:
+		c = newVectorCanvas(options.Width, options.Height)
+	case Text, ANSI:
+		return writeText(w, traces, options)
+	default:
+		return fmt.Errorf("plot: unknown format %d", options.Format)
+	}
+
+	right := 20.0
+
+	if options.Phase {
+		right = 56.0
+	}
+
+	const left, top, bottom = 56.0, 30.0, 40.0
+
+	width := float64(options.Width) - left - right
+	height := float64(options.Height) - top - bottom
+
+	newPlot(traces, options, left, top, width, height).draw(c)
+
+	return c.encode(w)
+}
+
+// plot holds the layout and the sampled curves.
+type plot struct {
+	options Options
+	traces  []Trace
+
+	// plot area in pixels, or in characters for the text
+	left, top, width, height float64
+
+	frequencies []float64
+
+	// gains and phases are indexed by the trace and the frequency.
+	gains  [][]float64
+	phases [][]float64
+}
+
+// newPlot samples the curves at every pixel of the plot area.
+func newPlot(traces []Trace, options Options, left, top, width, height float64) *plot {
+	p := &plot{
+		options: options,
+		traces:  traces,
+		left:    left,
+		top:     top,
+		width:   width,
+		height:  height,
+	}
+
+	// One point per pixel including both edges is enough for the smooth curve.
+	points := int(math.Max(1.0, math.Round(width))) + 1
+	ratio := math.Pow(options.MaxFrequency/options.MinFrequency, 1.0/float64(points-1))
+
+	p.frequencies = make([]float64, points)
+
+	for i := range p.frequencies {
+		p.frequencies[i] = options.MinFrequency * math.Pow(ratio, float64(i))
+	}
+	for _, trace := range traces {
+		gains := make([]float64, points)
+		phases := make([]float64, points)
+
+		for i, frequency := range p.frequencies {
+			response := trace.Responder.FrequencyResponse(frequency)
+			gains[i] = 20.0 * math.Log10(cmplx.Abs(response))
+			phases[i] = cmplx.Phase(response) * 180.0 / math.Pi
+		}
+
+		p.gains = append(p.gains, gains)
+		p.phases = append(p.phases, phases)
+	}
+	if p.options.MinGain == 0.0 && p.options.MaxGain == 0.0 {
+		p.options.MinGain, p.options.MaxGain = p.gainRange()
+	}
+
+	return p
+}
+
+// gainRange returns the gain axis which covers the curves in 6 dB steps. The range is at least ±12 dB and at most 120 dB below the peak.
+func (p *plot) gainRange() (float64, float64) {
+	lower, upper := -12.0, 12.0
+	peak := math.Inf(-1)
+
+	for _, gains := range p.gains {
+		for _, gain := range gains {
+			peak = math.Max(peak, gain)
+		}
+	}
+	for _, gains := range p.gains {
+		for _, gain := range gains {
+			if math.IsInf(gain, 0) || math.IsNaN(gain) {
+				continue
+			}
+
+			lower = math.Min(lower, math.Max(gain, peak-120.0))
+			upper = math.Max(upper, gain)
+		}
+	}
+
+	return 6.0 * math.Floor(lower/6.0), 6.0 * math.Ceil(upper/6.0)
+}
+
+// x returns the horizontal position of the frequency.
+func (p *plot) x(frequency float64) float64 {
+	o := p.options
+	t := math.Log(frequency/o.MinFrequency) / math.Log(o.MaxFrequency/o.MinFrequency)
+
+	return p.left + t*p.width
+}
+
+// y returns the vertical position of the value on the axis between min and max. The value outside the axis is clamped to the edge.
+func (p *plot) y(value, min, max float64) float64 {
+	t := (value - min) / (max - min)
+
+	if math.IsNaN(t) {
+		t = 0.0
+	}
+
+	t = math.Max(0.0, math.Min(1.0, t))
+
+	return p.top + (1.0-t)*p.height
+}
+
+func (p *plot) draw(c canvas) {
+	o := p.options
+	bottom := p.top + p.height
+
+	c.rect(0.0, 0.0, float64(o.Width), float64(o.Height), backgroundColor)
+
+	// frequency grid
+	for _, tick := range frequencyTicks(o.MinFrequency, o.MaxFrequency) {
+		x := p.x(tick.frequency)
+
+		if !tick.major {
+			c.polyline([][2]float64{{x, p.top}, {x, bottom}}, stroke{color: minorGridColor, width: 1.0})
+
+			continue
+		}
+
+		c.polyline([][2]float64{{x, p.top}, {x, bottom}}, stroke{color: majorGridColor, width: 1.0})
+		c.text(x, bottom+10.0, formatFrequency(tick.frequency), textColor, anchorMiddle)
+	}
+
+	// gain grid
+	step := gainStep(o.MaxGain - o.MinGain)
+
+	for gain := math.Ceil(o.MinGain/step) * step; gain <= o.MaxGain; gain += step {
+		y := p.y(gain, o.MinGain, o.MaxGain)
+
+		c.polyline([][2]float64{{p.left, y}, {p.left + p.width, y}}, stroke{color: majorGridColor, width: 1.0})
+		c.text(p.left-6.0, y, fmt.Sprintf("%g", gain), textColor, anchorEnd)
+	}
+
+	// phase axis
+	if o.Phase {
+		for phase := -180.0; phase <= 180.0; phase += 90.0 {
+			c.text(p.left+p.width+6.0, p.y(phase, -180.0, 180.0), fmt.Sprintf("%g", phase), textColor, anchorStart)
+		}
+
+		c.text(float64(o.Width)-6.0, p.top-10.0, "Phase (deg)", textColor, anchorEnd)
+	}
+
+	c.text(p.left+p.width/2.0, bottom+26.0, "Frequency (Hz)", textColor, anchorMiddle)
+	c.text(6.0, p.top-10.0, "Gain (dB)", textColor, anchorStart)
+	c.text(p.left+p.width/2.0, p.top-10.0, o.Title, textColor, anchorMiddle)
+
+	// border
+	c.polyline([][2]float64{
+		{p.left, p.top},
+		{p.left + p.width, p.top},
+		{p.left + p.width, bottom},
+		{p.left, bottom},
+		{p.left, p.top},
+	}, stroke{color: textColor, width: 1.0})
+
+	for i := range p.traces {
+		s := stroke{color: p.color(i), width: 2.0}
+
+		if o.Phase {
+			// Break the curve where the phase wraps, so the wrap is not drawn as the vertical line.
+			for _, segment := range splitWraps(p.phases[i]) {
+				points := make([][2]float64, 0, segment[1]-segment[0])
+
+				for n := segment[0]; n < segment[1]; n++ {
+					points = append(points, [2]float64{p.x(p.frequencies[n]), p.y(p.phases[i][n], -180.0, 180.0)})
+				}
+
+				c.polyline(points, stroke{color: s.color, width: 1.0, dashed: true})
+			}
+		}
+
+		points := make([][2]float64, len(p.frequencies))
+
+		for n, frequency := range p.frequencies {
+			points[n] = [2]float64{p.x(frequency), p.y(p.gains[i][n], o.MinGain, o.MaxGain)}
+		}
+
+		c.polyline(points, s)
+	}
+
+	p.drawLegend(c)
+}
+
+// drawLegend draws the names of the traces at the top left corner of the plot area.
+func (p *plot) drawLegend(c canvas) {
+	width := 0.0
+	count := 0
+
+	for _, trace := range p.traces {
+		if trace.Name != "" {
+			width = math.Max(width, textWidth(trace.Name))
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+
+	const lineHeight = 14.0
+
+	x, y := p.left+8.0, p.top+8.0
+
+	c.rect(x, y, width+36.0, float64(count)*lineHeight+6.0, backgroundColor)
+
+	y += 3.0 + lineHeight/2.0
+
+	for i, trace := range p.traces {
+		if trace.Name == "" {
+			continue
+		}
+
+		c.polyline([][2]float64{{x + 4.0, y}, {x + 22.0, y}}, stroke{color: p.color(i), width: 2.0})
+		c.text(x+28.0, y, trace.Name, textColor, anchorStart)
+
+		y += lineHeight
+	}
+}
+
+// color returns the color of the trace.
+func (p *plot) color(i int) color.RGBA {
+	if c := p.traces[i].Color; c != (color.RGBA{}) {
+		return c
+	}
+
+	return palette[i%len(palette)]
+}
+
+// tick is the grid line on the frequency axis.
+type tick struct {
+	frequency float64
+	major     bool
+}
+
+// frequencyTicks returns the 1, 2, 5 multiples of the decades as the major ticks and the other multiples as the minor ticks.
+func frequencyTicks(min, max float64) []tick {
+	var ticks []tick
+
+	for decade := math.Floor(math.Log10(min)); decade <= math.Ceil(math.Log10(max)); decade++ {
+		for m := 1; m < 10; m++ {
+			frequency := float64(m) * math.Pow(10.0, decade)
+
+			if frequency < min*(1.0-1e-9) || frequency > max*(1.0+1e-9) {
+				continue
+			}
+
+			ticks = append(ticks, tick{
+				frequency: frequency,
+				major:     m == 1 || m == 2 || m == 5,
+			})
+		}
+	}
+
+	return ticks
+}
+
+// formatFrequency returns the label of the frequency, e.g. 500 and 2k.
+func formatFrequency(frequency float64) string {
+	if frequency >= 1000.0 {
+		return fmt.Sprintf("%gk", frequency/1000.0)
+	}
+
+	return fmt.Sprintf("%g", frequency)
+}
+
+// gainStep returns the spacing of the gain grid which draws at most 10 lines.
+func gainStep(span float64) float64 {
+	for _, step := range []float64{1.0, 2.0, 3.0, 6.0, 12.0, 24.0} {
+		if span/step <= 10.0 {
+			return step
+		}
+	}
+
+	return 6.0 * math.Ceil(span/60.0)
+}
+
+// splitWraps returns the index ranges of the phase between the wraps.
+func splitWraps(phases []float64) [][2]int {
+	var segments [][2]int
+
+	start := 0
+
+	for n := 1; n < len(phases); n++ {
+		if math.Abs(phases[n]-phases[n-1]) > 180.0 {
+			segments = append(segments, [2]int{start, n})
+			start = n
+		}
+	}
+
+	return append(segments, [2]int{start, len(phases)})
+}
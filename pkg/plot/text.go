@@ -0,0 +1,197 @@
+package plot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// textMarkers are the characters of the traces.
+var textMarkers = []byte{'*', '+', 'o', 'x', '#', '@', '%', '='}
+
+// ansiColors are the SGR color codes of the traces.
+var ansiColors = []int{34, 33, 32, 31, 35, 36, 90, 37}
+
+// cell is the character on the text canvas. The color is the ANSI color code, 0 is the default color.
+type cell struct {
+	char  byte
+	color int
+}
+
+// writeText renders the magnitude of the traces with the characters.
+func writeText(w io.Writer, traces []Trace, options Options) error {
+	const left = 7
+
+	header := options.Title != ""
+
+	for _, trace := range traces {
+		header = header || trace.Name != ""
+	}
+
+	// Reserve the rows for the header, the axis and the frequency labels.
+	rows := options.Height - 2
+
+	if header {
+		rows--
+	}
+	if rows < 2 || options.Width-left < 2 {
+		return fmt.Errorf("plot: %dx%d is too small for the text plot", options.Width, options.Height)
+	}
+
+	columns := options.Width - left
+	p := newPlot(traces, options, float64(left), 0.0, float64(columns-1), float64(rows-1))
+	o := p.options
+	grid := make([][]cell, rows)
+
+	for r := range grid {
+		grid[r] = make([]cell, columns)
+
+		for c := range grid[r] {
+			grid[r][c] = cell{char: ' '}
+		}
+	}
+
+	row := func(gain float64) int {
+		return int(math.Round(p.y(gain, o.MinGain, o.MaxGain)))
+	}
+	column := func(frequency float64) int {
+		return int(math.Round(p.x(frequency))) - left
+	}
+
+	// grid
+	step := gainStep(o.MaxGain - o.MinGain)
+	labels := map[int]string{}
+
+	for gain := math.Ceil(o.MinGain/step) * step; gain <= o.MaxGain; gain += step {
+		r := row(gain)
+		labels[r] = fmt.Sprintf("%g", gain)
+
+		for c := range grid[r] {
+			grid[r][c].char = '.'
+		}
+	}
+
+	ticks := frequencyTicks(o.MinFrequency, o.MaxFrequency)
+
+	for _, tick := range ticks {
+		if c := column(tick.frequency); tick.major && c >= 0 && c < columns {
+			for r := range grid {
+				grid[r][c].char = ':'
+			}
+		}
+	}
+
+	// curves
+	for i := range traces {
+		marker := cell{
+			char:  textMarkers[i%len(textMarkers)],
+			color: ansiColors[i%len(ansiColors)],
+		}
+		previous := -1
+
+		for n := 0; n < columns && n < len(p.frequencies); n++ {
+			gain := p.gains[i][n]
+
+			if math.IsNaN(gain) {
+				previous = -1
+
+				continue
+			}
+
+			r := row(gain)
+			from, to := r, r
+
+			// Connect the steep part of the curve vertically.
+			if previous >= 0 && previous != r {
+				if previous < r {
+					from = previous + 1
+				} else {
+					to = previous - 1
+				}
+			}
+			for k := from; k <= to; k++ {
+				grid[k][n] = marker
+			}
+
+			previous = r
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	colored := options.Format == ANSI
+
+	if header {
+		b.WriteString(strings.Repeat(" ", left))
+		b.WriteString(o.Title)
+
+		for i, trace := range traces {
+			if trace.Name == "" {
+				continue
+			}
+
+			b.WriteString("  ")
+			writeCell(b, cell{char: textMarkers[i%len(textMarkers)], color: ansiColors[i%len(ansiColors)]}, colored)
+			b.WriteString(" " + trace.Name)
+		}
+
+		b.WriteByte('\n')
+	}
+	for r := range grid {
+		fmt.Fprintf(b, "%5s |", labels[r])
+
+		for _, c := range grid[r] {
+			writeCell(b, c, colored)
+		}
+
+		b.WriteByte('\n')
+	}
+
+	// frequency axis
+	axis := []byte(strings.Repeat("-", columns))
+	line := []byte(strings.Repeat(" ", columns))
+	end := -1
+
+	for _, tick := range ticks {
+		c := column(tick.frequency)
+
+		if !tick.major || c < 0 || c >= columns {
+			continue
+		}
+
+		axis[c] = '+'
+
+		label := formatFrequency(tick.frequency)
+		start := c - len(label)/2
+
+		if start < 0 {
+			start = 0
+		}
+		if start+len(label) > columns {
+			start = columns - len(label)
+		}
+		if start <= end {
+			continue
+		}
+
+		copy(line[start:], label)
+		end = start + len(label)
+	}
+
+	fmt.Fprintf(b, "%5s +%s\n", "dB", axis)
+	fmt.Fprintf(b, "%5s  %s\n", "Hz", strings.TrimRight(string(line), " "))
+
+	return b.Flush()
+}
+
+// writeCell writes the character with the color escape sequence when colored is true.
+func writeCell(b *bufio.Writer, c cell, colored bool) {
+	if colored && c.color != 0 {
+		fmt.Fprintf(b, "\x1b[%dm%c\x1b[0m", c.color, c.char)
+
+		return
+	}
+
+	b.WriteByte(c.char)
+}
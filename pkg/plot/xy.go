@@ -0,0 +1,78 @@
+package plot
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// XY is the point of the curve.
+type XY struct {
+	X float64
+	Y float64
+}
+
+// XYs is the sampled curve. It implements the plotter.XYer interface of gonum.org/v1/plot, so it can be passed to plotter.NewLine and plotter.NewScatter without importing this package into gonum or vice versa.
+// The integration/gonum module converts it to plotter.XYs where the concrete type is needed.
+//
+// NOTE: Set plot.LogScale{} to the X axis scale and plot.LogTicks{} to the X axis tick marker of the gonum plot to show the frequency on the logarithmic axis.
+type XYs []XY
+
+// Len returns the number of the points.
+func (xys XYs) Len() int {
+	return len(xys)
+}
+
+// XY returns the point at the index.
+func (xys XYs) XY(i int) (float64, float64) {
+	return xys[i].X, xys[i].Y
+}
+
+// Frequencies returns the logarithmically spaced frequencies including both ends.
+//
+// Parameters:
+//
+//     - min ... Lowest frequency in Hz. e.g. 20.0
+//     - max ... Highest frequency in Hz. e.g. 20000.0
+//     - count ... Number of the frequencies. e.g. 200
+func Frequencies(min, max float64, count int) []float64 {
+	if count < 2 {
+		return []float64{min}
+	}
+
+	frequencies := make([]float64, count)
+	ratio := math.Pow(max/min, 1.0/float64(count-1))
+
+	for i := range frequencies {
+		frequencies[i] = min * math.Pow(ratio, float64(i))
+	}
+
+	return frequencies
+}
+
+// MagnitudeXYs returns the gain in dB of the responder at the frequencies in Hz.
+func MagnitudeXYs(responder Responder, frequencies []float64) XYs {
+	xys := make(XYs, len(frequencies))
+
+	for i, frequency := range frequencies {
+		xys[i] = XY{
+			X: frequency,
+			Y: 20.0 * math.Log10(cmplx.Abs(responder.FrequencyResponse(frequency))),
+		}
+	}
+
+	return xys
+}
+
+// PhaseXYs returns the phase in degrees between -180 and 180 of the responder at the frequencies in Hz.
+func PhaseXYs(responder Responder, frequencies []float64) XYs {
+	xys := make(XYs, len(frequencies))
+
+	for i, frequency := range frequencies {
+		xys[i] = XY{
+			X: frequency,
+			Y: cmplx.Phase(responder.FrequencyResponse(frequency)) * 180.0 / math.Pi,
+		}
+	}
+
+	return xys
+}
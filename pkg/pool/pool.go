@@ -0,0 +1,109 @@
+// Package pool reuses the buffers of the block processing and the conversion between the bytes and the samples, so the
+// server which runs hundreds of chains does not allocate the new buffers for every block and every message.
+//
+// The buffers are grouped by the capacity in the powers of 2. Get returns the buffer of the smallest group which holds
+// the length, and Put returns it to the group of its capacity. The buffers larger than MaxSize are not pooled.
+package pool
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// MaxSize is the length of the largest buffer which is pooled.
+const MaxSize = 1 << 24
+
+// minShift is the capacity of the smallest group, 64 elements.
+const minShift = 6
+
+// classes is the number of the groups.
+const classes = 24 - minShift + 1
+
+var (
+	float64s [classes]sync.Pool
+	bytes    [classes]sync.Pool
+
+	// The pools hold the pointers to the slices, because putting the slice itself to the pool allocates its header.
+	// The pointers whose slices are taken are kept here to be used again.
+	float64Headers sync.Pool
+	byteHeaders    sync.Pool
+)
+
+// class returns the group of the buffer which holds the length.
+func class(length int) int {
+	if length <= 1<<minShift {
+		return 0
+	}
+
+	return bits.Len(uint(length-1)) - minShift
+}
+
+// GetFloat64s returns the buffer of the length. The values are not cleared. Call PutFloat64s when it is not used anymore.
+func GetFloat64s(length int) []float64 {
+	if length > MaxSize {
+		return make([]float64, length)
+	}
+
+	c := class(length)
+
+	if h, ok := float64s[c].Get().(*[]float64); ok {
+		b := *h
+		*h = nil
+		float64Headers.Put(h)
+
+		return b[:length]
+	}
+
+	return make([]float64, length, 1<<(c+minShift))
+}
+
+// PutFloat64s returns the buffer to the pool. The buffer must not be used after the call.
+func PutFloat64s(b []float64) {
+	if cap(b) > MaxSize || cap(b) < 1<<minShift || cap(b)&(cap(b)-1) != 0 {
+		return
+	}
+
+	h, ok := float64Headers.Get().(*[]float64)
+
+	if !ok {
+		h = new([]float64)
+	}
+
+	*h = b[:0]
+	float64s[class(cap(b))].Put(h)
+}
+
+// GetBytes returns the buffer of the length. The bytes are not cleared. Call PutBytes when it is not used anymore.
+func GetBytes(length int) []byte {
+	if length > MaxSize {
+		return make([]byte, length)
+	}
+
+	c := class(length)
+
+	if h, ok := bytes[c].Get().(*[]byte); ok {
+		b := *h
+		*h = nil
+		byteHeaders.Put(h)
+
+		return b[:length]
+	}
+
+	return make([]byte, length, 1<<(c+minShift))
+}
+
+// PutBytes returns the buffer to the pool. The buffer must not be used after the call.
+func PutBytes(b []byte) {
+	if cap(b) > MaxSize || cap(b) < 1<<minShift || cap(b)&(cap(b)-1) != 0 {
+		return
+	}
+
+	h, ok := byteHeaders.Get().(*[]byte)
+
+	if !ok {
+		h = new([]byte)
+	}
+
+	*h = b[:0]
+	bytes[class(cap(b))].Put(h)
+}
@@ -0,0 +1,165 @@
+package ring
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is the statistics of the bridge.
+type Stats struct {
+	// Underruns is the number of the callbacks which did not find enough samples, so the rest was filled with the silence.
+	Underruns int64
+
+	// Overruns is the number of the writes by TryWrite which dropped the samples because the buffer was full.
+	Overruns int64
+
+	// Buffered is the number of the frames waiting for the callback.
+	Buffered int
+}
+
+// Bridge passes the interleaved frames from the producer to the audio callback through the ring buffer and processes
+// them in the callback, so the changes of the equalizer are heard with the latency of the driver only.
+type Bridge struct {
+	// counters are accessed atomically, so they are placed first for the 64-bit alignment on the 32-bit platforms.
+	underruns int64
+	overruns  int64
+	priming   int32
+
+	buffer   *Buffer
+	channels int
+	prefill  int
+	process  func(samples []float64)
+}
+
+// NewBridge returns the bridge.
+//
+// Parameters:
+//
+//     - channels ... Number of the interleaved channels. e.g. 2
+//     - frames ... Number of the frames which the ring buffer holds. e.g. 8192
+//     - prefill ... Number of the frames buffered before the callback starts to play, after the start and after each underrun. e.g. 2048
+//     - process ... Function which processes the interleaved samples in place in the callback, e.g. Pipeline.Process. nil passes the samples through.
+//
+// NOTE: process runs on the audio callback, so it must not block. It must not allocate to avoid the pause of the garbage collector.
+func NewBridge(channels, frames, prefill int, process func(samples []float64)) *Bridge {
+	if channels < 1 {
+		channels = 1
+	}
+	if prefill > frames {
+		prefill = frames
+	}
+
+	return &Bridge{
+		priming:  1,
+		buffer:   NewBuffer(frames * channels),
+		channels: channels,
+		prefill:  prefill,
+		process:  process,
+	}
+}
+
+// Write copies the interleaved samples to the ring buffer and waits while it is full. It returns the error of the ctx when
+// it is canceled. The incomplete frame at the end is not written. Only the producer calls it.
+func (b *Bridge) Write(ctx context.Context, samples []float64) error {
+	samples = samples[:len(samples)-len(samples)%b.channels]
+
+	for len(samples) > 0 {
+		n := b.write(samples)
+		samples = samples[n:]
+
+		if len(samples) == 0 {
+			break
+		}
+
+		// The callback does not signal, so poll until it consumes the frames.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	return nil
+}
+
+// TryWrite copies the interleaved frames as many as the ring buffer can hold and returns the number of the copied samples.
+// The rest is dropped and counted as the overrun. Only the producer calls it.
+func (b *Bridge) TryWrite(samples []float64) int {
+	samples = samples[:len(samples)-len(samples)%b.channels]
+	n := b.write(samples)
+
+	if n < len(samples) {
+		atomic.AddInt64(&b.overruns, 1)
+	}
+
+	return n
+}
+
+// write copies the whole frames which fit in the ring buffer.
+func (b *Bridge) write(samples []float64) int {
+	free := b.buffer.Cap() - b.buffer.Len()
+	free -= free % b.channels
+
+	if len(samples) > free {
+		samples = samples[:free]
+	}
+
+	return b.buffer.Write(samples)
+}
+
+// Callback fills the interleaved output with the processed frames. The frames which have not arrived yet are filled
+// with the silence and counted as the underrun. Call it from the audio callback. Only one goroutine calls it.
+func (b *Bridge) Callback(output []float64) {
+	if atomic.LoadInt32(&b.priming) == 1 {
+		if b.buffer.Len() < b.prefill*b.channels {
+			silence(output)
+
+			return
+		}
+
+		atomic.StoreInt32(&b.priming, 0)
+	}
+
+	n := b.buffer.Read(output)
+
+	if n < len(output) {
+		silence(output[n:])
+		atomic.AddInt64(&b.underruns, 1)
+		atomic.StoreInt32(&b.priming, 1)
+	}
+
+	// The silence is processed as well, so the tail of the filters decays instead of stopping abruptly.
+	if b.process != nil {
+		b.process(output)
+	}
+}
+
+// Stats returns the statistics. It is safe to call from any goroutine.
+func (b *Bridge) Stats() Stats {
+	return Stats{
+		Underruns: atomic.LoadInt64(&b.underruns),
+		Overruns:  atomic.LoadInt64(&b.overruns),
+		Buffered:  b.buffer.Len() / b.channels,
+	}
+}
+
+// Reset discards the buffered frames and waits for the prefill again, e.g. after seeking. The stats are kept, so call
+// ResetStats to clear them. Call it from the audio callback or while the callback is stopped.
+func (b *Bridge) Reset() {
+	b.buffer.Reset()
+	atomic.StoreInt32(&b.priming, 1)
+}
+
+// ResetStats clears the underruns and the overruns. It is safe to call from any goroutine.
+func (b *Bridge) ResetStats() {
+	atomic.StoreInt64(&b.underruns, 0)
+	atomic.StoreInt64(&b.overruns, 0)
+}
+
+// silence fills the samples with 0.
+func silence(samples []float64) {
+	for i := range samples {
+		samples[i] = 0.0
+	}
+}
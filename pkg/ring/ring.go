@@ -0,0 +1,83 @@
+// Package ring connects the goroutine which produces the samples, e.g. the decoder, with the real-time audio callback
+// which runs the equalizer, so each audio integration does not implement its own buffer.
+//
+// The buffer is lock-free for one producer and one consumer. The consumer never blocks, allocates or takes the lock,
+// so it is safe to call from the callback of the audio driver.
+package ring
+
+import "sync/atomic"
+
+// Buffer is the lock-free ring buffer of the samples for one producer goroutine and one consumer goroutine.
+type Buffer struct {
+	// read and written are the total numbers of the samples read and written. They are accessed atomically, so they are
+	// placed first for the 64-bit alignment on the 32-bit platforms.
+	read    uint64
+	written uint64
+
+	samples []float64
+	mask    uint64
+}
+
+// NewBuffer returns the buffer which holds at least the given number of the samples. The capacity is rounded up to the power of 2.
+func NewBuffer(size int) *Buffer {
+	capacity := 1
+
+	for capacity < size {
+		capacity *= 2
+	}
+
+	return &Buffer{
+		samples: make([]float64, capacity),
+		mask:    uint64(capacity - 1),
+	}
+}
+
+// Cap returns the number of the samples which the buffer holds.
+func (b *Buffer) Cap() int {
+	return len(b.samples)
+}
+
+// Len returns the number of the samples which can be read.
+func (b *Buffer) Len() int {
+	return int(atomic.LoadUint64(&b.written) - atomic.LoadUint64(&b.read))
+}
+
+// Write copies the samples as many as the buffer can hold and returns the number of the copied samples. Only the producer calls it.
+func (b *Buffer) Write(samples []float64) int {
+	written := atomic.LoadUint64(&b.written)
+	free := len(b.samples) - int(written-atomic.LoadUint64(&b.read))
+
+	if len(samples) > free {
+		samples = samples[:free]
+	}
+	for i, sample := range samples {
+		b.samples[(written+uint64(i))&b.mask] = sample
+	}
+
+	// The samples are visible to the consumer before the new count.
+	atomic.StoreUint64(&b.written, written+uint64(len(samples)))
+
+	return len(samples)
+}
+
+// Read copies the samples as many as available and returns the number of the copied samples. Only the consumer calls it.
+func (b *Buffer) Read(samples []float64) int {
+	read := atomic.LoadUint64(&b.read)
+	available := int(atomic.LoadUint64(&b.written) - read)
+
+	if len(samples) > available {
+		samples = samples[:available]
+	}
+	for i := range samples {
+		samples[i] = b.samples[(read+uint64(i))&b.mask]
+	}
+
+	atomic.StoreUint64(&b.read, read+uint64(len(samples)))
+
+	return len(samples)
+}
+
+// Reset discards the samples which have not been read. Only the consumer calls it.
+func (b *Buffer) Reset() {
+	atomic.StoreUint64(&b.read, atomic.LoadUint64(&b.written))
+}
@@ -0,0 +1,191 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBufferWrapAround(t *testing.T) {
+	b := NewBuffer(5)
+
+	if b.Cap() != 8 {
+		t.Fatalf("capacity is %d, want 8", b.Cap())
+	}
+
+	// The writes and the reads of 3 samples cross the end of the 8 samples many times.
+	next := 0.0
+	read := make([]float64, 3)
+
+	for i := 0; i < 100; i++ {
+		if n := b.Write([]float64{float64(3 * i), float64(3*i + 1), float64(3*i + 2)}); n != 3 {
+			t.Fatalf("write %d copied %d samples, want 3", i, n)
+		}
+		if n := b.Read(read); n != 3 {
+			t.Fatalf("read %d copied %d samples, want 3", i, n)
+		}
+		for _, value := range read {
+			if value != next {
+				t.Fatalf("read %v, want %v", value, next)
+			}
+
+			next++
+		}
+	}
+
+	// The full buffer copies only the free space, and the empty buffer reads nothing.
+	if n := b.Write(make([]float64, 10)); n != 8 {
+		t.Errorf("write to the empty buffer copied %d samples, want 8", n)
+	}
+	if n := b.Write([]float64{1.0}); n != 0 {
+		t.Errorf("write to the full buffer copied %d samples, want 0", n)
+	}
+
+	b.Reset()
+
+	if b.Len() != 0 {
+		t.Errorf("length after Reset is %d, want 0", b.Len())
+	}
+	if n := b.Read(read); n != 0 {
+		t.Errorf("read after Reset copied %d samples, want 0", n)
+	}
+}
+
+func TestBridgeXruns(t *testing.T) {
+	b := NewBridge(2, 4, 2, nil)
+	output := make([]float64, 4)
+
+	// The callback plays the silence until the prefill arrives, which is not the underrun.
+	b.Callback(output)
+
+	if stats := b.Stats(); stats.Underruns != 0 {
+		t.Errorf("underruns while priming are %d, want 0", stats.Underruns)
+	}
+	if n := b.TryWrite([]float64{1.0, 2.0, 3.0, 4.0, 5.0}); n != 4 {
+		t.Errorf("TryWrite copied %d samples, want the 2 whole frames", n)
+	}
+
+	b.Callback(output)
+
+	for i, want := range []float64{1.0, 2.0, 3.0, 4.0} {
+		if output[i] != want {
+			t.Errorf("sample %d is %v, want %v", i, output[i], want)
+		}
+	}
+
+	// The empty buffer is the underrun, and the rest is filled with the silence.
+	b.TryWrite([]float64{5.0, 6.0})
+	b.Callback(output)
+
+	if output[0] != 5.0 || output[2] != 0.0 || output[3] != 0.0 {
+		t.Errorf("output after the underrun is %v", output)
+	}
+	if stats := b.Stats(); stats.Underruns != 1 {
+		t.Errorf("underruns are %d, want 1", stats.Underruns)
+	}
+
+	// The frames which do not fit are dropped and counted as the overrun.
+	if n := b.TryWrite(make([]float64, 10)); n != 8 {
+		t.Errorf("TryWrite copied %d samples, want 8", n)
+	}
+	if stats := b.Stats(); stats.Overruns != 1 || stats.Buffered != 4 {
+		t.Errorf("stats are %+v, want 1 overrun and 4 frames", stats)
+	}
+
+	// Reset discards the frames and primes again, but keeps the stats.
+	b.Reset()
+	b.TryWrite([]float64{7.0, 8.0})
+	b.Callback(output)
+
+	for i, value := range output {
+		if value != 0.0 {
+			t.Errorf("sample %d while priming after Reset is %v, want 0", i, value)
+		}
+	}
+	if stats := b.Stats(); stats.Underruns != 1 || stats.Overruns != 1 || stats.Buffered != 1 {
+		t.Errorf("stats after Reset are %+v", stats)
+	}
+
+	b.ResetStats()
+
+	if stats := b.Stats(); stats.Underruns != 0 || stats.Overruns != 0 {
+		t.Errorf("stats after ResetStats are %+v", stats)
+	}
+}
+
+func TestBridgeProducerConsumer(t *testing.T) {
+	const (
+		channels = 2
+		frames   = 20000
+	)
+
+	processed := 0
+	b := NewBridge(channels, 256, 64, func(samples []float64) {
+		processed += len(samples)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	// The producer writes the odd sized chunks, so the frames cross the end of the ring buffer at every position. The
+	// frames of the prefill follow the last one, so the callback does not wait for the prefill of the tail.
+	go func() {
+		samples := make([]float64, 0, 2*channels*37)
+
+		for frame := 0; frame < frames+64; {
+			samples = samples[:0]
+
+			for i := 0; i < 37 && frame < frames+64; i++ {
+				frame++
+
+				for c := 0; c < channels; c++ {
+					samples = append(samples, float64(frame))
+				}
+			}
+			if err := b.Write(ctx, samples); err != nil {
+				done <- err
+
+				return
+			}
+		}
+
+		done <- nil
+	}()
+
+	// The consumer skips the silence of the underruns, and the frames are received in order without loss.
+	output := make([]float64, channels*32)
+	next := 1.0
+
+	for next <= frames {
+		if ctx.Err() != nil {
+			t.Fatalf("timeout at frame %v", next)
+		}
+
+		b.Callback(output)
+
+		for i := 0; i < len(output); i += channels {
+			if output[i] == 0.0 {
+				continue
+			}
+			if output[i] != next || output[i+1] != next {
+				t.Fatalf("frame is %v, want %v", output[i:i+channels], next)
+			}
+
+			next++
+		}
+	}
+
+	// The producer may wait for the space of the frames of the prefill which are not consumed.
+	cancel()
+
+	if err := <-done; err != nil && err != context.Canceled {
+		t.Fatal(err)
+	}
+	if processed%len(output) != 0 || processed < frames*channels {
+		t.Errorf("%d samples are processed", processed)
+	}
+	if stats := b.Stats(); stats.Overruns != 0 {
+		t.Errorf("stats are %+v, want no overrun", stats)
+	}
+}
@@ -0,0 +1,138 @@
+package timeseries
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Read reads the two-column text. See the package document for the format.
+func Read(r io.Reader) (Series, error) {
+	var s Series
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	header := false
+
+	for scanner.Scan() {
+		line++
+
+		// The tab at the end is kept, because it separates the empty value.
+		text := strings.Trim(scanner.Text(), " ")
+
+		if trimmed := strings.TrimSpace(text); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		fields := splitFields(text)
+
+		if len(fields) != 2 {
+			return Series{}, fmt.Errorf("timeseries: line %d has %d columns, expected time and value", line, len(fields))
+		}
+
+		t, err1 := strconv.ParseFloat(fields[0], 64)
+		v, err2 := math.NaN(), error(nil)
+
+		// The empty value is the missing value.
+		if fields[1] != "" {
+			v, err2 = strconv.ParseFloat(fields[1], 64)
+		}
+
+		if err1 != nil || err2 != nil {
+			// Skip the header which precedes the first sample.
+			if s.Len() == 0 && !header {
+				header = true
+
+				continue
+			}
+
+			return Series{}, fmt.Errorf("timeseries: line %d is not numeric: %q", line, text)
+		}
+
+		s.Times = append(s.Times, t)
+		s.Values = append(s.Values, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return Series{}, err
+	}
+
+	return s, nil
+}
+
+// ReadFile reads the two-column text file.
+func ReadFile(path string) (Series, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return Series{}, err
+	}
+
+	defer file.Close()
+
+	s, err := Read(file)
+
+	if err != nil {
+		return Series{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Write writes the times and the values separated by the delimiter, e.g. ',' for CSV and '\t' for TSV.
+func Write(w io.Writer, s Series, delimiter rune) error {
+	b := bufio.NewWriter(w)
+
+	for i, value := range s.Values {
+		b.WriteString(strconv.FormatFloat(s.Times[i], 'g', -1, 64))
+		b.WriteRune(delimiter)
+		b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+		b.WriteByte('\n')
+	}
+
+	return b.Flush()
+}
+
+// WriteFile writes the series to the file. The delimiter is the tab when the extension is .tsv, otherwise the comma.
+func WriteFile(path string, s Series) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	delimiter := ','
+
+	if strings.HasSuffix(strings.ToLower(path), ".tsv") {
+		delimiter = '\t'
+	}
+	if err := Write(file, s, delimiter); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// splitFields splits the line by the comma, the tab or the spaces.
+func splitFields(line string) []string {
+	var fields []string
+
+	switch {
+	case strings.Contains(line, ","):
+		fields = strings.Split(line, ",")
+	case strings.Contains(line, "\t"):
+		fields = strings.Split(line, "\t")
+	default:
+		return strings.Fields(line)
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	return fields
+}
@@ -0,0 +1,97 @@
+package timeseries
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		times  []float64
+		values []float64
+	}{
+		{"comma with header", "time,value\n0,1.5\n60,2.5\n", []float64{0, 60}, []float64{1.5, 2.5}},
+		{"tab", "0\t1.5\n60\t2.5\n", []float64{0, 60}, []float64{1.5, 2.5}},
+		{"spaces", "  0   1.5\n60 2.5  \n", []float64{0, 60}, []float64{1.5, 2.5}},
+		{"comma with spaces", "0 , 1.5\n60,  2.5\n", []float64{0, 60}, []float64{1.5, 2.5}},
+		{"comments and blank lines", "# sensor 1\n\ntime,value\n# the first day\n0,1.5\n\n60,2.5\n", []float64{0, 60}, []float64{1.5, 2.5}},
+		{"empty value", "time,value\n0,1.5\n60,\n120,NaN\n180,2.5\n", []float64{0, 60, 120, 180}, []float64{1.5, math.NaN(), math.NaN(), 2.5}},
+		{"empty value of tab", "0\t1.5\n60\t\n", []float64{0, 60}, []float64{1.5, math.NaN()}},
+		{"no sample", "time,value\n", nil, nil},
+	}
+
+	for _, test := range tests {
+		s, err := Read(strings.NewReader(test.text))
+
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+
+			continue
+		}
+		if s.Len() != len(test.values) || len(s.Times) != len(test.times) {
+			t.Errorf("%s: %d samples, want %d", test.name, s.Len(), len(test.values))
+
+			continue
+		}
+		for i := range test.values {
+			if s.Times[i] != test.times[i] {
+				t.Errorf("%s: time %d is %v, want %v", test.name, i, s.Times[i], test.times[i])
+			}
+			if v, want := s.Values[i], test.values[i]; v != want && !(math.IsNaN(v) && math.IsNaN(want)) {
+				t.Errorf("%s: value %d is %v, want %v", test.name, i, v, want)
+			}
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"non-numeric row after the header", "time,value\n0,1.5\n60,high\n"},
+		{"second header", "time,value\ntime,value\n0,1.5\n"},
+		{"non-numeric time", "0,1.5\nnoon,2.5\n"},
+		{"three columns", "0,1.5,2.5\n"},
+		{"one column", "0\n60\n"},
+	}
+
+	for _, test := range tests {
+		if s, err := Read(strings.NewReader(test.text)); err == nil {
+			t.Errorf("%s: read %v without the error", test.name, s)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	s := Series{
+		Times:  []float64{0, 60, 120},
+		Values: []float64{1.5, math.NaN(), -2.25},
+	}
+
+	for _, delimiter := range []rune{',', '\t', ' '} {
+		var b bytes.Buffer
+
+		if err := Write(&b, s, delimiter); err != nil {
+			t.Fatal(err)
+		}
+
+		read, err := Read(&b)
+
+		if err != nil {
+			t.Fatalf("%q: %v", delimiter, err)
+		}
+		if read.Len() != s.Len() {
+			t.Fatalf("%q: %d samples, want %d", delimiter, read.Len(), s.Len())
+		}
+		for i := range s.Values {
+			if read.Times[i] != s.Times[i] || (read.Values[i] != s.Values[i] && !math.IsNaN(s.Values[i])) {
+				t.Errorf("%q: sample %d is %v %v, want %v %v", delimiter, i, read.Times[i], read.Values[i], s.Times[i], s.Values[i])
+			}
+		}
+	}
+}
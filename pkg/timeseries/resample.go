@@ -0,0 +1,147 @@
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Interpolation represents the method to estimate the values between the samples.
+type Interpolation int
+
+// Interpolation constants are the resampling methods.
+const (
+	Linear Interpolation = iota
+
+	// Spline is the natural cubic spline. It is smoother than Linear but may overshoot around the steps.
+	Spline
+)
+
+// Resample returns the series sampled every interval seconds from the first time to the last time, so the filters can
+// process the series with the jittered or missing timestamps. The samples do not have to be sorted by the time.
+//
+// NOTE: The long gap is bridged by the interpolation, too. It returns the error when two samples have the same time.
+func (s Series) Resample(interval float64, method Interpolation) (Series, error) {
+	if !(interval > 0.0) {
+		return Series{}, fmt.Errorf("timeseries: interval %g must be positive", interval)
+	}
+	if len(s.Times) < 2 || len(s.Times) != len(s.Values) {
+		return Series{}, errors.New("timeseries: needs at least 2 samples with the times")
+	}
+
+	sorted := s.sorted()
+
+	for i := 1; i < sorted.Len(); i++ {
+		if sorted.Times[i] == sorted.Times[i-1] {
+			return Series{}, fmt.Errorf("timeseries: duplicated time %g", sorted.Times[i])
+		}
+	}
+
+	first, last := sorted.Times[0], sorted.Times[sorted.Len()-1]
+	count := int(math.Floor((last-first)/interval+1e-9)) + 1
+
+	var at func(t float64) float64
+
+	switch method {
+	case Linear:
+		at = sorted.linear
+	case Spline:
+		at = sorted.spline()
+	default:
+		return Series{}, fmt.Errorf("timeseries: unknown interpolation %d", method)
+	}
+
+	resampled := Series{
+		Times:  make([]float64, count),
+		Values: make([]float64, count),
+	}
+
+	for i := range resampled.Times {
+		t := first + float64(i)*interval
+
+		resampled.Times[i] = t
+		resampled.Values[i] = at(t)
+	}
+
+	return resampled, nil
+}
+
+// sorted returns the copy of the series sorted by the time.
+func (s Series) sorted() Series {
+	indices := make([]int, s.Len())
+
+	for i := range indices {
+		indices[i] = i
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		return s.Times[indices[i]] < s.Times[indices[j]]
+	})
+
+	sorted := Series{
+		Times:  make([]float64, s.Len()),
+		Values: make([]float64, s.Len()),
+	}
+
+	for i, index := range indices {
+		sorted.Times[i] = s.Times[index]
+		sorted.Values[i] = s.Values[index]
+	}
+
+	return sorted
+}
+
+// segment returns the index i such that Times[i] <= t <= Times[i+1].
+func (s Series) segment(t float64) int {
+	i := sort.SearchFloat64s(s.Times, t) - 1
+
+	if i < 0 {
+		return 0
+	}
+	if i > s.Len()-2 {
+		return s.Len() - 2
+	}
+
+	return i
+}
+
+// linear returns the value at the time interpolated between the neighboring samples.
+func (s Series) linear(t float64) float64 {
+	i := s.segment(t)
+	u := (t - s.Times[i]) / (s.Times[i+1] - s.Times[i])
+
+	return s.Values[i] + (s.Values[i+1]-s.Values[i])*u
+}
+
+// spline returns the natural cubic spline through the samples.
+func (s Series) spline() func(t float64) float64 {
+	n := s.Len()
+
+	// m is the second derivative at each sample, solved from the tridiagonal system with the Thomas algorithm.
+	m := make([]float64, n)
+	c := make([]float64, n)
+	d := make([]float64, n)
+
+	for i := 1; i < n-1; i++ {
+		h0 := s.Times[i] - s.Times[i-1]
+		h1 := s.Times[i+1] - s.Times[i]
+		rhs := 6.0 * ((s.Values[i+1]-s.Values[i])/h1 - (s.Values[i]-s.Values[i-1])/h0)
+		pivot := 2.0*(h0+h1) - h0*c[i-1]
+
+		c[i] = h1 / pivot
+		d[i] = (rhs - h0*d[i-1]) / pivot
+	}
+	for i := n - 2; i > 0; i-- {
+		m[i] = d[i] - c[i]*m[i+1]
+	}
+
+	return func(t float64) float64 {
+		i := s.segment(t)
+		h := s.Times[i+1] - s.Times[i]
+		a := (s.Times[i+1] - t) / h
+		b := (t - s.Times[i]) / h
+
+		return a*s.Values[i] + b*s.Values[i+1] + ((a*a*a-a)*m[i]+(b*b*b-b)*m[i+1])*h*h/6.0
+	}
+}
@@ -0,0 +1,101 @@
+// Package timeseries applies the filters to the sensor data and other time series stored in the text files.
+//
+// The file has two columns, the time in seconds and the value, separated by the comma, the tab or the spaces:
+//
+//     time,value
+//     0,12.31
+//     60,12.29
+//     120,12.35
+//
+// The blank lines and the lines starting with # are ignored. The empty value and NaN are read as NaN, the missing value. The first line is treated as the header when it is not numeric.
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// ErrIrregular is returned when the samples are not evenly spaced in time.
+var ErrIrregular = errors.New("timeseries: irregular sample interval")
+
+// Series is the values sampled at the times in seconds.
+type Series struct {
+	Times  []float64
+	Values []float64
+}
+
+// Len returns the number of the samples.
+func (s Series) Len() int {
+	return len(s.Values)
+}
+
+// Interval returns the sample interval in seconds inferred from the median of the time steps.
+// It returns ErrIrregular when any step differs from the median by more than 1%, because the filters assume the uniform sampling.
+// Resample the irregular series to the uniform interval before filtering.
+func (s Series) Interval() (float64, error) {
+	if len(s.Times) < 2 || len(s.Times) != len(s.Values) {
+		return 0.0, errors.New("timeseries: needs at least 2 samples with the times")
+	}
+
+	steps := make([]float64, len(s.Times)-1)
+
+	for i := range steps {
+		steps[i] = s.Times[i+1] - s.Times[i]
+
+		if steps[i] <= 0.0 {
+			return 0.0, fmt.Errorf("timeseries: time %g of sample %d is not increasing", s.Times[i+1], i+1)
+		}
+	}
+
+	sorted := append([]float64(nil), steps...)
+	sort.Float64s(sorted)
+
+	interval := sorted[len(sorted)/2]
+
+	for i, step := range steps {
+		if math.Abs(step-interval) > 0.01*interval {
+			return interval, fmt.Errorf("%w: step %g at time %g, expected %g", ErrIrregular, step, s.Times[i], interval)
+		}
+	}
+
+	return interval, nil
+}
+
+// SampleRate returns the sample rate in Hz, which is the reciprocal of the interval. Pass it to the filter constructors.
+func (s Series) SampleRate() (float64, error) {
+	interval, err := s.Interval()
+
+	if err != nil {
+		return 0.0, err
+	}
+
+	return 1.0 / interval, nil
+}
+
+// Filter returns the series whose values are processed by the processor in the order of the time. The times are copied.
+// The whole values are passed to ProcessBuffer when the processor implements it, so equalizer.GapHandler with
+// equalizer.Interpolate can see both ends of the missing values.
+//
+// NOTE: The processor must be designed at the sample rate of the series, e.g. equalizer.NewHighPass(rate, 0.005, 0.707)
+// for the values sampled every 60 seconds. Wrap it with equalizer.NewGapHandler when the values contain NaN.
+func (s Series) Filter(processor equalizer.Processor) Series {
+	filtered := Series{
+		Times:  append([]float64(nil), s.Times...),
+		Values: append([]float64(nil), s.Values...),
+	}
+
+	if p, ok := processor.(interface{ ProcessBuffer(buffer []float64) }); ok {
+		p.ProcessBuffer(filtered.Values)
+
+		return filtered
+	}
+	for i, value := range filtered.Values {
+		filtered.Values[i] = processor.Apply(value)
+	}
+
+	return filtered
+}
@@ -0,0 +1,137 @@
+package timeseries
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+func TestInterval(t *testing.T) {
+	// The jitter within 1% of the interval is accepted.
+	s := Series{
+		Times:  []float64{0, 60, 120.5, 180, 239.5, 300},
+		Values: make([]float64, 6),
+	}
+	interval, err := s.Interval()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interval != 60.0 {
+		t.Errorf("interval is %v, want 60", interval)
+	}
+
+	rate, err := s.SampleRate()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 1.0/60.0 {
+		t.Errorf("sample rate is %v Hz, want 1/60 Hz", rate)
+	}
+}
+
+func TestIntervalError(t *testing.T) {
+	tests := []struct {
+		name      string
+		times     []float64
+		irregular bool
+	}{
+		{"decreasing", []float64{0, 60, 30, 90}, false},
+		{"duplicated", []float64{0, 60, 60, 120}, false},
+		{"step above 1%", []float64{0, 60, 120, 181, 240, 300}, true},
+		{"step below 1%", []float64{0, 60, 120, 179, 240, 300}, true},
+		{"gap", []float64{0, 60, 120, 300, 360, 420}, true},
+		{"one sample", []float64{0}, false},
+	}
+
+	for _, test := range tests {
+		s := Series{Times: test.times, Values: make([]float64, len(test.times))}
+		_, err := s.Interval()
+
+		if err == nil {
+			t.Errorf("%s: no error", test.name)
+
+			continue
+		}
+		if errors.Is(err, ErrIrregular) != test.irregular {
+			t.Errorf("%s: error is %v", test.name, err)
+		}
+	}
+}
+
+// sensorSeries returns the values sampled every minute for a day. The value is the slow daily cycle plus the fast
+// oscillation of the period of 4 minutes.
+func sensorSeries() (s Series, slow []float64) {
+	for i := 0; i < 1440; i++ {
+		t := 60.0 * float64(i)
+		daily := 10.0 + 2.0*math.Sin(2.0*math.Pi*t/86400.0)
+
+		s.Times = append(s.Times, t)
+		s.Values = append(s.Values, daily+math.Sin(2.0*math.Pi*t/240.0))
+		slow = append(slow, daily)
+	}
+
+	return s, slow
+}
+
+func TestFilter(t *testing.T) {
+	s, slow := sensorSeries()
+	rate, err := s.SampleRate()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The low-pass at 1 mHz removes the oscillation of about 4.2 mHz and keeps the daily cycle.
+	filtered := s.Filter(equalizer.NewLowPass(rate, 0.001, 0.707))
+
+	if filtered.Len() != s.Len() || filtered.Times[100] != s.Times[100] {
+		t.Fatalf("filtered series has %d samples", filtered.Len())
+	}
+
+	// The filter starts from 0, so the first hour is skipped.
+	for i := 60; i < filtered.Len(); i++ {
+		if d := math.Abs(filtered.Values[i] - slow[i]); d > 0.15 {
+			t.Fatalf("sample %d is %v, want %v", i, filtered.Values[i], slow[i])
+		}
+	}
+
+	// The processor without ProcessBuffer is applied sample by sample.
+	gain := s.Filter(equalizer.NewGain(rate, -6.0206))
+
+	if d := math.Abs(gain.Values[10] - s.Values[10]/2.0); d > 1e-4 {
+		t.Errorf("halved sample is %v, want %v", gain.Values[10], s.Values[10]/2.0)
+	}
+
+	// The original series is not changed.
+	if s.Values[100] != slow[100]+math.Sin(2.0*math.Pi*6000.0/240.0) {
+		t.Errorf("original sample is changed to %v", s.Values[100])
+	}
+}
+
+func TestFilterGap(t *testing.T) {
+	s, slow := sensorSeries()
+	rate, _ := s.SampleRate()
+
+	for i := 700; i < 710; i++ {
+		s.Values[i] = math.NaN()
+	}
+
+	// The missing values are interpolated, so NaN does not poison the state of the filter.
+	filtered := s.Filter(equalizer.NewGapHandler(equalizer.NewLowPass(rate, 0.001, 0.707), equalizer.Interpolate))
+
+	for i := 60; i < filtered.Len(); i++ {
+		limit := 0.15
+
+		// The straight line across the gap misses the fast oscillation, which the filter passes partially.
+		if i >= 700 && i < 800 {
+			limit = 1.0
+		}
+		if d := math.Abs(filtered.Values[i] - slow[i]); !(d < limit) {
+			t.Fatalf("sample %d is %v, want %v", i, filtered.Values[i], slow[i])
+		}
+	}
+}
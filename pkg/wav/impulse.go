@@ -0,0 +1,76 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+)
+
+// NewConvolvers returns the convolver for each channel of the impulse response stored in the WAV.
+//
+// Parameters:
+//
+//     - r ... WAV stream of the impulse response.
+//     - sampleRate ... sample rate in Hz. It must match the sample rate of the WAV.
+//     - blockSize ... Block size in samples. e.g. 512
+func NewConvolvers(r io.Reader, sampleRate float64, blockSize int) ([]*equalizer.Convolver, error) {
+	reader, err := NewReader(r)
+
+	if err != nil {
+		return nil, err
+	}
+	if float64(reader.Format.SampleRate) != sampleRate {
+		return nil, fmt.Errorf("wav: impulse response sample rate %d Hz does not match %g Hz", reader.Format.SampleRate, sampleRate)
+	}
+
+	channels, err := readChannels(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	convolvers := make([]*equalizer.Convolver, len(channels))
+
+	for i, channel := range channels {
+		convolvers[i] = equalizer.NewConvolver(channel, blockSize)
+	}
+
+	return convolvers, nil
+}
+
+// WriteImpulseResponse writes the impulse response to the w as the mono 32 bit float WAV, e.g. for the convolution plugins.
+func WriteImpulseResponse(w io.WriteSeeker, sampleRate float64, ir []float64) error {
+	writer, err := NewWriter(w, Format{
+		SampleRate:    int(sampleRate),
+		Channels:      1,
+		BitsPerSample: 32,
+		Float:         true,
+	})
+
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(ir); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// WriteImpulseResponseFile is the same as WriteImpulseResponse but writes the impulse response to the file.
+func WriteImpulseResponseFile(path string, sampleRate float64, ir []float64) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+	if err := WriteImpulseResponse(file, sampleRate, ir); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
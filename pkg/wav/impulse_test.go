@@ -0,0 +1,61 @@
+package wav
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImpulseResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ir.wav")
+	ir := []float64{0.5, 0.25, -0.125}
+
+	if err := WriteImpulseResponseFile(path, 48000.0, ir); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	if _, err := NewConvolvers(file, 44100.0, 4); err == nil {
+		t.Error("impulse response of the different sample rate is accepted")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	convolvers, err := NewConvolvers(file, 48000.0, 4)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convolvers) != 1 {
+		t.Fatalf("%d convolvers, want 1", len(convolvers))
+	}
+
+	// The convolver outputs the impulse response after the latency of one block.
+	c := convolvers[0]
+
+	for i := 0; i < 8; i++ {
+		input := 0.0
+
+		if i == 0 {
+			input = 1.0
+		}
+
+		want := 0.0
+
+		if j := i - c.Latency(); j >= 0 && j < len(ir) {
+			want = ir[j]
+		}
+		if output := c.Apply(input); output-want > 1e-12 || want-output > 1e-12 {
+			t.Errorf("sample %d is %v, want %v", i, output, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package wav
+
+import "io"
+
+// NewRawReader returns the reader of the headerless little-endian PCM stream, e.g. the output of ffmpeg -f s16le.
+// The stream is read until EOF and the incomplete frame at the end is dropped.
+func NewRawReader(r io.Reader, format Format) (*Reader, error) {
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		Format:  format,
+		r:       r,
+		unknown: true,
+	}, nil
+}
+
+// RawWriter writes the samples as the headerless little-endian PCM stream.
+type RawWriter struct {
+	Format Format
+
+	w      io.Writer
+	buffer []byte
+}
+
+// NewRawWriter returns the writer of the headerless PCM stream. Unlike Writer, the destination does not have to be seekable.
+func NewRawWriter(w io.Writer, format Format) (*RawWriter, error) {
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+
+	return &RawWriter{
+		Format: format,
+		w:      w,
+	}, nil
+}
+
+// Write writes the interleaved samples.
+func (w *RawWriter) Write(samples []float64) error {
+	w.buffer = w.Format.encodeSamples(w.buffer, samples)
+
+	_, err := w.w.Write(w.buffer)
+
+	return err
+}
@@ -0,0 +1,181 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Reader reads the samples from the WAV stream.
+type Reader struct {
+	Format Format
+
+	r         io.Reader
+	remaining int64
+	unknown   bool
+	buffer    []byte
+}
+
+// NewReader reads the WAV header and returns the reader positioned at the first sample.
+func NewReader(r io.Reader) (*Reader, error) {
+	header := make([]byte, 12)
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("%w: missing RIFF/WAVE header", ErrInvalidFormat)
+	}
+
+	var format Format
+	var hasFormat bool
+
+	for {
+		chunk := make([]byte, 8)
+
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, err
+		}
+
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			body := make([]byte, size+size%2)
+
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, err
+			}
+
+			f, err := parseFormat(body[:size])
+
+			if err != nil {
+				return nil, err
+			}
+
+			format = f
+			hasFormat = true
+		case "data":
+			if !hasFormat {
+				return nil, fmt.Errorf("%w: data chunk before fmt chunk", ErrInvalidFormat)
+			}
+
+			return &Reader{
+				Format:    format,
+				r:         r,
+				remaining: size,
+				// The streaming encoders write 0 or 0xFFFFFFFF when the size is unknown.
+				unknown: size == 0 || size == 0xFFFFFFFF,
+			}, nil
+		default:
+			if _, err := io.CopyN(ioutil.Discard, r, size+size%2); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// parseFormat parses the body of the fmt chunk.
+func parseFormat(body []byte) (Format, error) {
+	if len(body) < 16 {
+		return Format{}, fmt.Errorf("%w: fmt chunk is too short", ErrInvalidFormat)
+	}
+
+	tag := binary.LittleEndian.Uint16(body[0:2])
+
+	if tag == formatExtensible {
+		if len(body) < 26 {
+			return Format{}, fmt.Errorf("%w: extensible fmt chunk is too short", ErrInvalidFormat)
+		}
+
+		// The first 2 bytes of the sub format GUID are the format tag.
+		tag = binary.LittleEndian.Uint16(body[24:26])
+	}
+	if tag != formatPCM && tag != formatFloat {
+		return Format{}, fmt.Errorf("%w: unsupported format tag 0x%04x", ErrInvalidFormat, tag)
+	}
+
+	f := Format{
+		Channels:      int(binary.LittleEndian.Uint16(body[2:4])),
+		SampleRate:    int(binary.LittleEndian.Uint32(body[4:8])),
+		BitsPerSample: int(binary.LittleEndian.Uint16(body[14:16])),
+		Float:         tag == formatFloat,
+	}
+
+	return f, f.validate()
+}
+
+// Frames returns the number of the frames in the data chunk. It returns -1 when the size is unknown.
+func (r *Reader) Frames() int64 {
+	if r.unknown {
+		return -1
+	}
+
+	return r.remaining / int64(r.Format.bytesPerSample()*r.Format.Channels)
+}
+
+// Skip discards the frames from the current position. The stream is seeked instead of read when it implements io.Seeker.
+func (r *Reader) Skip(frames int64) error {
+	size := frames * int64(r.Format.bytesPerSample()*r.Format.Channels)
+
+	if !r.unknown && size > r.remaining {
+		return io.ErrUnexpectedEOF
+	}
+	if seeker, ok := r.r.(io.Seeker); ok {
+		if _, err := seeker.Seek(size, io.SeekCurrent); err != nil {
+			return err
+		}
+	} else if _, err := io.CopyN(ioutil.Discard, r.r, size); err != nil {
+		return err
+	}
+
+	r.remaining -= size
+
+	return nil
+}
+
+// Read reads the interleaved samples into the samples and returns the number of the samples read.
+// The number is the multiple of the channels. It returns io.EOF at the end of the data.
+func (r *Reader) Read(samples []float64) (int, error) {
+	frameSize := r.Format.bytesPerSample() * r.Format.Channels
+	frames := len(samples) / r.Format.Channels
+
+	if !r.unknown && int64(frames*frameSize) > r.remaining {
+		frames = int(r.remaining / int64(frameSize))
+	}
+	if frames == 0 {
+		if len(samples) < r.Format.Channels {
+			return 0, nil
+		}
+
+		return 0, io.EOF
+	}
+	if cap(r.buffer) < frames*frameSize {
+		r.buffer = make([]byte, frames*frameSize)
+	}
+
+	buffer := r.buffer[:frames*frameSize]
+	n, err := io.ReadFull(r.r, buffer)
+
+	// Drop the incomplete frame at the end of the stream.
+	n -= n % frameSize
+
+	if err == io.ErrUnexpectedEOF || (err == io.EOF && r.unknown) {
+		err = nil
+
+		if n == 0 {
+			err = io.EOF
+		}
+	}
+
+	r.remaining -= int64(n)
+	size := r.Format.bytesPerSample()
+
+	for i := 0; i < n/size; i++ {
+		samples[i] = r.Format.decode(buffer[i*size : (i+1)*size])
+	}
+
+	return n / size, err
+}
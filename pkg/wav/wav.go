@@ -0,0 +1,187 @@
+// Package wav provides the reader and the writer of the WAV audio file and the headerless PCM stream, and loads the
+// impulse response of the convolver of the equalizer package from the WAV.
+//
+// This package supports the following sample formats:
+//
+//     - 8, 16, 24 and 32 bit integer PCM
+//     - 32 and 64 bit IEEE float
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moutend/go-equalizer/pkg/pcm"
+	"github.com/moutend/go-equalizer/pkg/pool"
+)
+
+// WAV format tags.
+const (
+	formatPCM        = 0x0001
+	formatFloat      = 0x0003
+	formatExtensible = 0xFFFE
+)
+
+// ErrInvalidFormat is returned when the input is not the supported WAV file.
+var ErrInvalidFormat = errors.New("wav: invalid format")
+
+// Format describes the sample format of the WAV file.
+type Format struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+
+	// Float is true when the samples are IEEE float, otherwise the samples are integer PCM.
+	Float bool
+}
+
+// bytesPerSample returns the size of one sample in bytes.
+func (f Format) bytesPerSample() int {
+	return f.BitsPerSample / 8
+}
+
+// validate returns the error when the format is not supported.
+func (f Format) validate() error {
+	if f.SampleRate <= 0 || f.Channels <= 0 {
+		return fmt.Errorf("%w: sample rate %d, channels %d", ErrInvalidFormat, f.SampleRate, f.Channels)
+	}
+	if f.Float && f.BitsPerSample != 32 && f.BitsPerSample != 64 {
+		return fmt.Errorf("%w: %d bit float", ErrInvalidFormat, f.BitsPerSample)
+	}
+	if !f.Float && f.BitsPerSample != 8 && f.BitsPerSample != 16 && f.BitsPerSample != 24 && f.BitsPerSample != 32 {
+		return fmt.Errorf("%w: %d bit integer", ErrInvalidFormat, f.BitsPerSample)
+	}
+
+	return nil
+}
+
+// decode converts the bytes of one sample to the value between -1.0 and 1.0.
+func (f Format) decode(b []byte) float64 {
+	return f.pcm().DecodeSample(b)
+}
+
+// encode converts the value to the bytes of one sample. The integer samples are clipped between -1.0 and 1.0.
+func (f Format) encode(b []byte, value float64) {
+	f.pcm().EncodeSample(b, value, pcm.Clip)
+}
+
+// pcm returns the little endian sample format.
+func (f Format) pcm() pcm.Format {
+	return pcm.Format{
+		BitsPerSample: f.BitsPerSample,
+		Float:         f.Float,
+	}
+}
+
+// encodeSamples encodes the samples into the buffer and returns it. The buffer is reallocated when it is too small.
+func (f Format) encodeSamples(buffer []byte, samples []float64) []byte {
+	size := f.bytesPerSample()
+
+	if cap(buffer) < len(samples)*size {
+		buffer = make([]byte, len(samples)*size)
+	}
+
+	buffer = buffer[:len(samples)*size]
+
+	for i, sample := range samples {
+		f.encode(buffer[i*size:(i+1)*size], sample)
+	}
+
+	return buffer
+}
+
+// ReadFile reads the WAV file and returns the format and the samples of each channel.
+func ReadFile(path string) (Format, [][]float64, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return Format{}, nil, err
+	}
+
+	defer file.Close()
+
+	r, err := NewReader(file)
+
+	if err != nil {
+		return Format{}, nil, err
+	}
+
+	channels, err := readChannels(r)
+
+	if err != nil {
+		return Format{}, nil, err
+	}
+
+	return r.Format, channels, nil
+}
+
+// readChannels reads the rest of the samples of each channel.
+func readChannels(r *Reader) ([][]float64, error) {
+	channels := make([][]float64, r.Format.Channels)
+	buffer := pool.GetFloat64s(4096 * r.Format.Channels)
+
+	defer pool.PutFloat64s(buffer)
+
+	for {
+		n, err := r.Read(buffer)
+
+		for i := 0; i < n; i++ {
+			channels[i%r.Format.Channels] = append(channels[i%r.Format.Channels], buffer[i])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return channels, nil
+}
+
+// WriteFile writes the samples of each channel to the WAV file. The number of the channels in the format is ignored.
+func WriteFile(path string, format Format, channels [][]float64) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	format.Channels = len(channels)
+	w, err := NewWriter(file, format)
+
+	if err != nil {
+		file.Close()
+
+		return err
+	}
+
+	buffer := pool.GetFloat64s(4096 * len(channels))
+
+	defer pool.PutFloat64s(buffer)
+
+	block := make([][]float64, len(channels))
+
+	for start := 0; len(channels) > 0 && start < len(channels[0]); start += 4096 {
+		for c, channel := range channels {
+			block[c] = channel[start:]
+		}
+
+		frames := pcm.Interleave(buffer, block)
+
+		if err := w.Write(buffer[:frames*len(channels)]); err != nil {
+			file.Close()
+
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
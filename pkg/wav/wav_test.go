@@ -0,0 +1,165 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+var testFormat = Format{
+	SampleRate:    48000,
+	Channels:      2,
+	BitsPerSample: 16,
+}
+
+// endlessReader returns the same bytes forever, so the reader is read repeatedly without reaching EOF.
+type endlessReader struct{}
+
+func (endlessReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	return len(b), nil
+}
+
+// discardSeeker is io.WriteSeeker which discards the written bytes.
+type discardSeeker struct {
+	offset int64
+}
+
+func (d *discardSeeker) Write(b []byte) (int, error) {
+	d.offset += int64(len(b))
+
+	return len(b), nil
+}
+
+func (d *discardSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		d.offset = offset
+	case io.SeekCurrent:
+		d.offset += offset
+	}
+
+	return d.offset, nil
+}
+
+// newEndlessReader returns the WAV reader of the live stream which never ends.
+func newEndlessReader(tb testing.TB) *Reader {
+	header, err := StreamHeader(testFormat)
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	r, err := NewReader(io.MultiReader(bytes.NewReader(header), endlessReader{}))
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return r
+}
+
+func BenchmarkReaderRead(b *testing.B) {
+	r := newEndlessReader(b)
+	samples := make([]float64, 2048)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(samples) * testFormat.BitsPerSample / 8))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Read(samples); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriterWrite(b *testing.B) {
+	w, err := NewWriter(&discardSeeker{}, testFormat)
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	samples := make([]float64, 2048)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(samples) * testFormat.BitsPerSample / 8))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := w.Write(samples); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestReaderReadAllocs(t *testing.T) {
+	r := newEndlessReader(t)
+	samples := make([]float64, 2048)
+
+	// The first read allocates the buffer of the bytes.
+	r.Read(samples)
+
+	if n := testing.AllocsPerRun(100, func() { r.Read(samples) }); n != 0 {
+		t.Errorf("Reader.Read allocates %v times", n)
+	}
+}
+
+func TestWriterWriteAllocs(t *testing.T) {
+	w, err := NewWriter(&discardSeeker{}, testFormat)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples := make([]float64, 2048)
+
+	// The first write allocates the buffer of the bytes.
+	w.Write(samples)
+
+	if n := testing.AllocsPerRun(100, func() { w.Write(samples) }); n != 0 {
+		t.Errorf("Writer.Write allocates %v times", n)
+	}
+}
+
+func TestWriterReader(t *testing.T) {
+	var buffer bytes.Buffer
+
+	samples := []float64{0.0, 0.5, -0.5, 0.25, -1.0, 0.75}
+	w, err := NewRawWriter(&buffer, testFormat)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(samples); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRawReader(&buffer, testFormat)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := make([]float64, 8)
+	n, err := r.Read(read)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(samples) {
+		t.Fatalf("read %d samples, want %d", n, len(samples))
+	}
+	for i, sample := range samples {
+		if d := read[i] - sample; d > 1.0/32768.0 || d < -1.0/32768.0 {
+			t.Errorf("sample %d is %v, want %v", i, read[i], sample)
+		}
+	}
+	if _, err := r.Read(read); err != io.EOF {
+		t.Errorf("got %v at the end, want io.EOF", err)
+	}
+}
@@ -0,0 +1,133 @@
+package wav
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Writer writes the samples to the WAV stream.
+type Writer struct {
+	Format Format
+
+	w      io.WriteSeeker
+	size   int64
+	buffer []byte
+}
+
+// NewWriter writes the WAV header and returns the writer. Call Close after writing all samples to update the header.
+func NewWriter(w io.WriteSeeker, format Format) (*Writer, error) {
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+
+	writer := &Writer{
+		Format: format,
+		w:      w,
+	}
+
+	if _, err := w.Write(writer.header()); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// ResumeWriter returns the writer which continues the stream written by Writer after the frames, e.g. after the
+// interrupted process is restarted. The samples after the frames are overwritten, or removed when w implements
+// Truncate like *os.File. Call Close to update the header.
+func ResumeWriter(w io.WriteSeeker, format Format, frames int64) (*Writer, error) {
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+
+	writer := &Writer{
+		Format: format,
+		w:      w,
+		size:   frames * int64(format.bytesPerSample()*format.Channels),
+	}
+
+	offset := int64(len(writer.header())) + writer.size
+
+	if t, ok := w.(interface{ Truncate(size int64) error }); ok {
+		if err := t.Truncate(offset); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := w.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// StreamHeader returns the WAV header whose sizes are unknown, for the live stream which never ends, e.g. the internet radio.
+// Write the headerless samples with RawWriter after it. The readers accept 0xFFFFFFFF as the unknown size.
+func StreamHeader(format Format) ([]byte, error) {
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+
+	b := (&Writer{Format: format}).header()
+
+	binary.LittleEndian.PutUint32(b[4:8], 0xFFFFFFFF)
+	binary.LittleEndian.PutUint32(b[40:44], 0xFFFFFFFF)
+
+	return b, nil
+}
+
+// header returns the RIFF header, the fmt chunk and the data chunk header.
+func (w *Writer) header() []byte {
+	f := w.Format
+	tag := uint16(formatPCM)
+
+	if f.Float {
+		tag = formatFloat
+	}
+
+	b := make([]byte, 44)
+
+	copy(b[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(b[4:8], uint32(36+w.size+w.size%2))
+	copy(b[8:12], "WAVE")
+	copy(b[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(b[16:20], 16)
+	binary.LittleEndian.PutUint16(b[20:22], tag)
+	binary.LittleEndian.PutUint16(b[22:24], uint16(f.Channels))
+	binary.LittleEndian.PutUint32(b[24:28], uint32(f.SampleRate))
+	binary.LittleEndian.PutUint32(b[28:32], uint32(f.SampleRate*f.Channels*f.bytesPerSample()))
+	binary.LittleEndian.PutUint16(b[32:34], uint16(f.Channels*f.bytesPerSample()))
+	binary.LittleEndian.PutUint16(b[34:36], uint16(f.BitsPerSample))
+	copy(b[36:40], "data")
+	binary.LittleEndian.PutUint32(b[40:44], uint32(w.size))
+
+	return b
+}
+
+// Write writes the interleaved samples.
+func (w *Writer) Write(samples []float64) error {
+	w.buffer = w.Format.encodeSamples(w.buffer, samples)
+
+	n, err := w.w.Write(w.buffer)
+	w.size += int64(n)
+
+	return err
+}
+
+// Close updates the sizes in the header. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.size%2 == 1 {
+		if _, err := w.w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	if _, err := w.w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(w.header()); err != nil {
+		return err
+	}
+
+	_, err := w.w.Seek(0, io.SeekEnd)
+
+	return err
+}
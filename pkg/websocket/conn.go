@@ -0,0 +1,327 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moutend/go-equalizer/pkg/pool"
+)
+
+// acceptGUID is appended to the key of the client to compute the accept key. See RFC 6455 section 1.3.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// The opcodes of the frames.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// The status codes of the close frame.
+const (
+	closeNormal        = 1000
+	closeProtocolError = 1002
+	closeInvalidData   = 1007
+	closeTooBig        = 1009
+)
+
+var (
+	// ErrHandshake is returned when the request is not the valid WebSocket handshake.
+	ErrHandshake = errors.New("websocket: invalid handshake")
+
+	// ErrTooBig is returned when the message exceeds MaxMessageSize.
+	ErrTooBig = errors.New("websocket: message too big")
+
+	// errProtocol is returned when the client violates RFC 6455.
+	errProtocol = errors.New("websocket: protocol error")
+
+	// errInvalidUTF8 is returned when the text message is not valid UTF-8.
+	errInvalidUTF8 = errors.New("websocket: invalid UTF-8 in the text message")
+)
+
+// conn is the server side of the WebSocket connection. Only the features needed by Handler are implemented, i.e. no extensions and no subprotocols.
+type conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+	limit  int
+}
+
+// upgrade completes the handshake and takes over the connection of the request.
+func upgrade(w http.ResponseWriter, r *http.Request, limit int) (*conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, ErrHandshake.Error(), http.StatusBadRequest)
+
+		return nil, ErrHandshake
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+
+	if !ok {
+		http.Error(w, "websocket: connection cannot be hijacked", http.StatusInternalServerError)
+
+		return nil, errors.New("websocket: connection cannot be hijacked")
+	}
+
+	netConn, rw, err := hijacker.Hijack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+
+	fmt.Fprintf(rw.Writer, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept)
+
+	if err := rw.Writer.Flush(); err != nil {
+		netConn.Close()
+
+		return nil, err
+	}
+
+	return &conn{
+		conn:   netConn,
+		reader: rw.Reader,
+		writer: rw.Writer,
+		limit:  limit,
+	}, nil
+}
+
+// headerContains returns true when the comma separated values of the header contain the token, ignoring the case.
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReadMessage returns the next text or binary message. The ping is answered while reading, and io.EOF is returned
+// after the close handshake. The connection is closed with the status code when the client violates the protocol.
+// The payload is taken from the pool, so pass it to pool.PutBytes when it is not used anymore.
+func (c *conn) ReadMessage() (opcode byte, payload []byte, err error) {
+	opcode = 0xFF
+
+	for {
+		fin, op, data, err := c.readFrame()
+
+		if err != nil {
+			return 0, nil, c.fail(err)
+		}
+
+		switch op {
+		case opPing:
+			err := c.writeFrame(opPong, data)
+
+			pool.PutBytes(data)
+
+			if err != nil {
+				return 0, nil, err
+			}
+
+			continue
+		case opPong:
+			pool.PutBytes(data)
+
+			continue
+		case opClose:
+			code := closeNormal
+
+			if len(data) >= 2 {
+				code = int(binary.BigEndian.Uint16(data))
+			}
+
+			pool.PutBytes(data)
+			c.close(code)
+
+			return 0, nil, io.EOF
+		case opText, opBinary:
+			if opcode != 0xFF {
+				return 0, nil, c.fail(errProtocol)
+			}
+
+			opcode = op
+		case opContinuation:
+			if opcode == 0xFF {
+				return 0, nil, c.fail(errProtocol)
+			}
+		default:
+			return 0, nil, c.fail(errProtocol)
+		}
+
+		if len(payload)+len(data) > c.limit {
+			return 0, nil, c.fail(ErrTooBig)
+		}
+
+		// The message of one frame is returned without the copy.
+		if payload == nil {
+			payload = data
+		} else {
+			payload = append(payload, data...)
+			pool.PutBytes(data)
+		}
+
+		if !fin {
+			continue
+		}
+		if opcode == opText && !utf8.Valid(payload) {
+			return 0, nil, c.fail(errInvalidUTF8)
+		}
+
+		return opcode, payload, nil
+	}
+}
+
+// readFrame reads one frame and unmasks the payload.
+func (c *conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	// The extensions are not negotiated, so the reserved bits must be 0, and the client must mask every frame.
+	if header[0]&0x70 != 0 || !masked {
+		return false, 0, nil, errProtocol
+	}
+	if opcode >= opClose && (!fin || length > 125) {
+		return false, 0, nil, errProtocol
+	}
+
+	switch length {
+	case 126:
+		var extended [2]byte
+
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	if length > uint64(c.limit) {
+		return false, 0, nil, ErrTooBig
+	}
+
+	var mask [4]byte
+
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload = pool.GetBytes(int(length))
+
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		pool.PutBytes(payload)
+
+		return false, 0, nil, err
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage writes the message in one unmasked frame.
+func (c *conn) WriteMessage(opcode byte, payload []byte) error {
+	return c.writeFrame(opcode, payload)
+}
+
+func (c *conn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode, 0}
+	length := len(payload)
+
+	switch {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = append(header, byte(length>>8), byte(length))
+	default:
+		header[1] = 127
+
+		var extended [8]byte
+
+		binary.BigEndian.PutUint64(extended[:], uint64(length))
+		header = append(header, extended[:]...)
+	}
+
+	if _, err := c.writer.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.writer.Write(payload); err != nil {
+		return err
+	}
+
+	return c.writer.Flush()
+}
+
+// fail closes the connection with the status code of the error and returns the error.
+func (c *conn) fail(err error) error {
+	switch {
+	case errors.Is(err, ErrTooBig):
+		c.close(closeTooBig)
+	case errors.Is(err, errInvalidUTF8):
+		c.close(closeInvalidData)
+	case errors.Is(err, errProtocol):
+		c.close(closeProtocolError)
+	default:
+		c.conn.Close()
+	}
+
+	return err
+}
+
+// close sends the close frame with the status code and closes the connection.
+func (c *conn) close(code int) error {
+	var payload [2]byte
+
+	binary.BigEndian.PutUint16(payload[:], uint16(code))
+	c.writeFrame(opClose, payload[:])
+
+	return c.conn.Close()
+}
+
+// Close closes the connection normally.
+func (c *conn) Close() error {
+	return c.close(closeNormal)
+}
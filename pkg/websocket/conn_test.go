@@ -0,0 +1,198 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+)
+
+// clientFrame returns the frame sent by the client. The payload is masked unless masked is false.
+func clientFrame(fin bool, opcode byte, payload []byte, masked bool) []byte {
+	frame := []byte{opcode, byte(len(payload))}
+
+	if fin {
+		frame[0] |= 0x80
+	}
+	if !masked {
+		return append(frame, payload...)
+	}
+
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	frame[1] |= 0x80
+	frame = append(frame, mask...)
+
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	return frame
+}
+
+// serverFrame returns the unmasked frame sent by the server.
+func serverFrame(opcode byte, payload []byte) []byte {
+	return append([]byte{0x80 | opcode, byte(len(payload))}, payload...)
+}
+
+// closeFrame returns the close frame of the status code sent by the server.
+func closeFrame(code int) []byte {
+	return serverFrame(opClose, []byte{byte(code >> 8), byte(code)})
+}
+
+// testConn returns the server side of the connection to which the client writes the frames. The channel receives
+// all bytes written by the server after the connection is closed.
+func testConn(limit int, frames ...[]byte) (*conn, <-chan []byte) {
+	server, client := net.Pipe()
+	received := make(chan []byte, 1)
+
+	go func() {
+		// The server closes the connection before reading all frames when the client violates the protocol.
+		client.Write(bytes.Join(frames, nil))
+	}()
+	go func() {
+		data, _ := ioutil.ReadAll(client)
+		received <- data
+	}()
+
+	return &conn{
+		conn:   server,
+		reader: bufio.NewReader(server),
+		writer: bufio.NewWriter(server),
+		limit:  limit,
+	}, received
+}
+
+func TestReadMessageFragmented(t *testing.T) {
+	// The ping between the fragments is answered, and the fragments are joined.
+	c, received := testConn(1024,
+		clientFrame(false, opText, []byte("Hel"), true),
+		clientFrame(true, opPing, []byte("ping"), true),
+		clientFrame(false, opContinuation, []byte("lo, "), true),
+		clientFrame(true, opPong, nil, true),
+		clientFrame(true, opContinuation, []byte("world"), true),
+		clientFrame(true, opBinary, []byte{0x00, 0xFF}, true),
+	)
+
+	opcode, payload, err := c.ReadMessage()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opcode != opText || string(payload) != "Hello, world" {
+		t.Errorf("got %x %q, want the text message %q", opcode, payload, "Hello, world")
+	}
+
+	opcode, payload, err = c.ReadMessage()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opcode != opBinary || !bytes.Equal(payload, []byte{0x00, 0xFF}) {
+		t.Errorf("got %x %v, want the binary message", opcode, payload)
+	}
+
+	c.Close()
+
+	want := append(serverFrame(opPong, []byte("ping")), closeFrame(closeNormal)...)
+
+	if got := <-received; !bytes.Equal(got, want) {
+		t.Errorf("server sent %v, want the pong and the close frame %v", got, want)
+	}
+}
+
+func TestReadMessageClose(t *testing.T) {
+	c, received := testConn(1024, clientFrame(true, opClose, []byte{0x03, 0xE9}, true))
+
+	if _, _, err := c.ReadMessage(); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+	if got, want := <-received, closeFrame(1001); !bytes.Equal(got, want) {
+		t.Errorf("server sent %v, want the close frame echoed %v", got, want)
+	}
+}
+
+func TestReadMessageInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		frames [][]byte
+		err    error
+		code   int
+	}{
+		{
+			name:   "oversized frame",
+			limit:  4,
+			frames: [][]byte{clientFrame(true, opBinary, []byte("12345"), true)},
+			err:    ErrTooBig,
+			code:   closeTooBig,
+		},
+		{
+			name:  "oversized fragments",
+			limit: 4,
+			frames: [][]byte{
+				clientFrame(false, opBinary, []byte("123"), true),
+				clientFrame(true, opContinuation, []byte("45"), true),
+			},
+			err:  ErrTooBig,
+			code: closeTooBig,
+		},
+		{
+			name:   "unmasked frame",
+			limit:  1024,
+			frames: [][]byte{clientFrame(true, opText, []byte("hello"), false)},
+			err:    errProtocol,
+			code:   closeProtocolError,
+		},
+		{
+			name:   "fragmented control frame",
+			limit:  1024,
+			frames: [][]byte{clientFrame(false, opPing, []byte("ping"), true)},
+			err:    errProtocol,
+			code:   closeProtocolError,
+		},
+		{
+			name:   "continuation without the first fragment",
+			limit:  1024,
+			frames: [][]byte{clientFrame(true, opContinuation, []byte("lo"), true)},
+			err:    errProtocol,
+			code:   closeProtocolError,
+		},
+		{
+			name:  "message inside the fragmented message",
+			limit: 1024,
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("Hel"), true),
+				clientFrame(true, opText, []byte("lo"), true),
+			},
+			err:  errProtocol,
+			code: closeProtocolError,
+		},
+		{
+			name:   "reserved opcode",
+			limit:  1024,
+			frames: [][]byte{clientFrame(true, 0x3, nil, true)},
+			err:    errProtocol,
+			code:   closeProtocolError,
+		},
+		{
+			name:   "invalid UTF-8",
+			limit:  1024,
+			frames: [][]byte{clientFrame(true, opText, []byte{0xC3, 0x28}, true)},
+			err:    errInvalidUTF8,
+			code:   closeInvalidData,
+		},
+	}
+	for _, test := range tests {
+		c, received := testConn(test.limit, test.frames...)
+
+		if _, _, err := c.ReadMessage(); !errors.Is(err, test.err) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.err)
+		}
+		if got, want := <-received, closeFrame(test.code); !bytes.Equal(got, want) {
+			t.Errorf("%s: server sent %v, want the close frame %v", test.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package websocket serves the equalizer over WebSocket, so the browser can send the PCM captured or decoded by the Web Audio API
+// and play the filtered PCM, e.g. for the web demos and the remote monitoring.
+//
+// The binary message is the chunk of the interleaved float32 samples in little endian, i.e. the buffer of Float32Array,
+// and the filtered chunk is returned in the same format. The text message is the JSON control message which changes the chain:
+//
+//	{"sampleRate":48000,"channels":2,"bands":[{"type":"peak","frequency":1000,"q":1,"gain":6}],"bypass":false}
+//
+// Every field is optional, and the current state is returned in the same format, or {"error":"..."} when it is invalid.
+package websocket
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/moutend/go-equalizer/internal/config"
+	"github.com/moutend/go-equalizer/pkg/equalizer"
+	"github.com/moutend/go-equalizer/pkg/pool"
+)
+
+// MaxMessageSize is the default limit of the message in bytes.
+const MaxMessageSize = 1 << 20
+
+// MaxChannels is the largest number of the channels accepted by the control message.
+const MaxChannels = 64
+
+// Handler upgrades the request to WebSocket and processes the chunks with the chain of the connection.
+// Each connection starts with the chain of the handler and changes it independently by the control messages.
+type Handler struct {
+	// SampleRate, Channels and Bands are the initial chain of each connection.
+	SampleRate float64
+	Channels   int
+	Bands      []equalizer.Band
+
+	// MaxMessageSize is the limit of the message in bytes. 0 means MaxMessageSize.
+	MaxMessageSize int
+}
+
+// NewHandler returns the handler.
+//
+// Parameters:
+//
+//     - sampleRate ... Initial sample rate in Hz. The browser can change it to the rate of AudioContext. e.g. 48000.0
+//     - channels ... Initial number of the interleaved channels. e.g. 2
+//     - bands ... Initial bands applied to every channel.
+//
+// NOTE: The origin of the request is not checked, so wrap the handler when the connection must be limited to the own pages.
+func NewHandler(sampleRate float64, channels int, bands ...equalizer.Band) *Handler {
+	return &Handler{
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Bands:      bands,
+	}
+}
+
+// control is the JSON control message. The omitted fields are not changed.
+type control struct {
+	SampleRate *float64         `json:"sampleRate,omitempty"`
+	Channels   *int             `json:"channels,omitempty"`
+	Bands      *[]config.Filter `json:"bands,omitempty"`
+	Bypass     *bool            `json:"bypass,omitempty"`
+}
+
+// state is the reply of the control message.
+type state struct {
+	SampleRate float64         `json:"sampleRate"`
+	Channels   int             `json:"channels"`
+	Bands      []config.Filter `json:"bands"`
+	Bypass     bool            `json:"bypass"`
+}
+
+// ServeHTTP processes the messages until the client closes the connection.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	limit := h.MaxMessageSize
+
+	if limit <= 0 {
+		limit = MaxMessageSize
+	}
+
+	c, err := upgrade(w, r, limit)
+
+	if err != nil {
+		return
+	}
+
+	defer c.conn.Close()
+
+	s, err := newSession(h.SampleRate, h.Channels, h.Bands)
+
+	if err != nil {
+		c.close(closeInvalidData)
+
+		return
+	}
+
+	for {
+		opcode, payload, err := c.ReadMessage()
+
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opText:
+			err = c.WriteMessage(opText, s.control(payload))
+		case opBinary:
+			// The invalid chunk is reported in the text message, so the client can fix the channels and continue.
+			if output, processErr := s.process(payload); processErr != nil {
+				err = c.WriteMessage(opText, encodeError(processErr))
+			} else {
+				err = c.WriteMessage(opBinary, output)
+				pool.PutBytes(output)
+			}
+		}
+
+		pool.PutBytes(payload)
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// session is the chain of one connection.
+type session struct {
+	sampleRate float64
+	bands      []equalizer.Band
+	bypass     bool
+	equalizers []*equalizer.ParametricEQ
+	buffer     []float64
+}
+
+func newSession(sampleRate float64, channels int, bands []equalizer.Band) (*session, error) {
+	s := &session{}
+
+	if err := s.design(sampleRate, channels, bands); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// design replaces the chain. The state variables are preserved when only the parameters of the bands are changed, so the change does not click.
+func (s *session) design(sampleRate float64, channels int, bands []equalizer.Band) error {
+	if sampleRate <= 0.0 {
+		return fmt.Errorf("websocket: invalid sample rate %v", sampleRate)
+	}
+	if channels < 1 || channels > MaxChannels {
+		return fmt.Errorf("websocket: invalid channels %d", channels)
+	}
+
+	for _, band := range bands {
+		if err := equalizer.CheckFrequency(sampleRate, band.Frequency); err != nil {
+			return err
+		}
+	}
+
+	if sampleRate == s.sampleRate && channels == len(s.equalizers) && len(bands) == len(s.bands) {
+		for _, eq := range s.equalizers {
+			for i, band := range bands {
+				eq.SetBand(i, band)
+			}
+		}
+	} else {
+		s.equalizers = make([]*equalizer.ParametricEQ, channels)
+
+		for i := range s.equalizers {
+			s.equalizers[i] = equalizer.NewParametricEQ(sampleRate, bands...)
+		}
+	}
+
+	s.sampleRate = sampleRate
+	s.bands = append([]equalizer.Band(nil), bands...)
+
+	return nil
+}
+
+// control applies the control message and returns the reply.
+func (s *session) control(payload []byte) []byte {
+	var message control
+
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return encodeError(fmt.Errorf("websocket: invalid control message: %w", err))
+	}
+
+	sampleRate := s.sampleRate
+	channels := len(s.equalizers)
+	bands := s.bands
+
+	if message.SampleRate != nil {
+		sampleRate = *message.SampleRate
+	}
+	if message.Channels != nil {
+		channels = *message.Channels
+	}
+	if message.Bands != nil {
+		b, err := config.Bands(*message.Bands)
+
+		if err != nil {
+			return encodeError(err)
+		}
+
+		bands = b
+	}
+	if err := s.design(sampleRate, channels, bands); err != nil {
+		return encodeError(err)
+	}
+	if message.Bypass != nil {
+		// The state variables are cleared when the chain is enabled again, because they are stale.
+		if s.bypass && !*message.Bypass {
+			for _, eq := range s.equalizers {
+				eq.Reset()
+			}
+		}
+
+		s.bypass = *message.Bypass
+	}
+
+	reply, _ := json.Marshal(state{
+		SampleRate: s.sampleRate,
+		Channels:   len(s.equalizers),
+		Bands:      config.Filters(s.bands),
+		Bypass:     s.bypass,
+	})
+
+	return reply
+}
+
+// process filters the chunk of the interleaved float32 samples and returns the filtered chunk taken from the pool.
+func (s *session) process(payload []byte) ([]byte, error) {
+	channels := len(s.equalizers)
+
+	if len(payload)%(4*channels) != 0 {
+		return nil, fmt.Errorf("websocket: %d bytes are not the whole frames of %d channels", len(payload), channels)
+	}
+
+	output := pool.GetBytes(len(payload))
+	frames := len(payload) / (4 * channels)
+
+	if cap(s.buffer) < frames {
+		s.buffer = make([]float64, frames)
+	}
+
+	buffer := s.buffer[:frames]
+
+	for c, eq := range s.equalizers {
+		for i := range buffer {
+			offset := 4 * (i*channels + c)
+			buffer[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(payload[offset:])))
+		}
+		if !s.bypass {
+			eq.ProcessBuffer(buffer)
+		}
+
+		for i := range buffer {
+			offset := 4 * (i*channels + c)
+			binary.LittleEndian.PutUint32(output[offset:], math.Float32bits(float32(buffer[i])))
+		}
+	}
+
+	return output, nil
+}
+
+func encodeError(err error) []byte {
+	reply, _ := json.Marshal(map[string]string{"error": err.Error()})
+
+	return reply
+}